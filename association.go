@@ -0,0 +1,416 @@
+package pfcpgolb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// Association is a PFCP association with a peer (TS 29.244 6.2.6).
+type Association struct {
+	NodeID *NodeID
+	// Addr is the address of the peer the association was set up with
+	Addr *net.UDPAddr
+	// RecoveryTimeStamp is the start time of the peer
+	RecoveryTimeStamp time.Time
+	// Features of the peer, as last received
+	UPFunctionFeatures *UPFunctionFeatures
+	CPFunctionFeatures *CPFunctionFeatures
+	// SetUpAt is when the association was set up
+	SetUpAt time.Time
+}
+
+type AssociationEventType uint8
+
+const (
+	AssociationUp AssociationEventType = iota
+	AssociationUpdated
+	AssociationDown
+)
+
+func (t AssociationEventType) String() string {
+	switch t {
+	case AssociationUp:
+		return "up"
+	case AssociationUpdated:
+		return "updated"
+	case AssociationDown:
+		return "down"
+	default:
+		return fmt.Sprintf("AssociationEventType(%d)", uint8(t))
+	}
+}
+
+// AssociationEvent reports a change of the association with a peer.
+type AssociationEvent struct {
+	Type        AssociationEventType
+	Association Association
+	// Reason is why an association went down, nil for a release
+	Reason error
+}
+
+// ErrAssociationReplaced is the Reason of an association going down because
+// the peer set up a new one.
+var ErrAssociationReplaced = errors.New("pfcp: association set up again")
+
+// RejectedError reports a request rejected by the peer.
+type RejectedError struct {
+	MessageType MessageType
+	Cause       uint8
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("pfcp: message type %d rejected with cause %d", e.MessageType, e.Cause)
+}
+
+// AssociationManager keeps the PFCP associations of a server with its peers,
+// by Node ID. It sets them up, updates and releases them on request of the
+// local node through Setup, Update and Release, and on request of the peers
+// through the handlers added to a ServeMux by Register.
+//
+// The server NodeID and RecoveryTimeStamp identify the local node.
+type AssociationManager struct {
+	server *PfcpServer
+	// Features of the local node sent to the peers
+	UPFunctionFeatures *UPFunctionFeatures
+	CPFunctionFeatures *CPFunctionFeatures
+
+	mu           sync.RWMutex
+	associations map[string]*Association
+
+	subs subscribers[AssociationEvent]
+}
+
+func NewAssociationManager(server *PfcpServer) *AssociationManager {
+	return &AssociationManager{
+		server:       server,
+		associations: make(map[string]*Association),
+	}
+}
+
+// Subscribe has f called with the events of the associations, until the
+// returned function is called. f must not block.
+func (m *AssociationManager) Subscribe(f func(AssociationEvent)) (unsubscribe func()) {
+	return m.subs.subscribe(f)
+}
+
+// Association returns the association with the peer nodeID.
+func (m *AssociationManager) Association(nodeID *NodeID) (Association, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	assoc, ok := m.associations[nodeID.String()]
+	if !ok {
+		return Association{}, false
+	}
+	return *assoc, true
+}
+
+// Associations returns the associations set up.
+func (m *AssociationManager) Associations() []Association {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	assocs := make([]Association, 0, len(m.associations))
+	for _, assoc := range m.associations {
+		assocs = append(assocs, *assoc)
+	}
+	return assocs
+}
+
+// AssociationByAddr returns the association set up with the peer at addr.
+func (m *AssociationManager) AssociationByAddr(addr *net.UDPAddr) (Association, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, assoc := range m.associations {
+		if assoc.Addr.IP.Equal(addr.IP) && assoc.Addr.Port == addr.Port {
+			return *assoc, true
+		}
+	}
+	return Association{}, false
+}
+
+// set stores assoc, replacing the previous association with the same peer.
+func (m *AssociationManager) set(assoc *Association) {
+	m.mu.Lock()
+	old := m.associations[assoc.NodeID.String()]
+	m.associations[assoc.NodeID.String()] = assoc
+	m.mu.Unlock()
+	if old != nil {
+		m.subs.emit(AssociationEvent{Type: AssociationDown, Association: *old, Reason: ErrAssociationReplaced})
+	}
+	m.subs.emit(AssociationEvent{Type: AssociationUp, Association: *assoc})
+}
+
+// update applies f to the association with nodeID, if there is one.
+func (m *AssociationManager) update(nodeID *NodeID, f func(*Association)) bool {
+	m.mu.Lock()
+	assoc, ok := m.associations[nodeID.String()]
+	if ok {
+		f(assoc)
+	}
+	var updated Association
+	if ok {
+		updated = *assoc
+	}
+	m.mu.Unlock()
+	if ok {
+		m.subs.emit(AssociationEvent{Type: AssociationUpdated, Association: updated})
+	}
+	return ok
+}
+
+// Drop removes the association with the peer nodeID without sending it a
+// release request, for instance when the peer stopped answering.
+func (m *AssociationManager) Drop(nodeID *NodeID, reason error) bool {
+	m.mu.Lock()
+	assoc, ok := m.associations[nodeID.String()]
+	delete(m.associations, nodeID.String())
+	m.mu.Unlock()
+	if ok {
+		m.subs.emit(AssociationEvent{Type: AssociationDown, Association: *assoc, Reason: reason})
+	}
+	return ok
+}
+
+func (m *AssociationManager) localNode() (*NodeID, error) {
+	if m.server.NodeID == nil {
+		return nil, errors.New("pfcp: server has no NodeID")
+	}
+	return m.server.NodeID, nil
+}
+
+// Setup sets up an association with the peer at addr.
+func (m *AssociationManager) Setup(ctx context.Context, addr *net.UDPAddr) (Association, error) {
+	nodeID, err := m.localNode()
+	if err != nil {
+		return Association{}, err
+	}
+	req := &PFCPMessage{
+		Header: Header{Version: PfcpVersion, MessageType: PFCP_ASSOCIATION_SETUP_REQUEST},
+		Body: PFCPAssociationSetupRequest{
+			NodeID:             nodeID,
+			RecoveryTimeStamp:  &RecoveryTimeStamp{RecoveryTimeStamp: m.server.RecoveryTimeStamp},
+			UPFunctionFeatures: m.UPFunctionFeatures,
+			CPFunctionFeatures: m.CPFunctionFeatures,
+		},
+	}
+	res, err := m.server.SendRequest(ctx, req, addr)
+	if err != nil {
+		return Association{}, err
+	}
+	body, ok := res.PfcpMessage.Body.(PFCPAssociationSetupResponse)
+	if !ok {
+		return Association{}, fmt.Errorf("pfcp: unexpected association setup response %T", res.PfcpMessage.Body)
+	}
+	if body.Cause.CauseValue != CauseRequestAccepted {
+		return Association{}, &RejectedError{MessageType: PFCP_ASSOCIATION_SETUP_REQUEST, Cause: body.Cause.CauseValue}
+	}
+
+	assoc := &Association{
+		NodeID:             body.NodeID,
+		Addr:               addr,
+		RecoveryTimeStamp:  body.RecoveryTimeStamp.RecoveryTimeStamp,
+		UPFunctionFeatures: body.UPFunctionFeatures,
+		CPFunctionFeatures: body.CPFunctionFeatures,
+		SetUpAt:            time.Now(),
+	}
+	m.set(assoc)
+	return *assoc, nil
+}
+
+// Update sends the current features of the local node to the peer nodeID.
+func (m *AssociationManager) Update(ctx context.Context, peer *NodeID) error {
+	nodeID, err := m.localNode()
+	if err != nil {
+		return err
+	}
+	assoc, ok := m.Association(peer)
+	if !ok {
+		return fmt.Errorf("pfcp: no association with %s", peer)
+	}
+	req := &PFCPMessage{
+		Header: Header{Version: PfcpVersion, MessageType: PFCP_ASSOCIATION_UPDATE_REQUEST},
+		Body: PFCPAssociationUpdateRequest{
+			NodeID:             nodeID,
+			UPFunctionFeatures: m.UPFunctionFeatures,
+			CPFunctionFeatures: m.CPFunctionFeatures,
+		},
+	}
+	res, err := m.server.SendRequest(ctx, req, assoc.Addr)
+	if err != nil {
+		return err
+	}
+	body, ok := res.PfcpMessage.Body.(PFCPAssociationUpdateResponse)
+	if !ok {
+		return fmt.Errorf("pfcp: unexpected association update response %T", res.PfcpMessage.Body)
+	}
+	if body.Cause.CauseValue != CauseRequestAccepted {
+		return &RejectedError{MessageType: PFCP_ASSOCIATION_UPDATE_REQUEST, Cause: body.Cause.CauseValue}
+	}
+	if body.UPFunctionFeatures != nil || body.CPFunctionFeatures != nil {
+		m.update(peer, func(assoc *Association) {
+			if body.UPFunctionFeatures != nil {
+				assoc.UPFunctionFeatures = body.UPFunctionFeatures
+			}
+			if body.CPFunctionFeatures != nil {
+				assoc.CPFunctionFeatures = body.CPFunctionFeatures
+			}
+		})
+	}
+	return nil
+}
+
+// Release releases the association with the peer nodeID. The association is
+// removed even when the peer does not answer.
+func (m *AssociationManager) Release(ctx context.Context, peer *NodeID) error {
+	nodeID, err := m.localNode()
+	if err != nil {
+		return err
+	}
+	assoc, ok := m.Association(peer)
+	if !ok {
+		return fmt.Errorf("pfcp: no association with %s", peer)
+	}
+	req := &PFCPMessage{
+		Header: Header{Version: PfcpVersion, MessageType: PFCP_ASSOCIATION_RELEASE_REQUEST},
+		Body:   PFCPAssociationReleaseRequest{NodeID: nodeID},
+	}
+	res, err := m.server.SendRequest(ctx, req, assoc.Addr)
+	m.Drop(peer, nil)
+	if err != nil {
+		return err
+	}
+	body, ok := res.PfcpMessage.Body.(PFCPAssociationReleaseResponse)
+	if !ok {
+		return fmt.Errorf("pfcp: unexpected association release response %T", res.PfcpMessage.Body)
+	}
+	if body.Cause.CauseValue != CauseRequestAccepted {
+		return &RejectedError{MessageType: PFCP_ASSOCIATION_RELEASE_REQUEST, Cause: body.Cause.CauseValue}
+	}
+	return nil
+}
+
+// Register has mux answer the association setup, update and release
+// requests of the peers.
+func (m *AssociationManager) Register(mux *ServeMux) {
+	mux.HandleFunc(PFCP_ASSOCIATION_SETUP_REQUEST, m.serveSetup)
+	mux.HandleFunc(PFCP_ASSOCIATION_UPDATE_REQUEST, m.serveUpdate)
+	mux.HandleFunc(PFCP_ASSOCIATION_RELEASE_REQUEST, m.serveRelease)
+}
+
+func (m *AssociationManager) serveSetup(w ResponseWriter, req *Message) {
+	body := req.PfcpMessage.Body.(PFCPAssociationSetupRequest)
+	nodeID, err := m.localNode()
+	if err != nil {
+		logger.Warnf("Association setup from %s: %+v", req.RemoteAddr, err)
+		w.Reject(CauseSystemFailure, 0)
+		return
+	}
+	assoc := &Association{
+		NodeID:             body.NodeID,
+		Addr:               req.RemoteAddr,
+		RecoveryTimeStamp:  body.RecoveryTimeStamp.RecoveryTimeStamp,
+		UPFunctionFeatures: body.UPFunctionFeatures,
+		CPFunctionFeatures: body.CPFunctionFeatures,
+		SetUpAt:            time.Now(),
+	}
+	// Set up before the peer gets the response and sends session requests
+	m.set(assoc)
+	err = w.Write(PFCPAssociationSetupResponse{
+		NodeID:             nodeID,
+		Cause:              &Cause{CauseValue: CauseRequestAccepted},
+		RecoveryTimeStamp:  &RecoveryTimeStamp{RecoveryTimeStamp: m.server.RecoveryTimeStamp},
+		UPFunctionFeatures: m.UPFunctionFeatures,
+		CPFunctionFeatures: m.CPFunctionFeatures,
+	})
+	if err != nil {
+		logger.Warnf("Association setup response to %s: %+v", req.RemoteAddr, err)
+		m.Drop(assoc.NodeID, err)
+	}
+}
+
+func (m *AssociationManager) serveUpdate(w ResponseWriter, req *Message) {
+	body := req.PfcpMessage.Body.(PFCPAssociationUpdateRequest)
+	nodeID, err := m.localNode()
+	if err != nil {
+		logger.Warnf("Association update from %s: %+v", req.RemoteAddr, err)
+		w.Reject(CauseSystemFailure, 0)
+		return
+	}
+	var previous Association
+	updated := m.update(body.NodeID, func(assoc *Association) {
+		previous = *assoc
+		if body.UPFunctionFeatures != nil {
+			assoc.UPFunctionFeatures = body.UPFunctionFeatures
+		}
+		if body.CPFunctionFeatures != nil {
+			assoc.CPFunctionFeatures = body.CPFunctionFeatures
+		}
+	})
+	if !updated {
+		w.Reject(CauseNoEstablishedPfcpAssociation, 0)
+		return
+	}
+	if err := w.Write(PFCPAssociationUpdateResponse{
+		NodeID:             nodeID,
+		Cause:              &Cause{CauseValue: CauseRequestAccepted},
+		UPFunctionFeatures: m.UPFunctionFeatures,
+		CPFunctionFeatures: m.CPFunctionFeatures,
+	}); err != nil {
+		logger.Warnf("Association update response to %s: %+v", req.RemoteAddr, err)
+		// The peer keeps the features it had
+		m.update(body.NodeID, func(assoc *Association) {
+			assoc.UPFunctionFeatures = previous.UPFunctionFeatures
+			assoc.CPFunctionFeatures = previous.CPFunctionFeatures
+		})
+	}
+}
+
+func (m *AssociationManager) serveRelease(w ResponseWriter, req *Message) {
+	body := req.PfcpMessage.Body.(PFCPAssociationReleaseRequest)
+	nodeID, err := m.localNode()
+	if err != nil {
+		logger.Warnf("Association release from %s: %+v", req.RemoteAddr, err)
+		w.Reject(CauseSystemFailure, 0)
+		return
+	}
+	if !m.Drop(body.NodeID, nil) {
+		w.Reject(CauseNoEstablishedPfcpAssociation, 0)
+		return
+	}
+	if err := w.Write(PFCPAssociationReleaseResponse{
+		NodeID: nodeID,
+		Cause:  &Cause{CauseValue: CauseRequestAccepted},
+	}); err != nil {
+		logger.Warnf("Association release response to %s: %+v", req.RemoteAddr, err)
+	}
+}
+
+// RequireAssociation is a Middleware rejecting the session related requests
+// of the peers without an association with CauseNoEstablishedPfcpAssociation.
+// The peer of a session establishment request is identified by its Node ID,
+// and otherwise by its address.
+func (m *AssociationManager) RequireAssociation(next Handler) Handler {
+	return HandlerFunc(func(w ResponseWriter, req *Message) {
+		if req.PfcpMessage.Header.Len() == 16 && !m.associated(req) {
+			if err := w.Reject(CauseNoEstablishedPfcpAssociation, 0); err != nil {
+				logger.Warnf("Reject request error: %+v", err)
+			}
+			return
+		}
+		next.ServePFCP(w, req)
+	})
+}
+
+func (m *AssociationManager) associated(req *Message) bool {
+	if body, ok := req.PfcpMessage.Body.(PFCPSessionEstablishmentRequest); ok && body.NodeID != nil {
+		_, ok := m.Association(body.NodeID)
+		return ok
+	}
+	_, ok := m.AssociationByAddr(req.RemoteAddr)
+	return ok
+}
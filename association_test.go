@@ -0,0 +1,247 @@
+package pfcpgolb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb/memconn"
+)
+
+// associationEvents returns the events of the associations of m.
+func associationEvents(t *testing.T, m *AssociationManager) <-chan AssociationEvent {
+	t.Helper()
+	events := make(chan AssociationEvent, 16)
+	t.Cleanup(m.Subscribe(func(e AssociationEvent) { events <- e }))
+	return events
+}
+
+// expectAssociationEvent checks the next event of events.
+func expectAssociationEvent(t *testing.T, events <-chan AssociationEvent, typ AssociationEventType, nodeID *NodeID,
+	reason error,
+) AssociationEvent {
+	t.Helper()
+	select {
+	case e := <-events:
+		if e.Type != typ || e.Association.NodeID.String() != nodeID.String() || !errors.Is(e.Reason, reason) {
+			t.Fatalf("event %s of %s (%v), want %s of %s (%v)", e.Type, e.Association.NodeID, e.Reason,
+				typ, nodeID, reason)
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no %s event of %s", typ, nodeID)
+		return AssociationEvent{}
+	}
+}
+
+// newTestAssociations returns the association managers of the CP function
+// and UP function servers of newTestServers, serving the association
+// requests. The UP function requires an association for the session
+// deletion requests, which it accepts.
+func newTestAssociations(t *testing.T) (cp, up *AssociationManager) {
+	t.Helper()
+	_, cpServer, upServer := newTestServers(t)
+	cp, up = NewAssociationManager(cpServer), NewAssociationManager(upServer)
+	cp.CPFunctionFeatures = &CPFunctionFeatures{SupportedFeatures: 1}
+	up.UPFunctionFeatures = &UPFunctionFeatures{SupportedFeatures: 0x11}
+
+	upMux := NewServeMux()
+	up.Register(upMux)
+	upMux.Use(up.RequireAssociation)
+	upMux.HandleFunc(PFCP_SESSION_DELETION_REQUEST, func(w ResponseWriter, req *Message) {
+		w.Write(PFCPSessionDeletionResponse{Cause: &Cause{CauseValue: CauseRequestAccepted}})
+	})
+	serve(t, upServer, upMux)
+	cpMux := NewServeMux()
+	cp.Register(cpMux)
+	serve(t, cpServer, cpMux)
+	return cp, up
+}
+
+// deleteSession returns the cause of the response to a session deletion
+// request sent by cp.
+func deleteSession(t *testing.T, cp *AssociationManager) uint8 {
+	t.Helper()
+	res := sendRequest(t, cp.server, testUPAddr, Header{MessageType: PFCP_SESSION_DELETION_REQUEST, S: SEID_PRESENT, SEID: 7},
+		PFCPSessionDeletionRequest{})
+	return res.Body.(PFCPSessionDeletionResponse).Cause.CauseValue
+}
+
+func TestAssociationLifecycle(t *testing.T) {
+	cp, up := newTestAssociations(t)
+	cpEvents, upEvents := associationEvents(t, cp), associationEvents(t, up)
+	cpNode, upNode := cp.server.NodeID, up.server.NodeID
+	ctx := context.Background()
+
+	if cause := deleteSession(t, cp); cause != CauseNoEstablishedPfcpAssociation {
+		t.Fatalf("cause before setup = %d, want %d", cause, CauseNoEstablishedPfcpAssociation)
+	}
+
+	assoc, err := cp.Setup(ctx, testUPAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assoc.NodeID.String() != upNode.String() || assoc.UPFunctionFeatures.SupportedFeatures != 0x11 ||
+		!assoc.RecoveryTimeStamp.Equal(up.server.RecoveryTimeStamp) {
+		t.Errorf("association = %+v", assoc)
+	}
+	expectAssociationEvent(t, cpEvents, AssociationUp, upNode, nil)
+	e := expectAssociationEvent(t, upEvents, AssociationUp, cpNode, nil)
+	if e.Association.CPFunctionFeatures.SupportedFeatures != 1 {
+		t.Errorf("CP function features = %+v, want 1", e.Association.CPFunctionFeatures)
+	}
+	if _, ok := up.AssociationByAddr(testCPAddr); !ok {
+		t.Error("no association with the address of the CP function")
+	}
+	if cause := deleteSession(t, cp); cause != CauseRequestAccepted {
+		t.Fatalf("cause after setup = %d, want %d", cause, CauseRequestAccepted)
+	}
+
+	cp.CPFunctionFeatures = &CPFunctionFeatures{SupportedFeatures: 3}
+	if err := cp.Update(ctx, upNode); err != nil {
+		t.Fatal(err)
+	}
+	e = expectAssociationEvent(t, upEvents, AssociationUpdated, cpNode, nil)
+	if e.Association.CPFunctionFeatures.SupportedFeatures != 3 {
+		t.Errorf("updated CP function features = %+v, want 3", e.Association.CPFunctionFeatures)
+	}
+	expectAssociationEvent(t, cpEvents, AssociationUpdated, upNode, nil)
+
+	if err := cp.Release(ctx, upNode); err != nil {
+		t.Fatal(err)
+	}
+	expectAssociationEvent(t, cpEvents, AssociationDown, upNode, nil)
+	expectAssociationEvent(t, upEvents, AssociationDown, cpNode, nil)
+	if n, m := len(cp.Associations()), len(up.Associations()); n != 0 || m != 0 {
+		t.Errorf("%d and %d associations left after release, want 0", n, m)
+	}
+	if cause := deleteSession(t, cp); cause != CauseNoEstablishedPfcpAssociation {
+		t.Errorf("cause after release = %d, want %d", cause, CauseNoEstablishedPfcpAssociation)
+	}
+	if err := cp.Update(ctx, upNode); err == nil {
+		t.Error("update of a released association succeeded")
+	}
+}
+
+func TestAssociationSetupAgain(t *testing.T) {
+	cp, up := newTestAssociations(t)
+	upEvents := associationEvents(t, up)
+	cpNode := cp.server.NodeID
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := cp.Setup(ctx, testUPAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectAssociationEvent(t, upEvents, AssociationUp, cpNode, nil)
+	expectAssociationEvent(t, upEvents, AssociationDown, cpNode, ErrAssociationReplaced)
+	expectAssociationEvent(t, upEvents, AssociationUp, cpNode, nil)
+	if n := len(up.Associations()); n != 1 {
+		t.Errorf("%d associations, want 1", n)
+	}
+}
+
+func TestAssociationDroppedByPeer(t *testing.T) {
+	cp, up := newTestAssociations(t)
+	cpEvents := associationEvents(t, cp)
+	upNode := up.server.NodeID
+	ctx := context.Background()
+
+	if _, err := cp.Setup(ctx, testUPAddr); err != nil {
+		t.Fatal(err)
+	}
+	expectAssociationEvent(t, cpEvents, AssociationUp, upNode, nil)
+	up.Drop(cp.server.NodeID, errors.New("lost"))
+
+	var rejected *RejectedError
+	err := cp.Update(ctx, upNode)
+	if !errors.As(err, &rejected) || rejected.Cause != CauseNoEstablishedPfcpAssociation {
+		t.Fatalf("update error = %v, want cause %d", err, CauseNoEstablishedPfcpAssociation)
+	}
+	// The association is released locally all the same
+	err = cp.Release(ctx, upNode)
+	if !errors.As(err, &rejected) || rejected.Cause != CauseNoEstablishedPfcpAssociation {
+		t.Fatalf("release error = %v, want cause %d", err, CauseNoEstablishedPfcpAssociation)
+	}
+	expectAssociationEvent(t, cpEvents, AssociationDown, upNode, nil)
+	if _, ok := cp.Association(upNode); ok {
+		t.Error("association kept after release")
+	}
+}
+
+func TestAssociationSetBeforeResponse(t *testing.T) {
+	cp, up := newTestAssociations(t)
+	for i := 0; i < 10; i++ {
+		if _, err := cp.Setup(context.Background(), testUPAddr); err != nil {
+			t.Fatal(err)
+		}
+		// The peer may send session requests right away
+		if _, ok := up.Association(cp.server.NodeID); !ok {
+			t.Fatal("association not set up when the peer gets the response")
+		}
+		if err := cp.Release(context.Background(), up.server.NodeID); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAssociationResponseFailure(t *testing.T) {
+	network := memconn.NewNetwork()
+	upConn, err := network.Listen(testUPAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := &failingConn{PacketConn: upConn}
+	upServer := NewPfcpServerConn(failing)
+	upServer.NodeID = &NodeID{NodeIdType: NodeIdTypeIpv4Address, IP: testUPAddr.IP}
+	upServer.RecoveryTimeStamp = time.Unix(time.Now().Unix(), 0)
+	t.Cleanup(func() { upServer.Close() })
+	up := NewAssociationManager(upServer)
+	upMux := NewServeMux()
+	up.Register(upMux)
+	serve(t, upServer, upMux)
+	upEvents := associationEvents(t, up)
+
+	cpServer := newTestServer(t, network, testCPAddr)
+	retransmit := RetransmitTimers{T1: 50 * time.Millisecond, N1: 3}
+	cpServer.Config.Retransmit = map[MessageType]RetransmitTimers{
+		PFCP_ASSOCIATION_SETUP_REQUEST:  retransmit,
+		PFCP_ASSOCIATION_UPDATE_REQUEST: retransmit,
+	}
+	cp := NewAssociationManager(cpServer)
+	cp.CPFunctionFeatures = &CPFunctionFeatures{SupportedFeatures: 1}
+	serve(t, cpServer, NewServeMux())
+	cpNode := cpServer.NodeID
+	ctx := context.Background()
+
+	// The association failing to be answered is dropped, and set up again
+	// on the retransmission of the request
+	if _, err := cp.Setup(ctx, testUPAddr); err != nil {
+		t.Fatal(err)
+	}
+	expectAssociationEvent(t, upEvents, AssociationUp, cpNode, nil)
+	select {
+	case e := <-upEvents:
+		if e.Type != AssociationDown || e.Reason == nil {
+			t.Fatalf("event %s (%v), want down with the write error", e.Type, e.Reason)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no down event")
+	}
+	expectAssociationEvent(t, upEvents, AssociationUp, cpNode, nil)
+
+	// Likewise, the update failing to be answered is undone
+	failing.failed.Store(false)
+	cp.CPFunctionFeatures = &CPFunctionFeatures{SupportedFeatures: 3}
+	if err := cp.Update(ctx, up.server.NodeID); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []uint8{3, 1, 3} {
+		e := expectAssociationEvent(t, upEvents, AssociationUpdated, cpNode, nil)
+		if got := e.Association.CPFunctionFeatures.SupportedFeatures; got != want {
+			t.Errorf("CP function features = %d, want %d", got, want)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+// Command tlvgen generates the AppendBinary and DecodeFrom methods of the
+// structs whose fields are all tagged `tlv`, so that the tlv package can encode
+// and decode them without reflection.
+//
+// Usage:
+//
+//	tlvgen -output pfcp_tlv_gen.go pfcp.go pfcptype.go
+//
+// Structs with a field the generator doesn't handle are left to the
+// reflective encoder. The IE values themselves (Node ID, F-SEID, ...) are
+// still encoded through their BinaryMarshaler and BinaryUnmarshaler methods.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type fieldKind int
+
+const (
+	fieldPtr      fieldKind = iota // *T
+	fieldSlicePtr                  // []*T
+	fieldUnknown                   // []tlv.RawIE `tlv:"unknown"`
+	fieldOrder                     // tlv.Order `tlv:"order"`
+)
+
+type field struct {
+	name         string
+	index        int
+	kind         fieldKind
+	elemType     string
+	tag          int
+	enterpriseID uint16
+	mandatory    bool
+}
+
+type structType struct {
+	name   string
+	fields []field
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("tlvgen: ")
+	output := flag.String("output", "", "output file name")
+	flag.Parse()
+	if *output == "" || flag.NArg() == 0 {
+		log.Fatal("usage: tlvgen -output <file> <go files...>")
+	}
+
+	fset := token.NewFileSet()
+	var pkgName string
+	var types []structType
+	for _, fileName := range flag.Args() {
+		file, err := parser.ParseFile(fset, fileName, nil, parser.ParseComments)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pkgName = file.Name.Name
+		types = append(types, collectStructs(file)...)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].name < types[j].name })
+
+	src, err := generate(pkgName, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// collectStructs returns the structs of file that can have generated methods.
+func collectStructs(file *ast.File) []structType {
+	var types []structType
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			st, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			if fields, ok := parseFields(st); ok {
+				types = append(types, structType{name: typeSpec.Name.Name, fields: fields})
+			}
+		}
+	}
+	return types
+}
+
+func parseFields(st *ast.StructType) ([]field, bool) {
+	var fields []field
+	tags := make(map[string]bool)
+	for _, astField := range st.Fields.List {
+		if len(astField.Names) != 1 || astField.Tag == nil {
+			return nil, false
+		}
+		rawTag, err := strconv.Unquote(astField.Tag.Value)
+		if err != nil {
+			return nil, false
+		}
+		tag, ok := reflect.StructTag(rawTag).Lookup("tlv")
+		if !ok {
+			return nil, false
+		}
+
+		f := field{name: astField.Names[0].Name, index: len(fields)}
+		if tag == "unknown" {
+			if typeString(astField.Type) != "[]tlv.RawIE" {
+				return nil, false
+			}
+			f.kind = fieldUnknown
+			fields = append(fields, f)
+			continue
+		}
+		if tag == "order" {
+			if typeString(astField.Type) != "tlv.Order" {
+				return nil, false
+			}
+			f.kind = fieldOrder
+			fields = append(fields, f)
+			continue
+		}
+
+		if !parseTag(tag, &f) {
+			return nil, false
+		}
+		key := fmt.Sprintf("%d/%d", f.tag, f.enterpriseID)
+		if tags[key] {
+			return nil, false
+		}
+		tags[key] = true
+
+		switch t := astField.Type.(type) {
+		case *ast.StarExpr:
+			ident, ok := t.X.(*ast.Ident)
+			if !ok {
+				return nil, false
+			}
+			f.kind, f.elemType = fieldPtr, ident.Name
+		case *ast.ArrayType:
+			star, ok := t.Elt.(*ast.StarExpr)
+			if !ok || t.Len != nil {
+				return nil, false
+			}
+			ident, ok := star.X.(*ast.Ident)
+			if !ok {
+				return nil, false
+			}
+			f.kind, f.elemType = fieldSlicePtr, ident.Name
+		default:
+			return nil, false
+		}
+		fields = append(fields, f)
+	}
+	return fields, len(fields) > 0
+}
+
+func parseTag(tag string, f *field) bool {
+	parts := strings.Split(tag, ",")
+	tagVal, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	f.tag = tagVal
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "mandatory":
+			f.mandatory = true
+		case strings.HasPrefix(opt, "eid="):
+			eid, err := strconv.ParseUint(strings.TrimPrefix(opt, "eid="), 10, 16)
+			if err != nil {
+				return false
+			}
+			f.enterpriseID = uint16(eid)
+		}
+	}
+	return true
+}
+
+func typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + typeString(t.X)
+	case *ast.ArrayType:
+		return "[]" + typeString(t.Elt)
+	case *ast.SelectorExpr:
+		return typeString(t.X) + "." + t.Sel.Name
+	}
+	return ""
+}
+
+func isVendorSpecific(tag int) bool {
+	return tag&0x8000 != 0
+}
+
+func generate(pkgName string, types []structType) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by tlvgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %s\n\n", pkgName)
+	fmt.Fprintf(buf, "import \"github.com/Nikhil690/pfcpgolb/tlv\"\n")
+
+	for _, t := range types {
+		generateAppend(buf, t)
+		generateDecode(buf, t)
+	}
+	return format.Source(buf.Bytes())
+}
+
+func generateAppend(buf *bytes.Buffer, t structType) {
+	fmt.Fprintf(buf, "\nfunc (v *%s) AppendBinary(b []byte) ([]byte, error) {\n", t.name)
+	// Unknown IEs are interleaved with the known ones by position, and the
+	// IEs received out of field order are written back in their order
+	var reflective []string
+	for _, f := range t.fields {
+		switch f.kind {
+		case fieldUnknown:
+			reflective = append(reflective, fmt.Sprintf("len(v.%s) > 0", f.name))
+		case fieldOrder:
+			reflective = append(reflective, fmt.Sprintf("v.%s.Len() > 0", f.name))
+		}
+	}
+	if len(reflective) > 0 {
+		fmt.Fprintf(buf, "if %s {\n", strings.Join(reflective, " || "))
+		fmt.Fprintf(buf, "ies, err := tlv.MarshalReflect(v)\n")
+		fmt.Fprintf(buf, "if err != nil {\nreturn nil, err\n}\n")
+		fmt.Fprintf(buf, "return append(b, ies...), nil\n}\n")
+	}
+	if hasKnownFields(t) {
+		fmt.Fprintf(buf, "var err error\n")
+	}
+	for _, f := range t.fields {
+		appendCall := fmt.Sprintf("tlv.AppendIE(b, %d, ie)", f.tag)
+		if isVendorSpecific(f.tag) {
+			appendCall = fmt.Sprintf("tlv.AppendVendorIE(b, %d, %d, ie)", f.tag, f.enterpriseID)
+		}
+		switch f.kind {
+		case fieldPtr:
+			fmt.Fprintf(buf, "if ie := v.%s; ie != nil {\n", f.name)
+			fmt.Fprintf(buf, "if b, err = %s; err != nil {\nreturn nil, err\n}\n}\n", appendCall)
+		case fieldSlicePtr:
+			fmt.Fprintf(buf, "for _, ie := range v.%s {\n", f.name)
+			fmt.Fprintf(buf, "if ie == nil {\ncontinue\n}\n")
+			fmt.Fprintf(buf, "if b, err = %s; err != nil {\nreturn nil, err\n}\n}\n", appendCall)
+		}
+	}
+	fmt.Fprintf(buf, "return b, nil\n}\n")
+}
+
+func hasKnownFields(t structType) bool {
+	for _, f := range t.fields {
+		if f.kind == fieldPtr || f.kind == fieldSlicePtr {
+			return true
+		}
+	}
+	return false
+}
+
+func generateDecode(buf *bytes.Buffer, t structType) {
+	var unknown, order string
+	byTag := make(map[int][]field)
+	var tags []int
+	for _, f := range t.fields {
+		if f.kind == fieldUnknown {
+			unknown = f.name
+			continue
+		}
+		if f.kind == fieldOrder {
+			order = f.name
+			continue
+		}
+		if _, ok := byTag[f.tag]; !ok {
+			tags = append(tags, f.tag)
+		}
+		byTag[f.tag] = append(byTag[f.tag], f)
+	}
+
+	skip := "continue"
+	if unknown != "" {
+		skip = fmt.Sprintf("v.%s = append(v.%s, ie)", unknown, unknown)
+	}
+
+	fmt.Fprintf(buf, "\nfunc (v *%s) DecodeFrom(b []byte) error {\n", t.name)
+	if unknown != "" {
+		fmt.Fprintf(buf, "v.%s = nil\n", unknown)
+	}
+	ordered := order != "" && len(tags) > 0
+	if ordered {
+		fmt.Fprintf(buf, "var order tlv.OrderChecker\n")
+	}
+	fmt.Fprintf(buf, "r := tlv.NewReader(b)\n")
+	fmt.Fprintf(buf, "for r.More() {\n")
+	fmt.Fprintf(buf, "ie, err := r.Next()\n")
+	fmt.Fprintf(buf, "if err != nil {\nreturn err\n}\n")
+	if len(tags) == 0 {
+		fmt.Fprintf(buf, "%s\n}\n", skip)
+	} else {
+		fmt.Fprintf(buf, "switch ie.Type {\n")
+		for _, tag := range tags {
+			fmt.Fprintf(buf, "case %d:\n", tag)
+			fields := byTag[tag]
+			if !isVendorSpecific(tag) {
+				generateDecodeField(buf, fields[0], ordered)
+				continue
+			}
+			fmt.Fprintf(buf, "switch ie.EnterpriseID {\n")
+			for _, f := range fields {
+				fmt.Fprintf(buf, "case %d:\n", f.enterpriseID)
+				generateDecodeField(buf, f, ordered)
+			}
+			fmt.Fprintf(buf, "default:\n%s\n}\n", skip)
+		}
+		fmt.Fprintf(buf, "default:\n%s\n}\n", skip)
+		fmt.Fprintf(buf, "}\n")
+	}
+	switch {
+	case ordered:
+		fmt.Fprintf(buf, "v.%s = order.Order(b)\n", order)
+	case order != "":
+		fmt.Fprintf(buf, "v.%s = tlv.Order{}\n", order)
+	}
+	fmt.Fprintf(buf, "return nil\n}\n")
+}
+
+func generateDecodeField(buf *bytes.Buffer, f field, ordered bool) {
+	if ordered {
+		fmt.Fprintf(buf, "order.Add(%d)\n", f.index)
+	}
+	switch f.kind {
+	case fieldPtr:
+		wrapErr := fmt.Sprintf("return tlv.WrapDecodeError(err, %q, -1, ie, %t)", f.name, f.mandatory)
+		fmt.Fprintf(buf, "if v.%s == nil {\nv.%s = new(%s)\n}\n", f.name, f.name, f.elemType)
+		fmt.Fprintf(buf, "if err := tlv.Unmarshal(ie.Value, v.%s); err != nil {\n%s\n}\n", f.name, wrapErr)
+	case fieldSlicePtr:
+		wrapErr := fmt.Sprintf("return tlv.WrapDecodeError(err, %q, len(v.%s), ie, %t)", f.name, f.name, f.mandatory)
+		fmt.Fprintf(buf, "elem := new(%s)\n", f.elemType)
+		fmt.Fprintf(buf, "if err := tlv.Unmarshal(ie.Value, elem); err != nil {\n%s\n}\n", wrapErr)
+		fmt.Fprintf(buf, "v.%s = append(v.%s, elem)\n", f.name, f.name)
+	}
+}
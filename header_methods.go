@@ -3,11 +3,12 @@ package pfcpgolb
 import (
 	"bytes"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"net"
 
 	logger "github.com/sirupsen/logrus"
+
+	"github.com/Nikhil690/pfcpgolb/tlv"
 )
 
 func (h *Header) Len() int {
@@ -22,23 +23,23 @@ func (h *Header) UnmarshalBinary(data []byte) error {
 	var tmpBuf uint8
 	byteReader := bytes.NewReader(data)
 	if err := binary.Read(byteReader, binary.BigEndian, &tmpBuf); err != nil {
-		return errors.New("")
+		return &tlv.DecodeError{Path: "Header.Flags", Offset: 0, Err: err}
 	}
-	h.Version, h.MP, h.S = tmpBuf>>5, (tmpBuf&0x02)>>1, tmpBuf&0x01
+	h.Version, h.FO, h.MP, h.S = tmpBuf>>5, (tmpBuf&0x04)>>2, (tmpBuf&0x02)>>1, tmpBuf&0x01
 	if err := binary.Read(byteReader, binary.BigEndian, &h.MessageType); err != nil {
-		fmt.Printf("Binary write error: %+v", err)
+		return &tlv.DecodeError{Path: "Header.MessageType", Offset: 1, Err: err}
 	}
 	if err := binary.Read(byteReader, binary.BigEndian, &h.MessageLength); err != nil {
-		fmt.Printf("Binary write error: %+v", err)
+		return &tlv.DecodeError{Path: "Header.MessageLength", Offset: 2, Err: err}
 	}
 	if h.S&1 != 0 {
 		if err := binary.Read(byteReader, binary.BigEndian, &h.SEID); err != nil {
-			fmt.Printf("Binary write error: %+v", err)
+			return &tlv.DecodeError{Path: "Header.SEID", Offset: 4, Err: err}
 		}
 	}
 	var snAndSpare uint32
 	if err := binary.Read(byteReader, binary.BigEndian, &snAndSpare); err != nil {
-		fmt.Printf("Binary write error: %+v", err)
+		return &tlv.DecodeError{Path: "Header.SequenceNumber", Offset: 4 + 8*int(h.S&1), Err: err}
 	}
 
 	h.SequenceNumber = snAndSpare >> 8
@@ -92,7 +93,7 @@ func (pfcpServer *PfcpServer) FindTransaction(msg *PFCPMessage, addr *net.UDPAdd
 func (h *Header) MarshalBinary() (data []byte, err error) {
 	var tmpbuf uint8
 	buffer := new(bytes.Buffer)
-	tmpbuf = h.Version<<5 | (h.MP&1)<<1 | (h.S & 1)
+	tmpbuf = h.Version<<5 | (h.FO&1)<<2 | (h.MP&1)<<1 | (h.S & 1)
 	if err := binary.Write(buffer, binary.BigEndian, &tmpbuf); err != nil {
 		fmt.Printf("Binary write error: %+v", err)
 	}
@@ -114,8 +115,8 @@ func (h *Header) MarshalBinary() (data []byte, err error) {
 	} else {
 		spareAndMP = 0
 	}
-	if h.SequenceNumber > (1<<24 - 1) {
-		fmt.Printf("Sequence number must be less 24bit integer")
+	if h.SequenceNumber > MaxSequenceNumber {
+		return nil, fmt.Errorf("sequence number %d exceeds 24 bits", h.SequenceNumber)
 	}
 
 	snAndSpare = h.SequenceNumber<<8 | uint32(spareAndMP)
@@ -0,0 +1,29 @@
+package pfcpgolb
+
+import (
+	"testing"
+)
+
+func FuzzHeaderUnmarshalBinary(f *testing.F) {
+	for _, data := range testMessages(f) {
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var header Header
+		if err := header.UnmarshalBinary(data); err != nil {
+			return
+		}
+		// The header decoded is encoded into one that decodes the same
+		encoded, err := header.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary of %+v: %v", header, err)
+		}
+		var decoded Header
+		if err := decoded.UnmarshalBinary(encoded); err != nil {
+			t.Fatalf("UnmarshalBinary of %x: %v", encoded, err)
+		}
+		if decoded != header {
+			t.Fatalf("UnmarshalBinary = %+v, want %+v", decoded, header)
+		}
+	})
+}
@@ -0,0 +1,313 @@
+package pfcpgolb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultHeartbeatInterval is the time between two heartbeats sent to a
+	// peer, unless set by HeartbeatSupervisor.Interval.
+	DefaultHeartbeatInterval = 30 * time.Second
+	// DefaultHeartbeatMaxMissed is the number of heartbeats in a row a peer
+	// fails to answer before it is deemed failed, unless set by
+	// HeartbeatSupervisor.MaxMissed.
+	DefaultHeartbeatMaxMissed = 3
+)
+
+type HeartbeatEventType uint8
+
+const (
+	// PeerFailed reports a peer that missed MaxMissed heartbeats in a row
+	PeerFailed HeartbeatEventType = iota
+	// PeerRecovered reports a failed peer answering again
+	PeerRecovered
+	// PeerRestarted reports a peer whose recovery time stamp changed
+	PeerRestarted
+)
+
+func (t HeartbeatEventType) String() string {
+	switch t {
+	case PeerFailed:
+		return "failed"
+	case PeerRecovered:
+		return "recovered"
+	case PeerRestarted:
+		return "restarted"
+	default:
+		return fmt.Sprintf("HeartbeatEventType(%d)", uint8(t))
+	}
+}
+
+// HeartbeatEvent reports a change of a peer watched by a HeartbeatSupervisor.
+type HeartbeatEvent struct {
+	Type HeartbeatEventType
+	Addr *net.UDPAddr
+	// RecoveryTimeStamp is the start time of the peer, as last received
+	RecoveryTimeStamp time.Time
+	// PreviousRecoveryTimeStamp is the start time of the peer before it
+	// restarted
+	PreviousRecoveryTimeStamp time.Time
+	// Err is the failure of the last heartbeat of a failed peer
+	Err error
+}
+
+type heartbeatPeer struct {
+	addr              *net.UDPAddr
+	cancel            context.CancelFunc
+	done              chan struct{}
+	recoveryTimeStamp time.Time
+	missed            int
+	failed            bool
+}
+
+// HeartbeatSupervisor checks that the peers it watches are alive with
+// heartbeats (TS 29.244 6.2.2), and detects their restarts from the recovery
+// time stamps they send. It answers the heartbeats of the peers through the
+// handler added to a ServeMux by Register, with the RecoveryTimeStamp of the
+// server.
+type HeartbeatSupervisor struct {
+	server *PfcpServer
+	// Interval is the time between two heartbeats sent to a peer, by default
+	// DefaultHeartbeatInterval.
+	Interval time.Duration
+	// MaxMissed is the number of heartbeats in a row a peer fails to answer
+	// before it is deemed failed, by default DefaultHeartbeatMaxMissed.
+	MaxMissed int
+
+	mu    sync.Mutex
+	peers map[string]*heartbeatPeer
+
+	subs subscribers[HeartbeatEvent]
+}
+
+func NewHeartbeatSupervisor(server *PfcpServer) *HeartbeatSupervisor {
+	return &HeartbeatSupervisor{
+		server: server,
+		peers:  make(map[string]*heartbeatPeer),
+	}
+}
+
+// Subscribe has f called with the events of the peers watched, until the
+// returned function is called. f must not block.
+func (h *HeartbeatSupervisor) Subscribe(f func(HeartbeatEvent)) (unsubscribe func()) {
+	return h.subs.subscribe(f)
+}
+
+// Watch starts sending heartbeats to the peer at addr. recoveryTimeStamp is
+// the start time of the peer when known, for instance from its association,
+// and is zero otherwise.
+func (h *HeartbeatSupervisor) Watch(addr *net.UDPAddr, recoveryTimeStamp time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.peers[addr.String()]; ok {
+		return fmt.Errorf("pfcp: heartbeat: %s already watched", addr)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	peer := &heartbeatPeer{
+		addr:              addr,
+		cancel:            cancel,
+		done:              make(chan struct{}),
+		recoveryTimeStamp: recoveryTimeStamp,
+	}
+	h.peers[addr.String()] = peer
+	go h.run(ctx, peer)
+	return nil
+}
+
+// Unwatch stops sending heartbeats to the peer at addr.
+func (h *HeartbeatSupervisor) Unwatch(addr *net.UDPAddr) {
+	h.mu.Lock()
+	peer, ok := h.peers[addr.String()]
+	delete(h.peers, addr.String())
+	h.mu.Unlock()
+	if ok {
+		peer.cancel()
+	}
+}
+
+// Close stops sending heartbeats to all the peers, and waits for the
+// heartbeats in flight to end. It must not be called by a subscriber.
+func (h *HeartbeatSupervisor) Close() {
+	h.mu.Lock()
+	peers := h.peers
+	h.peers = make(map[string]*heartbeatPeer)
+	h.mu.Unlock()
+	for _, peer := range peers {
+		peer.cancel()
+	}
+	for _, peer := range peers {
+		<-peer.done
+	}
+}
+
+func (h *HeartbeatSupervisor) interval() time.Duration {
+	if h.Interval > 0 {
+		return h.Interval
+	}
+	return DefaultHeartbeatInterval
+}
+
+func (h *HeartbeatSupervisor) maxMissed() int {
+	if h.MaxMissed > 0 {
+		return h.MaxMissed
+	}
+	return DefaultHeartbeatMaxMissed
+}
+
+func (h *HeartbeatSupervisor) run(ctx context.Context, peer *heartbeatPeer) {
+	defer close(peer.done)
+	timer := time.NewTimer(h.interval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+
+		req := &PFCPMessage{
+			Header: Header{Version: PfcpVersion, MessageType: PFCP_HEARTBEAT_REQUEST},
+			Body: HeartbeatRequest{
+				RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: h.server.RecoveryTimeStamp},
+			},
+		}
+		res, err := h.server.SendRequest(ctx, req, peer.addr)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			if body, ok := res.PfcpMessage.Body.(HeartbeatResponse); ok {
+				h.alive(peer, body.RecoveryTimeStamp.RecoveryTimeStamp)
+			} else {
+				err = fmt.Errorf("pfcp: unexpected heartbeat response %T", res.PfcpMessage.Body)
+			}
+		}
+		if err != nil {
+			h.miss(peer, err)
+		}
+		timer.Reset(h.interval())
+	}
+}
+
+// alive records an answer of peer, with its recovery time stamp.
+func (h *HeartbeatSupervisor) alive(peer *heartbeatPeer, recoveryTimeStamp time.Time) {
+	var events []HeartbeatEvent
+	h.mu.Lock()
+	peer.missed = 0
+	if peer.failed {
+		peer.failed = false
+		events = append(events, HeartbeatEvent{
+			Type:              PeerRecovered,
+			Addr:              peer.addr,
+			RecoveryTimeStamp: recoveryTimeStamp,
+		})
+	}
+	if !peer.recoveryTimeStamp.IsZero() && !peer.recoveryTimeStamp.Equal(recoveryTimeStamp) {
+		events = append(events, HeartbeatEvent{
+			Type:                      PeerRestarted,
+			Addr:                      peer.addr,
+			RecoveryTimeStamp:         recoveryTimeStamp,
+			PreviousRecoveryTimeStamp: peer.recoveryTimeStamp,
+		})
+	}
+	peer.recoveryTimeStamp = recoveryTimeStamp
+	h.mu.Unlock()
+	for _, event := range events {
+		h.subs.emit(event)
+	}
+}
+
+// miss records a heartbeat that peer failed to answer.
+func (h *HeartbeatSupervisor) miss(peer *heartbeatPeer, err error) {
+	h.mu.Lock()
+	peer.missed++
+	failed := !peer.failed && peer.missed >= h.maxMissed()
+	if failed {
+		peer.failed = true
+	}
+	missed, recoveryTimeStamp := peer.missed, peer.recoveryTimeStamp
+	h.mu.Unlock()
+	logger.Debugf("Heartbeat to %s missed (%d in a row): %+v", peer.addr, missed, err)
+	if failed {
+		h.subs.emit(HeartbeatEvent{
+			Type:              PeerFailed,
+			Addr:              peer.addr,
+			RecoveryTimeStamp: recoveryTimeStamp,
+			Err:               err,
+		})
+	}
+}
+
+// Register has mux answer the heartbeats of the peers.
+func (h *HeartbeatSupervisor) Register(mux *ServeMux) {
+	mux.HandleFunc(PFCP_HEARTBEAT_REQUEST, h.serveHeartbeat)
+}
+
+func (h *HeartbeatSupervisor) serveHeartbeat(w ResponseWriter, req *Message) {
+	body := req.PfcpMessage.Body.(HeartbeatRequest)
+	if err := w.Write(HeartbeatResponse{
+		RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: h.server.RecoveryTimeStamp},
+	}); err != nil {
+		logger.Warnf("Heartbeat response to %s: %+v", req.RemoteAddr, err)
+	}
+
+	h.mu.Lock()
+	peer, ok := h.peers[req.RemoteAddr.String()]
+	h.mu.Unlock()
+	if ok {
+		h.alive(peer, body.RecoveryTimeStamp.RecoveryTimeStamp)
+	}
+}
+
+// Reasons of an association dropped by a supervisor
+var (
+	ErrPeerFailed    = errors.New("pfcp: peer failed")
+	ErrPeerRestarted = errors.New("pfcp: peer restarted")
+)
+
+// Supervise has h watch the peers while they have an association, dropping
+// the association of a peer that fails or restarts. It stops when the
+// returned function is called.
+func (m *AssociationManager) Supervise(h *HeartbeatSupervisor) (stop func()) {
+	stopAssociations := m.Subscribe(func(event AssociationEvent) {
+		switch event.Type {
+		case AssociationUp:
+			h.Unwatch(event.Association.Addr)
+			if err := h.Watch(event.Association.Addr, event.Association.RecoveryTimeStamp); err != nil {
+				logger.Warnf("Watch %s: %+v", event.Association.Addr, err)
+			}
+		case AssociationDown:
+			h.Unwatch(event.Association.Addr)
+		}
+	})
+	stopHeartbeats := h.Subscribe(func(event HeartbeatEvent) {
+		var reason error
+		switch event.Type {
+		case PeerFailed:
+			reason = ErrPeerFailed
+		case PeerRestarted:
+			reason = ErrPeerRestarted
+		default:
+			return
+		}
+		if assoc, ok := m.AssociationByAddr(event.Addr); ok {
+			m.Drop(assoc.NodeID, reason)
+		}
+	})
+	for _, assoc := range m.Associations() {
+		if err := h.Watch(assoc.Addr, assoc.RecoveryTimeStamp); err != nil {
+			logger.Debugf("Watch %s: %+v", assoc.Addr, err)
+		}
+	}
+	return func() {
+		stopAssociations()
+		stopHeartbeats()
+	}
+}
@@ -0,0 +1,197 @@
+package pfcpgolb
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testHeartbeatPeer is a UP function server answering the heartbeats with
+// recoveryTimeStamp, unless silent.
+type testHeartbeatPeer struct {
+	server *PfcpServer
+	mux    *ServeMux
+
+	recoveryTimeStamp atomic.Int64
+	silent            atomic.Bool
+}
+
+func (p *testHeartbeatPeer) ServePFCP(w ResponseWriter, req *Message) {
+	if p.silent.Load() {
+		return
+	}
+	w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: time.Unix(p.recoveryTimeStamp.Load(), 0)}})
+}
+
+// newTestSupervisor returns a supervisor of the CP function server of
+// newTestServers sending heartbeats every interval, and the UP function
+// peer answering them.
+func newTestSupervisor(t *testing.T, interval time.Duration) (*HeartbeatSupervisor, *testHeartbeatPeer) {
+	t.Helper()
+	_, cp, up := newTestServers(t)
+	cp.Config.Retransmit = map[MessageType]RetransmitTimers{PFCP_HEARTBEAT_REQUEST: {T1: 50 * time.Millisecond}}
+	h := NewHeartbeatSupervisor(cp)
+	h.Interval, h.MaxMissed = interval, 2
+	t.Cleanup(h.Close)
+	mux := NewServeMux()
+	h.Register(mux)
+	serve(t, cp, mux)
+
+	peer := &testHeartbeatPeer{server: up, mux: NewServeMux()}
+	peer.recoveryTimeStamp.Store(up.RecoveryTimeStamp.Unix())
+	peer.mux.Handle(PFCP_HEARTBEAT_REQUEST, peer)
+	serve(t, up, peer.mux)
+	return h, peer
+}
+
+// heartbeatEvents returns the events of the peers watched by h.
+func heartbeatEvents(t *testing.T, h *HeartbeatSupervisor) <-chan HeartbeatEvent {
+	t.Helper()
+	events := make(chan HeartbeatEvent, 16)
+	t.Cleanup(h.Subscribe(func(e HeartbeatEvent) { events <- e }))
+	return events
+}
+
+func expectHeartbeatEvent(t *testing.T, events <-chan HeartbeatEvent, typ HeartbeatEventType) HeartbeatEvent {
+	t.Helper()
+	select {
+	case e := <-events:
+		if e.Type != typ {
+			t.Fatalf("event %s of %s, want %s", e.Type, e.Addr, typ)
+		}
+		if e.Addr.String() != testUPAddr.String() {
+			t.Errorf("event address %s, want %s", e.Addr, testUPAddr)
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no %s event", typ)
+		return HeartbeatEvent{}
+	}
+}
+
+// noHeartbeatEvent checks that no event comes for a few intervals.
+func noHeartbeatEvent(t *testing.T, events <-chan HeartbeatEvent, interval time.Duration) {
+	t.Helper()
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event %s of %s", e.Type, e.Addr)
+	case <-time.After(5 * interval):
+	}
+}
+
+func TestHeartbeatFailureAndRecovery(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	h, peer := newTestSupervisor(t, interval)
+	up := peer.server
+	events := heartbeatEvents(t, h)
+	if err := h.Watch(testUPAddr, up.RecoveryTimeStamp); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Watch(testUPAddr, up.RecoveryTimeStamp); err == nil {
+		t.Error("peer watched twice")
+	}
+	noHeartbeatEvent(t, events, interval)
+
+	peer.silent.Store(true)
+	e := expectHeartbeatEvent(t, events, PeerFailed)
+	if e.Err == nil || !e.RecoveryTimeStamp.Equal(up.RecoveryTimeStamp) {
+		t.Errorf("failure event = %+v", e)
+	}
+	// Reported once while the peer stays silent
+	noHeartbeatEvent(t, events, interval)
+
+	peer.silent.Store(false)
+	e = expectHeartbeatEvent(t, events, PeerRecovered)
+	if !e.RecoveryTimeStamp.Equal(up.RecoveryTimeStamp) {
+		t.Errorf("recovery time stamp = %s, want %s", e.RecoveryTimeStamp, up.RecoveryTimeStamp)
+	}
+	noHeartbeatEvent(t, events, interval)
+
+	h.Unwatch(testUPAddr)
+	peer.silent.Store(true)
+	noHeartbeatEvent(t, events, interval)
+}
+
+func TestHeartbeatRestart(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	h, peer := newTestSupervisor(t, interval)
+	up := peer.server
+	events := heartbeatEvents(t, h)
+	// Without a known recovery time stamp, the first one received is not a
+	// restart
+	if err := h.Watch(testUPAddr, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	noHeartbeatEvent(t, events, interval)
+
+	restart := up.RecoveryTimeStamp.Add(time.Hour)
+	peer.recoveryTimeStamp.Store(restart.Unix())
+	e := expectHeartbeatEvent(t, events, PeerRestarted)
+	if !e.RecoveryTimeStamp.Equal(restart) || !e.PreviousRecoveryTimeStamp.Equal(up.RecoveryTimeStamp) {
+		t.Errorf("restart from %s to %s, want from %s to %s", e.PreviousRecoveryTimeStamp, e.RecoveryTimeStamp,
+			up.RecoveryTimeStamp, restart)
+	}
+	noHeartbeatEvent(t, events, interval)
+}
+
+func TestHeartbeatRestartFromRequest(t *testing.T) {
+	h, peer := newTestSupervisor(t, time.Hour)
+	up := peer.server
+	events := heartbeatEvents(t, h)
+	if err := h.Watch(testUPAddr, up.RecoveryTimeStamp); err != nil {
+		t.Fatal(err)
+	}
+
+	// The heartbeats of the peer tell its restarts too
+	restart := up.RecoveryTimeStamp.Add(time.Hour)
+	res := sendRequest(t, up, testCPAddr, Header{MessageType: PFCP_HEARTBEAT_REQUEST},
+		HeartbeatRequest{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: restart}})
+	if stamp := res.Body.(HeartbeatResponse).RecoveryTimeStamp.RecoveryTimeStamp; !stamp.Equal(h.server.RecoveryTimeStamp) {
+		t.Errorf("response recovery time stamp = %s, want %s", stamp, h.server.RecoveryTimeStamp)
+	}
+	e := expectHeartbeatEvent(t, events, PeerRestarted)
+	if !e.RecoveryTimeStamp.Equal(restart) {
+		t.Errorf("recovery time stamp = %s, want %s", e.RecoveryTimeStamp, restart)
+	}
+}
+
+func TestSuperviseAssociations(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	h, peer := newTestSupervisor(t, interval)
+	cp := NewAssociationManager(h.server)
+	NewAssociationManager(peer.server).Register(peer.mux)
+	t.Cleanup(cp.Supervise(h))
+	// Subscribed after Supervise, to get the events once the peer is
+	// watched or unwatched
+	cpEvents := associationEvents(t, cp)
+	upNode := peer.server.NodeID
+	ctx := context.Background()
+
+	if _, err := cp.Setup(ctx, testUPAddr); err != nil {
+		t.Fatal(err)
+	}
+	expectAssociationEvent(t, cpEvents, AssociationUp, upNode, nil)
+	peer.silent.Store(true)
+	expectAssociationEvent(t, cpEvents, AssociationDown, upNode, ErrPeerFailed)
+
+	peer.silent.Store(false)
+	if _, err := cp.Setup(ctx, testUPAddr); err != nil {
+		t.Fatal(err)
+	}
+	expectAssociationEvent(t, cpEvents, AssociationUp, upNode, nil)
+	peer.recoveryTimeStamp.Add(1)
+	expectAssociationEvent(t, cpEvents, AssociationDown, upNode, ErrPeerRestarted)
+
+	// The peer is no longer watched without its association
+	select {
+	case e := <-cpEvents:
+		t.Fatalf("unexpected event %s of %s", e.Type, e.Association.NodeID)
+	case <-time.After(5 * interval):
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.peers) != 0 {
+		t.Errorf("%d peers watched, want 0", len(h.peers))
+	}
+}
@@ -0,0 +1,65 @@
+package memconn
+
+import (
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// Conditions are the impairments of the datagrams written to a connection
+// wrapped by Impair.
+type Conditions struct {
+	// Loss is the probability for a datagram to be dropped, from 0 to 1
+	Loss float64
+	// Latency delays every datagram
+	Latency time.Duration
+	// Jitter adds a random delay of up to Jitter to the latency, reordering
+	// the datagrams
+	Jitter time.Duration
+	// Seed seeds the random losses and jitter, from the time when 0
+	Seed int64
+}
+
+// Impair returns conn with the datagrams it writes lost or delayed as set by
+// cond. It wraps any net.PacketConn, a UDP socket as well as a Conn.
+//
+// Like a lossy network, a dropped datagram is reported as written, and a
+// delayed one is written later, its write error ignored.
+func Impair(conn net.PacketConn, cond Conditions) net.PacketConn {
+	seed := cond.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &impairedConn{PacketConn: conn, cond: cond, rand: rand.New(rand.NewSource(seed))}
+}
+
+type impairedConn struct {
+	net.PacketConn
+	cond Conditions
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func (c *impairedConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	c.mu.Lock()
+	lost := c.cond.Loss > 0 && c.rand.Float64() < c.cond.Loss
+	delay := c.cond.Latency
+	if c.cond.Jitter > 0 {
+		delay += time.Duration(c.rand.Int63n(int64(c.cond.Jitter) + 1))
+	}
+	c.mu.Unlock()
+
+	switch {
+	case lost:
+		return len(b), nil
+	case delay <= 0:
+		return c.PacketConn.WriteTo(b, addr)
+	}
+	data := append([]byte(nil), b...)
+	time.AfterFunc(delay, func() {
+		c.PacketConn.WriteTo(data, addr)
+	})
+	return len(b), nil
+}
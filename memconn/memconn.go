@@ -0,0 +1,187 @@
+// Package memconn provides an in-memory packet transport, to run PFCP servers
+// against each other without opening sockets.
+//
+// The connections of a Network use *net.UDPAddr addresses and behave like
+// UDP sockets: a datagram sent to an address nobody listens on, or to a
+// connection whose receive queue is full, is silently lost. Impair adds
+// random losses and delays to any connection.
+package memconn
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// QueueLen is the number of datagrams a connection holds before dropping the
+// ones it receives.
+const QueueLen = 256
+
+// Network links the connections listening on it.
+type Network struct {
+	mu    sync.Mutex
+	conns map[string]*Conn
+}
+
+// Conn is a connection of a Network. It implements net.PacketConn.
+type Conn struct {
+	network   *Network
+	addr      *net.UDPAddr
+	in        chan packet
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	mu            sync.Mutex
+	readDeadline  time.Time
+	deadlineSet   chan struct{}
+	writeDeadline time.Time
+}
+
+type packet struct {
+	data []byte
+	from *net.UDPAddr
+}
+
+func NewNetwork() *Network {
+	return &Network{conns: make(map[string]*Conn)}
+}
+
+// Listen returns a connection receiving the datagrams sent to addr.
+func (n *Network) Listen(addr *net.UDPAddr) (*Conn, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	key := addr.String()
+	if _, ok := n.conns[key]; ok {
+		return nil, fmt.Errorf("memconn: listen %s: address already in use", key)
+	}
+	c := &Conn{
+		network:     n,
+		addr:        addr,
+		in:          make(chan packet, QueueLen),
+		closed:      make(chan struct{}),
+		deadlineSet: make(chan struct{}),
+	}
+	n.conns[key] = c
+	return c, nil
+}
+
+func (n *Network) lookup(addr string) *Conn {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.conns[addr]
+}
+
+func (c *Conn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		c.mu.Lock()
+		deadline, deadlineSet := c.readDeadline, c.deadlineSet
+		c.mu.Unlock()
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			d := time.Until(deadline)
+			if d <= 0 {
+				return 0, nil, c.opError("read", os.ErrDeadlineExceeded)
+			}
+			timer = time.NewTimer(d)
+			timeout = timer.C
+		}
+
+		n, from, err, done := c.receive(b, timeout, deadlineSet)
+		if timer != nil {
+			timer.Stop()
+		}
+		if done {
+			return n, from, err
+		}
+	}
+}
+
+// receive waits for a datagram, returning done false when the read deadline
+// changed.
+func (c *Conn) receive(b []byte, timeout <-chan time.Time, deadlineSet chan struct{}) (int, net.Addr, error, bool) {
+	select {
+	case p := <-c.in:
+		return copy(b, p.data), p.from, nil, true
+	case <-c.closed:
+		return 0, nil, c.opError("read", net.ErrClosed), true
+	case <-timeout:
+		return 0, nil, c.opError("read", os.ErrDeadlineExceeded), true
+	case <-deadlineSet:
+		return 0, nil, nil, false
+	}
+}
+
+func (c *Conn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, c.opError("write", net.ErrClosed)
+	default:
+	}
+	c.mu.Lock()
+	deadline := c.writeDeadline
+	c.mu.Unlock()
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return 0, c.opError("write", os.ErrDeadlineExceeded)
+	}
+
+	dest := c.network.lookup(addr.String())
+	if dest == nil {
+		return len(b), nil
+	}
+	p := packet{data: append([]byte(nil), b...), from: c.addr}
+	select {
+	case dest.in <- p:
+	default:
+		// Receive queue full
+	}
+	return len(b), nil
+}
+
+// Close closes the connection and frees its address.
+func (c *Conn) Close() error {
+	err := c.opError("close", net.ErrClosed)
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.network.mu.Lock()
+		delete(c.network.conns, c.addr.String())
+		c.network.mu.Unlock()
+		err = nil
+	})
+	return err
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return c.addr
+}
+
+func (c *Conn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	// Wake up the pending reads
+	close(c.deadlineSet)
+	c.deadlineSet = make(chan struct{})
+	return nil
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeDeadline = t
+	return nil
+}
+
+func (c *Conn) opError(op string, err error) error {
+	return &net.OpError{Op: op, Net: "memconn", Addr: c.addr, Err: err}
+}
@@ -0,0 +1,157 @@
+package memconn
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+var (
+	addrA = &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1).To4(), Port: 8805}
+	addrB = &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2).To4(), Port: 8805}
+)
+
+// listen returns the connections of a and b on a new network, closed at the
+// end of the test.
+func listen(t *testing.T) (a, b *Conn) {
+	t.Helper()
+	network := NewNetwork()
+	a, err := network.Listen(addrA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = network.Listen(addrB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		a.Close()
+		b.Close()
+	})
+	return a, b
+}
+
+// read returns the next datagram received by conn, failing after timeout.
+func read(t *testing.T, conn net.PacketConn, timeout time.Duration) ([]byte, net.Addr, error) {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	buf := make([]byte, 64)
+	n, from, err := conn.ReadFrom(buf)
+	return buf[:n], from, err
+}
+
+func TestConn(t *testing.T) {
+	a, b := listen(t)
+	if _, err := a.WriteTo([]byte("ping"), addrB); err != nil {
+		t.Fatal(err)
+	}
+	data, from, err := read(t, b, time.Second)
+	if err != nil || string(data) != "ping" || from.String() != addrA.String() {
+		t.Fatalf("ReadFrom = %q, %s, %v, want %q from %s", data, from, err, "ping", addrA)
+	}
+
+	// Nobody listens on the address: lost
+	if _, err := a.WriteTo([]byte("lost"), &net.UDPAddr{IP: net.IPv4(10, 0, 0, 9), Port: 8805}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := read(t, b, 10*time.Millisecond); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("ReadFrom error = %v, want %v", err, os.ErrDeadlineExceeded)
+	}
+}
+
+func TestConnReadDeadlineUnblocks(t *testing.T) {
+	a, _ := listen(t)
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := a.ReadFrom(make([]byte, 64))
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	a.SetReadDeadline(time.Now())
+	select {
+	case err := <-done:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatalf("ReadFrom error = %v, want %v", err, os.ErrDeadlineExceeded)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pending ReadFrom not woken up by SetReadDeadline")
+	}
+}
+
+func TestConnClose(t *testing.T) {
+	network := NewNetwork()
+	a, err := network.Listen(addrA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := network.Listen(addrA); err == nil {
+		t.Fatal("Listen on an address in use = nil error")
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := a.ReadFrom(make([]byte, 64))
+		done <- err
+	}()
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; !errors.Is(err, net.ErrClosed) {
+		t.Errorf("pending ReadFrom error = %v, want %v", err, net.ErrClosed)
+	}
+	if _, err := a.WriteTo([]byte("ping"), addrB); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("WriteTo error = %v, want %v", err, net.ErrClosed)
+	}
+	if err := a.Close(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("second Close error = %v, want %v", err, net.ErrClosed)
+	}
+	// The address is free again
+	if _, err := network.Listen(addrA); err != nil {
+		t.Errorf("Listen after Close: %v", err)
+	}
+}
+
+func TestImpairLoss(t *testing.T) {
+	a, b := listen(t)
+	const n = 200
+	lossy := Impair(a, Conditions{Loss: 0.5, Seed: 1})
+	for i := 0; i < n; i++ {
+		if _, err := lossy.WriteTo([]byte{byte(i)}, addrB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	received := 0
+	for {
+		if _, _, err := read(t, b, 10*time.Millisecond); err != nil {
+			break
+		}
+		received++
+	}
+	if received < n/4 || received > 3*n/4 {
+		t.Errorf("%d datagrams of %d received with a loss of 0.5", received, n)
+	}
+
+	lost := Impair(a, Conditions{Loss: 1})
+	lost.WriteTo([]byte("lost"), addrB)
+	if _, _, err := read(t, b, 10*time.Millisecond); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("ReadFrom error = %v, want %v", err, os.ErrDeadlineExceeded)
+	}
+}
+
+func TestImpairLatency(t *testing.T) {
+	a, b := listen(t)
+	cond := Conditions{Latency: 30 * time.Millisecond, Jitter: 20 * time.Millisecond, Seed: 1}
+	slow := Impair(a, cond)
+	start := time.Now()
+	if _, err := slow.WriteTo([]byte("ping"), addrB); err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := read(t, b, time.Second)
+	if err != nil || string(data) != "ping" {
+		t.Fatalf("ReadFrom = %q, %v", data, err)
+	}
+	if elapsed := time.Since(start); elapsed < cond.Latency {
+		t.Errorf("datagram received after %s, want at least %s", elapsed, cond.Latency)
+	}
+}
@@ -0,0 +1,223 @@
+package pfcpgolb
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRoutes is the number of peers whose socket multiConn remembers
+	maxRoutes = 4096
+	// routeTTL is how long multiConn remembers the socket of a peer it no
+	// longer receives from
+	routeTTL = 10 * time.Minute
+)
+
+// multiConn is a net.PacketConn receiving on several UDP sockets. A datagram
+// to a peer is sent from the socket that last received from it in routeTTL,
+// or else from the first socket of the address family of the peer.
+type multiConn struct {
+	conns     []*net.UDPConn
+	in        chan multiConnPacket
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	routesMu sync.Mutex
+	// Socket per peer address, up to maxRoutes
+	routes map[string]multiConnRoute
+
+	mu           sync.Mutex
+	readDeadline time.Time
+	deadlineSet  chan struct{}
+}
+
+type multiConnRoute struct {
+	conn *net.UDPConn
+	// seen is when the peer was last received from
+	seen time.Time
+}
+
+type multiConnPacket struct {
+	data []byte
+	addr net.Addr
+	err  error
+}
+
+func newMultiConn(conns []*net.UDPConn) *multiConn {
+	c := &multiConn{
+		conns:       conns,
+		routes:      make(map[string]multiConnRoute),
+		in:          make(chan multiConnPacket),
+		closed:      make(chan struct{}),
+		deadlineSet: make(chan struct{}),
+	}
+	for _, conn := range conns {
+		go c.receive(conn)
+	}
+	return c
+}
+
+func (c *multiConn) receive(conn *net.UDPConn) {
+	for {
+		buf := make([]byte, PFCP_MAX_UDP_LEN)
+		n, addr, err := conn.ReadFromUDP(buf)
+		p := multiConnPacket{data: buf[:n], err: err}
+		if err == nil {
+			p.addr = addr
+			c.learnRoute(addr, conn, time.Now())
+		}
+		select {
+		case c.in <- p:
+		case <-c.closed:
+			return
+		}
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+	}
+}
+
+func (c *multiConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		c.mu.Lock()
+		deadline, deadlineSet := c.readDeadline, c.deadlineSet
+		c.mu.Unlock()
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			d := time.Until(deadline)
+			if d <= 0 {
+				return 0, nil, c.opError("read", os.ErrDeadlineExceeded)
+			}
+			timer = time.NewTimer(d)
+			timeout = timer.C
+		}
+
+		var p multiConnPacket
+		received := true
+		select {
+		case p = <-c.in:
+		case <-c.closed:
+			p.err = c.opError("read", net.ErrClosed)
+		case <-timeout:
+			p.err = c.opError("read", os.ErrDeadlineExceeded)
+		case <-deadlineSet:
+			received = false
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if received {
+			return copy(b, p.data), p.addr, p.err
+		}
+	}
+}
+
+func (c *multiConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	return c.route(addr).WriteTo(b, addr)
+}
+
+// localAddrTo returns the address of the socket the datagrams to addr are
+// sent from.
+func (c *multiConn) localAddrTo(addr net.Addr) net.Addr {
+	return c.route(addr).LocalAddr()
+}
+
+// route returns the socket the datagrams to addr are sent from.
+func (c *multiConn) route(addr net.Addr) *net.UDPConn {
+	c.routesMu.Lock()
+	route, ok := c.routes[addr.String()]
+	c.routesMu.Unlock()
+	if ok && time.Since(route.seen) < routeTTL {
+		return route.conn
+	}
+	return c.familyRoute(addr)
+}
+
+// learnRoute records that addr was received from on conn at now. With
+// maxRoutes peers known, the expired routes are dropped, and else the oldest
+// one.
+func (c *multiConn) learnRoute(addr *net.UDPAddr, conn *net.UDPConn, now time.Time) {
+	key := addr.String()
+	c.routesMu.Lock()
+	defer c.routesMu.Unlock()
+	if _, ok := c.routes[key]; !ok && len(c.routes) >= maxRoutes {
+		var oldest string
+		for peer, route := range c.routes {
+			if now.Sub(route.seen) >= routeTTL {
+				delete(c.routes, peer)
+			} else if oldest == "" || route.seen.Before(c.routes[oldest].seen) {
+				oldest = peer
+			}
+		}
+		if len(c.routes) >= maxRoutes {
+			delete(c.routes, oldest)
+		}
+	}
+	c.routes[key] = multiConnRoute{conn: conn, seen: now}
+}
+
+// familyRoute returns the first socket of the address family of addr.
+func (c *multiConn) familyRoute(addr net.Addr) *net.UDPConn {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return c.conns[0]
+	}
+	isIPv4 := udpAddr.IP.To4() != nil
+	for _, conn := range c.conns {
+		local := conn.LocalAddr().(*net.UDPAddr)
+		if (local.IP.To4() != nil) == isIPv4 {
+			return conn
+		}
+	}
+	return c.conns[0]
+}
+
+func (c *multiConn) Close() error {
+	var errs []error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		for _, conn := range c.conns {
+			errs = append(errs, conn.Close())
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// LocalAddr returns the address of the first socket.
+func (c *multiConn) LocalAddr() net.Addr {
+	return c.conns[0].LocalAddr()
+}
+
+func (c *multiConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+func (c *multiConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	// Wake up the pending reads
+	close(c.deadlineSet)
+	c.deadlineSet = make(chan struct{})
+	return nil
+}
+
+func (c *multiConn) SetWriteDeadline(t time.Time) error {
+	var errs []error
+	for _, conn := range c.conns {
+		errs = append(errs, conn.SetWriteDeadline(t))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *multiConn) opError(op string, err error) error {
+	return &net.OpError{Op: op, Net: "udp", Addr: c.LocalAddr(), Err: err}
+}
@@ -0,0 +1,93 @@
+package pfcpgolb
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// listenLoopback returns a socket on the loopback address of network, closed
+// at the end of the test, or skips the test when there is none.
+func listenLoopback(t *testing.T, network string) *net.UDPConn {
+	t.Helper()
+	ip := net.IPv4(127, 0, 0, 1)
+	if network == "udp6" {
+		ip = net.IPv6loopback
+	}
+	conn, err := net.ListenUDP(network, &net.UDPAddr{IP: ip})
+	if err != nil {
+		t.Skipf("no %s loopback: %v", network, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestMultiConnRoute(t *testing.T) {
+	v4, v6 := listenLoopback(t, "udp4"), listenLoopback(t, "udp6")
+	other := listenLoopback(t, "udp4")
+	c := newMultiConn([]*net.UDPConn{v4, v6, other})
+	defer c.Close()
+
+	peer4 := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9}
+	peer6 := &net.UDPAddr{IP: net.IPv6loopback, Port: 9}
+	// By address family
+	if local := c.localAddrTo(peer4); local != v4.LocalAddr() {
+		t.Errorf("IPv4 peer routed through %s, want %s", local, v4.LocalAddr())
+	}
+	if local := c.localAddrTo(peer6); local != v6.LocalAddr() {
+		t.Errorf("IPv6 peer routed through %s, want %s", local, v6.LocalAddr())
+	}
+
+	// Through the socket receiving from the peer
+	sender := listenLoopback(t, "udp4")
+	if _, err := sender.WriteTo([]byte{0}, other.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := c.ReadFrom(make([]byte, PFCP_MAX_UDP_LEN)); err != nil {
+		t.Fatal(err)
+	}
+	if local := c.localAddrTo(sender.LocalAddr()); local != other.LocalAddr() {
+		t.Errorf("peer routed through %s, want %s", local, other.LocalAddr())
+	}
+
+	// Until the route expires
+	c.learnRoute(peer4, other, time.Now().Add(-routeTTL))
+	if local := c.localAddrTo(peer4); local != v4.LocalAddr() {
+		t.Errorf("IPv4 peer with an expired route routed through %s, want %s", local, v4.LocalAddr())
+	}
+}
+
+func TestMultiConnRoutesBounded(t *testing.T) {
+	conn := listenLoopback(t, "udp4")
+	c := newMultiConn([]*net.UDPConn{conn})
+	defer c.Close()
+
+	now := time.Now()
+	peer := func(i int) *net.UDPAddr {
+		return &net.UDPAddr{IP: net.IPv4(10, byte(i>>16), byte(i>>8), byte(i)), Port: 1}
+	}
+	for i := 0; i < maxRoutes; i++ {
+		c.learnRoute(peer(i), conn, now.Add(time.Duration(i)))
+	}
+	// The oldest route makes room
+	c.learnRoute(peer(maxRoutes), conn, now.Add(maxRoutes))
+	if len(c.routes) != maxRoutes {
+		t.Fatalf("%d routes, want %d", len(c.routes), maxRoutes)
+	}
+	if _, ok := c.routes[peer(0).String()]; ok {
+		t.Error("oldest route kept")
+	}
+
+	// The expired routes all go at once
+	later := now.Add(routeTTL + time.Duration(maxRoutes/2))
+	c.learnRoute(peer(maxRoutes+1), conn, later)
+	if n := len(c.routes); n != maxRoutes/2+1 {
+		t.Errorf("%d routes after expiry, want %d", n, maxRoutes/2+1)
+	}
+	for _, i := range []int{maxRoutes/2 + 1, maxRoutes + 1} {
+		if _, ok := c.routes[peer(i).String()]; !ok {
+			t.Errorf("route of peer %d dropped", i)
+		}
+	}
+}
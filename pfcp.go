@@ -1,8 +1,12 @@
 package pfcpgolb
 
+//go:generate go run ./cmd/tlvgen -output pfcp_tlv_gen.go pfcp.go pfcptype.go
+
 import (
     "net"
 	logger "github.com/sirupsen/logrus"
+
+	"github.com/Nikhil690/pfcpgolb/tlv"
 )
 
 type MessageType uint8
@@ -40,269 +44,426 @@ const (
 )
 
 type PFCPAssociationSetupRequest struct {
-    NodeID                         *NodeID                         `tlv:"60"`
-    RecoveryTimeStamp              *RecoveryTimeStamp              `tlv:"96"`
-    UPFunctionFeatures             *UPFunctionFeatures             `tlv:"43"`
-    CPFunctionFeatures             *CPFunctionFeatures             `tlv:"89"`
+    NodeID                         *NodeID                         `tlv:"60,mandatory"`
+    RecoveryTimeStamp              *RecoveryTimeStamp              `tlv:"96,mandatory"`
+    UPFunctionFeatures             *UPFunctionFeatures             `tlv:"43,conditional"`
+    CPFunctionFeatures             *CPFunctionFeatures             `tlv:"89,conditional"`
     UserPlaneIPResourceInformation *UserPlaneIPResourceInformation `tlv:"116"`
+    UnknownIEs                     []tlv.RawIE                     `tlv:"unknown"`
+    IEOrder                        tlv.Order                       `tlv:"order"`
 }
 
 
 type PFCPAssociationSetupResponse struct {
-    NodeID                         *NodeID                         `tlv:"60"`
-    Cause                          *Cause                          `tlv:"19"`
-    RecoveryTimeStamp              *RecoveryTimeStamp              `tlv:"96"`
-    UPFunctionFeatures             *UPFunctionFeatures             `tlv:"43"`
-    CPFunctionFeatures             *CPFunctionFeatures             `tlv:"89"`
+    NodeID                         *NodeID                         `tlv:"60,mandatory"`
+    Cause                          *Cause                          `tlv:"19,mandatory"`
+    RecoveryTimeStamp              *RecoveryTimeStamp              `tlv:"96,mandatory"`
+    UPFunctionFeatures             *UPFunctionFeatures             `tlv:"43,conditional"`
+    CPFunctionFeatures             *CPFunctionFeatures             `tlv:"89,conditional"`
+    UserPlaneIPResourceInformation *UserPlaneIPResourceInformation `tlv:"116"`
+    UnknownIEs                     []tlv.RawIE                     `tlv:"unknown"`
+    IEOrder                        tlv.Order                       `tlv:"order"`
+}
+
+type PFCPAssociationUpdateRequest struct {
+    NodeID                         *NodeID                         `tlv:"60,mandatory"`
+    UPFunctionFeatures             *UPFunctionFeatures             `tlv:"43,conditional"`
+    CPFunctionFeatures             *CPFunctionFeatures             `tlv:"89,conditional"`
     UserPlaneIPResourceInformation *UserPlaneIPResourceInformation `tlv:"116"`
+    UnknownIEs                     []tlv.RawIE                     `tlv:"unknown"`
+    IEOrder                        tlv.Order                       `tlv:"order"`
+}
+
+type PFCPAssociationUpdateResponse struct {
+    NodeID             *NodeID             `tlv:"60,mandatory"`
+    Cause              *Cause              `tlv:"19,mandatory"`
+    UPFunctionFeatures *UPFunctionFeatures `tlv:"43,conditional"`
+    CPFunctionFeatures *CPFunctionFeatures `tlv:"89,conditional"`
+    UnknownIEs         []tlv.RawIE         `tlv:"unknown"`
+    IEOrder            tlv.Order           `tlv:"order"`
 }
 
 type PFCPAssociationReleaseRequest struct {
-    NodeID *NodeID `tlv:"60"`
+    NodeID *NodeID `tlv:"60,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
 }
 
 type PFCPAssociationReleaseResponse struct {
-    NodeID *NodeID `tlv:"60"`
-    Cause  *Cause  `tlv:"19"`
+    NodeID *NodeID `tlv:"60,mandatory"`
+    Cause  *Cause  `tlv:"19,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
 }
 
 type CreatePDR struct {
-    PDRID                   *PacketDetectionRuleID   `tlv:"56"`
-    Precedence              *Precedence              `tlv:"29"`
-    PDI                     *PDI                              `tlv:"2"`
-    OuterHeaderRemoval      *OuterHeaderRemoval      `tlv:"95"`
-    FARID                   *FARID                   `tlv:"108"`
-    URRID                   []*URRID                 `tlv:"81"`
-    QERID                   []*QERID                 `tlv:"109"`
-    ActivatePredefinedRules *ActivatePredefinedRules `tlv:"106"`
+    PDRID                   *PacketDetectionRuleID   `tlv:"56,mandatory"`
+    Precedence              *Precedence              `tlv:"29,mandatory"`
+    PDI                     *PDI                              `tlv:"2,mandatory"`
+    OuterHeaderRemoval      *OuterHeaderRemoval      `tlv:"95,conditional"`
+    FARID                   *FARID                   `tlv:"108,conditional"`
+    URRID                   []*URRID                 `tlv:"81,conditional"`
+    QERID                   []*QERID                 `tlv:"109,conditional"`
+    ActivatePredefinedRules *ActivatePredefinedRules `tlv:"106,conditional"`
+    UnknownIEs              []tlv.RawIE              `tlv:"unknown"`
+    IEOrder                 tlv.Order                `tlv:"order"`
 }
 
 type PDI struct {
-    SourceInterface               *SourceInterface               `tlv:"20"`
-    LocalFTEID                    *FTEID                         `tlv:"21"`
-    NetworkInstance               *NetworkInstance               `tlv:"22"`
-    UEIPAddress                   *UEIPAddress                   `tlv:"93"`
-    TrafficEndpointID             *TrafficEndpointID             `tlv:"131"`
-    SDFFilter                     *SDFFilter                     `tlv:"23"`
-    ApplicationID                 *ApplicationID                 `tlv:"24"`
-    EthernetPDUSessionInformation *EthernetPDUSessionInformation `tlv:"142"`
-    EthernetPacketFilter          *EthernetPacketFilter                   `tlv:"132"`
-    QFI                           []*QFI                         `tlv:"124"`
-    FramedRoute                   *FramedRoute                   `tlv:"153"`
-    FramedRouting                 *FramedRouting                 `tlv:"154"`
-    FramedIPv6Route               *FramedIPv6Route               `tlv:"155"`
+    SourceInterface               *SourceInterface               `tlv:"20,mandatory"`
+    LocalFTEID                    *FTEID                         `tlv:"21,conditional"`
+    NetworkInstance               *NetworkInstance               `tlv:"22,conditional"`
+    UEIPAddress                   *UEIPAddress                   `tlv:"93,conditional"`
+    TrafficEndpointID             *TrafficEndpointID             `tlv:"131,conditional"`
+    SDFFilter                     *SDFFilter                     `tlv:"23,conditional"`
+    ApplicationID                 *ApplicationID                 `tlv:"24,conditional"`
+    EthernetPDUSessionInformation *EthernetPDUSessionInformation `tlv:"142,conditional"`
+    EthernetPacketFilter          *EthernetPacketFilter                   `tlv:"132,conditional"`
+    QFI                           []*QFI                         `tlv:"124,conditional"`
+    FramedRoute                   *FramedRoute                   `tlv:"153,conditional"`
+    FramedRouting                 *FramedRouting                 `tlv:"154,conditional"`
+    FramedIPv6Route               *FramedIPv6Route               `tlv:"155,conditional"`
+    UnknownIEs                    []tlv.RawIE                    `tlv:"unknown"`
+    IEOrder                       tlv.Order                      `tlv:"order"`
 }
 
 type CreateFAR struct {
-    FARID                 *FARID                 `tlv:"108"`
-    ApplyAction           *ApplyAction           `tlv:"44"`
-    ForwardingParameters  *ForwardingParametersIEInFAR    `tlv:"4"`
-    DuplicatingParameters *DuplicatingParameters `tlv:"5"`
-    BARID                 *BARID                 `tlv:"88"`
+    FARID                 *FARID                 `tlv:"108,mandatory"`
+    ApplyAction           *ApplyAction           `tlv:"44,mandatory"`
+    ForwardingParameters  *ForwardingParametersIEInFAR    `tlv:"4,conditional"`
+    DuplicatingParameters *DuplicatingParameters `tlv:"5,conditional"`
+    BARID                 *BARID                 `tlv:"88,conditional"`
+    UnknownIEs            []tlv.RawIE            `tlv:"unknown"`
+    IEOrder               tlv.Order              `tlv:"order"`
 }
 
 type ForwardingParametersIEInFAR struct {
-    DestinationInterface    *DestinationInterface  `tlv:"42"`
-    NetworkInstance         *NetworkInstance       `tlv:"22"`
-    RedirectInformation     *RedirectInformation   `tlv:"38"`
-    OuterHeaderCreation     *OuterHeaderCreation   `tlv:"84"`
-    TransportLevelMarking   *TransportLevelMarking `tlv:"30"`
-    ForwardingPolicy        *ForwardingPolicy      `tlv:"41"`
-    HeaderEnrichment        *HeaderEnrichment      `tlv:"98"`
-    LinkedTrafficEndpointID *TrafficEndpointID     `tlv:"131"`
-    Proxying                *Proxying              `tlv:"137"`
+    DestinationInterface    *DestinationInterface  `tlv:"42,mandatory"`
+    NetworkInstance         *NetworkInstance       `tlv:"22,conditional"`
+    RedirectInformation     *RedirectInformation   `tlv:"38,conditional"`
+    OuterHeaderCreation     *OuterHeaderCreation   `tlv:"84,conditional"`
+    TransportLevelMarking   *TransportLevelMarking `tlv:"30,conditional"`
+    ForwardingPolicy        *ForwardingPolicy      `tlv:"41,conditional"`
+    HeaderEnrichment        *HeaderEnrichment      `tlv:"98,conditional"`
+    LinkedTrafficEndpointID *TrafficEndpointID     `tlv:"131,conditional"`
+    Proxying                *Proxying              `tlv:"137,conditional"`
+    UnknownIEs              []tlv.RawIE            `tlv:"unknown"`
+    IEOrder                 tlv.Order              `tlv:"order"`
 }
 
 type CreateQER struct {
-    QERID              *QERID              `tlv:"109"`
-    QERCorrelationID   *QERCorrelationID   `tlv:"28"`
-    GateStatus         *GateStatus         `tlv:"25"`
-    MaximumBitrate     *MBR                `tlv:"26"`
-    GuaranteedBitrate  *GBR                `tlv:"27"`
-    PacketRate         *PacketRate         `tlv:"94"`
-    DLFlowLevelMarking *DLFlowLevelMarking `tlv:"97"`
-    QoSFlowIdentifier  *QFI                `tlv:"124"`
-    ReflectiveQoS      *RQI                `tlv:"123"`
+    QERID              *QERID              `tlv:"109,mandatory"`
+    QERCorrelationID   *QERCorrelationID   `tlv:"28,conditional"`
+    GateStatus         *GateStatus         `tlv:"25,mandatory"`
+    MaximumBitrate     *MBR                `tlv:"26,conditional"`
+    GuaranteedBitrate  *GBR                `tlv:"27,conditional"`
+    PacketRate         *PacketRate         `tlv:"94,conditional"`
+    DLFlowLevelMarking *DLFlowLevelMarking `tlv:"97,conditional"`
+    QoSFlowIdentifier  *QFI                `tlv:"124,conditional"`
+    ReflectiveQoS      *RQI                `tlv:"123,conditional"`
+    UnknownIEs         []tlv.RawIE         `tlv:"unknown"`
+    IEOrder            tlv.Order           `tlv:"order"`
 }
 
 type UpdatePDR struct {
-    PDRID                     *PacketDetectionRuleID     `tlv:"56"`
-    OuterHeaderRemoval        *OuterHeaderRemoval        `tlv:"95"`
-    Precedence                *Precedence                `tlv:"29"`
-    PDI                       *PDI                                `tlv:"2"`
-    FARID                     *FARID                     `tlv:"108"`
-    URRID                     []*URRID                   `tlv:"81"`
-    QERID                     []*QERID                   `tlv:"109"`
-    ActivatePredefinedRules   *ActivatePredefinedRules   `tlv:"106"`
-    DeactivatePredefinedRules *DeactivatePredefinedRules `tlv:"107"`
+    PDRID                     *PacketDetectionRuleID     `tlv:"56,mandatory"`
+    OuterHeaderRemoval        *OuterHeaderRemoval        `tlv:"95,conditional"`
+    Precedence                *Precedence                `tlv:"29,conditional"`
+    PDI                       *PDI                                `tlv:"2,conditional"`
+    FARID                     *FARID                     `tlv:"108,conditional"`
+    URRID                     []*URRID                   `tlv:"81,conditional"`
+    QERID                     []*QERID                   `tlv:"109,conditional"`
+    ActivatePredefinedRules   *ActivatePredefinedRules   `tlv:"106,conditional"`
+    DeactivatePredefinedRules *DeactivatePredefinedRules `tlv:"107,conditional"`
+    UnknownIEs                []tlv.RawIE                `tlv:"unknown"`
+    IEOrder                   tlv.Order                  `tlv:"order"`
 }
 
 type UpdateFAR struct {
-    FARID                       *FARID                       `tlv:"108"`
-    ApplyAction                 *ApplyAction                 `tlv:"44"`
-    UpdateForwardingParameters  *UpdateForwardingParametersIEInFAR    `tlv:"11"`
-    UpdateDuplicatingParameters *UpdateDuplicatingParameters `tlv:"105"`
-    BARID                       *BARID                       `tlv:"88"`
+    FARID                       *FARID                       `tlv:"108,mandatory"`
+    ApplyAction                 *ApplyAction                 `tlv:"44,conditional"`
+    UpdateForwardingParameters  *UpdateForwardingParametersIEInFAR    `tlv:"11,conditional"`
+    UpdateDuplicatingParameters *UpdateDuplicatingParameters `tlv:"105,conditional"`
+    BARID                       *BARID                       `tlv:"88,conditional"`
+    UnknownIEs                  []tlv.RawIE                  `tlv:"unknown"`
+    IEOrder                     tlv.Order                    `tlv:"order"`
 }
 
 type UpdateForwardingParametersIEInFAR struct {
-    DestinationInterface    *DestinationInterface  `tlv:"42"`
-    NetworkInstance         *NetworkInstance       `tlv:"22"`
-    RedirectInformation     *RedirectInformation   `tlv:"38"`
-    OuterHeaderCreation     *OuterHeaderCreation   `tlv:"84"`
-    TransportLevelMarking   *TransportLevelMarking `tlv:"30"`
-    ForwardingPolicy        *ForwardingPolicy      `tlv:"41"`
-    HeaderEnrichment        *HeaderEnrichment      `tlv:"98"`
-    PFCPSMReqFlags          *PFCPSMReqFlags        `tlv:"49"`
-    LinkedTrafficEndpointID *TrafficEndpointID     `tlv:"131"`
+    DestinationInterface    *DestinationInterface  `tlv:"42,conditional"`
+    NetworkInstance         *NetworkInstance       `tlv:"22,conditional"`
+    RedirectInformation     *RedirectInformation   `tlv:"38,conditional"`
+    OuterHeaderCreation     *OuterHeaderCreation   `tlv:"84,conditional"`
+    TransportLevelMarking   *TransportLevelMarking `tlv:"30,conditional"`
+    ForwardingPolicy        *ForwardingPolicy      `tlv:"41,conditional"`
+    HeaderEnrichment        *HeaderEnrichment      `tlv:"98,conditional"`
+    PFCPSMReqFlags          *PFCPSMReqFlags        `tlv:"49,conditional"`
+    LinkedTrafficEndpointID *TrafficEndpointID     `tlv:"131,conditional"`
+    UnknownIEs              []tlv.RawIE            `tlv:"unknown"`
+    IEOrder                 tlv.Order              `tlv:"order"`
 }
 
 type CreateTrafficEndpoint struct {
-	TrafficEndpointID             *TrafficEndpointID             `tlv:"131"`
-	LocalFTEID                    *FTEID                         `tlv:"21"`
+	TrafficEndpointID             *TrafficEndpointID             `tlv:"131,mandatory"`
+	LocalFTEID                    *FTEID                         `tlv:"21,conditional"`
 	NetworkInstance               *NetworkInstance               `tlv:"22"`
 	UEIPAddress                   *UEIPAddress                   `tlv:"93"`
 	EthernetPDUSessionInformation *EthernetPDUSessionInformation `tlv:"142"`
 	FramedRoute                   *FramedRoute                   `tlv:"153"`
 	FramedRouting                 *FramedRouting                 `tlv:"154"`
 	FramedIPv6Route               *FramedIPv6Route               `tlv:"155"`
+	UnknownIEs                    []tlv.RawIE                    `tlv:"unknown"`
+	IEOrder                       tlv.Order                      `tlv:"order"`
 }
 
 type PFCPSessionEstablishmentRequest struct {
-    NodeID                   *NodeID                   `tlv:"60"`
-    CPFSEID                  *FSEID                    `tlv:"57"`
-    CreatePDR                []*CreatePDR                       `tlv:"1"`
-    CreateFAR                []*CreateFAR                       `tlv:"3"`
-    CreateQER                []*CreateQER                       `tlv:"7"`
-    CreateTrafficEndpoint    *CreateTrafficEndpoint             `tlv:"127"`
-    PDNType                  *PDNType                  `tlv:"113"`
+    NodeID                   *NodeID                   `tlv:"60,mandatory"`
+    CPFSEID                  *FSEID                    `tlv:"57,mandatory"`
+    CreatePDR                []*CreatePDR                       `tlv:"1,mandatory"`
+    CreateFAR                []*CreateFAR                       `tlv:"3,mandatory"`
+    CreateURR                []*CreateURR                       `tlv:"6,conditional"`
+    CreateQER                []*CreateQER                       `tlv:"7,conditional"`
+    CreateBAR                *CreateBAR                         `tlv:"85,conditional"`
+    CreateTrafficEndpoint    *CreateTrafficEndpoint             `tlv:"127,conditional"`
+    PDNType                  *PDNType                  `tlv:"113,conditional"`
     UserPlaneInactivityTimer *UserPlaneInactivityTimer `tlv:"117"`
     UserID                   *UserID                   `tlv:"141"`
     TraceInformation         *TraceInformation         `tlv:"152"`
+    UnknownIEs               []tlv.RawIE               `tlv:"unknown"`
+    IEOrder                  tlv.Order                 `tlv:"order"`
 }
 
 type LoadControlInformation struct {
-    LoadControlSequenceNumber *SequenceNumber `tlv:"52"`
+    LoadControlSequenceNumber *SequenceNumber `tlv:"52,mandatory"`
+    UnknownIEs                []tlv.RawIE     `tlv:"unknown"`
+    IEOrder                   tlv.Order       `tlv:"order"`
 }
 
 type CreatedTrafficEndpoint struct {
-    TrafficEndpointID *TrafficEndpointID `tlv:"131"`
-    LocalFTEID        *FTEID             `tlv:"21"`
+    TrafficEndpointID *TrafficEndpointID `tlv:"131,mandatory"`
+    LocalFTEID        *FTEID             `tlv:"21,conditional"`
+    UnknownIEs        []tlv.RawIE        `tlv:"unknown"`
+    IEOrder           tlv.Order          `tlv:"order"`
 }
 
 
 type PFCPSessionEstablishmentResponse struct {
-    NodeID                     *NodeID            `tlv:"60"`
-    Cause                      *Cause             `tlv:"19"`
-    OffendingIE                *OffendingIE       `tlv:"40"`
-    UPFSEID                    *FSEID             `tlv:"57"`
-    CreatedPDR                 *CreatedPDR                 `tlv:"8"`
+    NodeID                     *NodeID            `tlv:"60,mandatory"`
+    Cause                      *Cause             `tlv:"19,mandatory"`
+    OffendingIE                *OffendingIE       `tlv:"40,conditional"`
+    UPFSEID                    *FSEID             `tlv:"57,conditional"`
+    CreatedPDR                 *CreatedPDR                 `tlv:"8,conditional"`
     LoadControlInformation     *LoadControlInformation     `tlv:"51"`
-    FailedRuleID               *FailedRuleID      `tlv:"114"`
-    CreatedTrafficEndpoint     *CreatedTrafficEndpoint     `tlv:"128"`
+    FailedRuleID               *FailedRuleID      `tlv:"114,conditional"`
+    CreatedTrafficEndpoint     *CreatedTrafficEndpoint     `tlv:"128,conditional"`
+    UnknownIEs                 []tlv.RawIE                 `tlv:"unknown"`
+    IEOrder                    tlv.Order                   `tlv:"order"`
 }
 
 type CreatedPDR struct {
-    PDRID      *PacketDetectionRuleID `tlv:"56"`
-    LocalFTEID *FTEID                 `tlv:"21"`
+    PDRID      *PacketDetectionRuleID `tlv:"56,mandatory"`
+    LocalFTEID *FTEID                 `tlv:"21,conditional"`
+    UnknownIEs []tlv.RawIE            `tlv:"unknown"`
+    IEOrder    tlv.Order              `tlv:"order"`
 }
 
 type RemoveTrafficEndpoint struct {
-    TrafficEndpointID *TrafficEndpointID `tlv:"131"`
+    TrafficEndpointID *TrafficEndpointID `tlv:"131,mandatory"`
+    UnknownIEs        []tlv.RawIE        `tlv:"unknown"`
+    IEOrder           tlv.Order          `tlv:"order"`
 }
 
 type UpdateQER struct {
-	QERID              *QERID              `tlv:"109"`
-	QERCorrelationID   *QERCorrelationID   `tlv:"28"`
-	GateStatus         *GateStatus         `tlv:"25"`
-	MaximumBitrate     *MBR                `tlv:"26"`
-	GuaranteedBitrate  *GBR                `tlv:"27"`
-	PacketRate         *PacketRate         `tlv:"94"`
-	DLFlowLevelMarking *DLFlowLevelMarking `tlv:"97"`
-	QoSFlowIdentifier  *QFI                `tlv:"124"`
-	ReflectiveQoS      *RQI                `tlv:"123"`
+	QERID              *QERID              `tlv:"109,mandatory"`
+	QERCorrelationID   *QERCorrelationID   `tlv:"28,conditional"`
+	GateStatus         *GateStatus         `tlv:"25,conditional"`
+	MaximumBitrate     *MBR                `tlv:"26,conditional"`
+	GuaranteedBitrate  *GBR                `tlv:"27,conditional"`
+	PacketRate         *PacketRate         `tlv:"94,conditional"`
+	DLFlowLevelMarking *DLFlowLevelMarking `tlv:"97,conditional"`
+	QoSFlowIdentifier  *QFI                `tlv:"124,conditional"`
+	ReflectiveQoS      *RQI                `tlv:"123,conditional"`
+	UnknownIEs         []tlv.RawIE         `tlv:"unknown"`
+	IEOrder            tlv.Order           `tlv:"order"`
 }
 
 type UpdateTrafficEndpoint struct {
-    TrafficEndpointID *TrafficEndpointID `tlv:"131"`
-    LocalFTEID        *FTEID             `tlv:"21"`
-    NetworkInstance   *NetworkInstance   `tlv:"22"`
-    UEIPAddress       *UEIPAddress       `tlv:"93"`
-    FramedRoute       *FramedRoute       `tlv:"153"`
-    FramedRouting     *FramedRouting     `tlv:"154"`
-    FramedIPv6Route   *FramedIPv6Route   `tlv:"155"`
+    TrafficEndpointID *TrafficEndpointID `tlv:"131,mandatory"`
+    LocalFTEID        *FTEID             `tlv:"21,conditional"`
+    NetworkInstance   *NetworkInstance   `tlv:"22,conditional"`
+    UEIPAddress       *UEIPAddress       `tlv:"93,conditional"`
+    FramedRoute       *FramedRoute       `tlv:"153,conditional"`
+    FramedRouting     *FramedRouting     `tlv:"154,conditional"`
+    FramedIPv6Route   *FramedIPv6Route   `tlv:"155,conditional"`
+    UnknownIEs        []tlv.RawIE        `tlv:"unknown"`
+    IEOrder           tlv.Order          `tlv:"order"`
 }
 
 
 type PFCPSessionModificationRequest struct {
-    CPFSEID                  *FSEID                          `tlv:"57"`
-    RemovePDR                []*RemovePDR                             `tlv:"15"`
-    RemoveFAR                []*RemoveFAR                             `tlv:"16"`
-    // RemoveURR                []*RemoveURR                             `tlv:"17"`
-    // RemoveQER                []*RemoveQER                             `tlv:"18"`
-    // RemoveBAR                []*RemoveBAR                             `tlv:"87"`
-    RemoveTrafficEndpoint    *RemoveTrafficEndpoint                   `tlv:"130"`
-    CreatePDR                []*CreatePDR                             `tlv:"1"`
-    CreateFAR                []*CreateFAR                             `tlv:"3"`
-    // CreateURR                []*CreateURR                             `tlv:"6"`
-    CreateQER                []*CreateQER                             `tlv:"7"`
-    // CreateBAR                []*CreateBAR                             `tlv:"85"`
-    CreateTrafficEndpoint    *CreateTrafficEndpoint                   `tlv:"127"`
-    UpdatePDR                []*UpdatePDR                             `tlv:"9"`
-    UpdateFAR                []*UpdateFAR                             `tlv:"10"`
-    // UpdateURR                []*UpdateURR                             `tlv:"13"`
-    UpdateQER                []*UpdateQER                             `tlv:"14"`
-    // UpdateBAR                *UpdateBARPFCPSessionModificationRequest `tlv:"86"`
-    UpdateTrafficEndpoint    *UpdateTrafficEndpoint                   `tlv:"129"`
-    PFCPSMReqFlags           *PFCPSMReqFlags                 `tlv:"49"`
+    CPFSEID                  *FSEID                          `tlv:"57,conditional"`
+    RemovePDR                []*RemovePDR                             `tlv:"15,conditional"`
+    RemoveFAR                []*RemoveFAR                             `tlv:"16,conditional"`
+    RemoveURR                []*RemoveURR                             `tlv:"17,conditional"`
+    RemoveQER                []*RemoveQER                             `tlv:"18,conditional"`
+    RemoveBAR                *RemoveBAR                               `tlv:"87,conditional"`
+    RemoveTrafficEndpoint    *RemoveTrafficEndpoint                   `tlv:"130,conditional"`
+    CreatePDR                []*CreatePDR                             `tlv:"1,conditional"`
+    CreateFAR                []*CreateFAR                             `tlv:"3,conditional"`
+    CreateURR                []*CreateURR                             `tlv:"6,conditional"`
+    CreateQER                []*CreateQER                             `tlv:"7,conditional"`
+    CreateBAR                *CreateBAR                               `tlv:"85,conditional"`
+    CreateTrafficEndpoint    *CreateTrafficEndpoint                   `tlv:"127,conditional"`
+    UpdatePDR                []*UpdatePDR                             `tlv:"9,conditional"`
+    UpdateFAR                []*UpdateFAR                             `tlv:"10,conditional"`
+    UpdateURR                []*UpdateURR                             `tlv:"13,conditional"`
+    UpdateQER                []*UpdateQER                             `tlv:"14,conditional"`
+    UpdateBAR                *UpdateBARPFCPSessionModificationRequest `tlv:"86,conditional"`
+    UpdateTrafficEndpoint    *UpdateTrafficEndpoint                   `tlv:"129,conditional"`
+    PFCPSMReqFlags           *PFCPSMReqFlags                 `tlv:"49,conditional"`
     // QueryURR                 []*QueryURR                              `tlv:"77"`
     UserPlaneInactivityTimer *UserPlaneInactivityTimer       `tlv:"117"`
     // QueryURRReference        *QueryURRReference              `tlv:"125"`
     TraceInformation         *TraceInformation               `tlv:"152"`
+    UnknownIEs               []tlv.RawIE                     `tlv:"unknown"`
+    IEOrder                  tlv.Order                       `tlv:"order"`
 }
 
 type RemovePDR struct {
-    PDRID *PacketDetectionRuleID `tlv:"56"`
+    PDRID *PacketDetectionRuleID `tlv:"56,mandatory"`
+    UnknownIEs []tlv.RawIE       `tlv:"unknown"`
+    IEOrder    tlv.Order         `tlv:"order"`
 }
 
 
 type RemoveFAR struct {
-    FARID *FARID `tlv:"108"`
+    FARID *FARID `tlv:"108,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
+}
+
+type RemoveURR struct {
+    URRID      *URRID      `tlv:"81,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
+}
+
+type RemoveQER struct {
+    QERID      *QERID      `tlv:"109,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
+}
+
+type RemoveBAR struct {
+    BARID      *BARID      `tlv:"88,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
+}
+
+// CreateURR keeps the IEs of the URR other than its ID undecoded.
+type CreateURR struct {
+    URRID      *URRID      `tlv:"81,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
+}
+
+// UpdateURR keeps the IEs of the URR other than its ID undecoded.
+type UpdateURR struct {
+    URRID      *URRID      `tlv:"81,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
+}
+
+// CreateBAR keeps the IEs of the BAR other than its ID undecoded.
+type CreateBAR struct {
+    BARID      *BARID      `tlv:"88,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
+}
+
+// UpdateBARPFCPSessionModificationRequest keeps the IEs of the BAR other than
+// its ID undecoded.
+type UpdateBARPFCPSessionModificationRequest struct {
+    BARID      *BARID      `tlv:"88,mandatory"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
 }
 
 type PFCPSessionModificationResponse struct {
-    Cause                             *Cause                               `tlv:"19"`
-    OffendingIE                       *OffendingIE                         `tlv:"40"`
-    CreatedPDR                        *CreatedPDR                                   `tlv:"8"`
+    Cause                             *Cause                               `tlv:"19,mandatory"`
+    OffendingIE                       *OffendingIE                         `tlv:"40,conditional"`
+    CreatedPDR                        *CreatedPDR                                   `tlv:"8,conditional"`
     LoadControlInformation            *LoadControlInformation                       `tlv:"51"`
     // OverloadControlInformation        *OverloadControlInformation                   `tlv:"54"`
     // UsageReport                       []*UsageReportPFCPSessionModificationResponse `tlv:"78"`
-    FailedRuleID                      *FailedRuleID                        `tlv:"114"`
+    FailedRuleID                      *FailedRuleID                        `tlv:"114,conditional"`
     // AdditionalUsageReportsInformation *AdditionalUsageReportsInformation   `tlv:"126"`
-    CreatedUpdatedTrafficEndpoint     *CreatedTrafficEndpoint                       `tlv:"128"`
+    CreatedUpdatedTrafficEndpoint     *CreatedTrafficEndpoint                       `tlv:"128,conditional"`
+    UnknownIEs                        []tlv.RawIE                                   `tlv:"unknown"`
+    IEOrder                           tlv.Order                                     `tlv:"order"`
 }
 
-type PFCPSessionDeletionRequest struct{}
+type PFCPSessionDeletionRequest struct {
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
+}
 
 type PFCPSessionDeletionResponse struct {
-    Cause                      *Cause                           `tlv:"19"`
-    OffendingIE                *OffendingIE                     `tlv:"40"`
+    Cause                      *Cause                           `tlv:"19,mandatory"`
+    OffendingIE                *OffendingIE                     `tlv:"40,conditional"`
     LoadControlInformation     *LoadControlInformation                   `tlv:"51"`
     // OverloadControlInformation *OverloadControlInformation               `tlv:"54"`
     // UsageReport                []*UsageReportPFCPSessionDeletionResponse `tlv:"79"`
+    UnknownIEs []tlv.RawIE `tlv:"unknown"`
+    IEOrder    tlv.Order   `tlv:"order"`
 }
 
 type HeartbeatRequest struct {
-    RecoveryTimeStamp *RecoveryTimeStamp `tlv:"96"`
+    RecoveryTimeStamp *RecoveryTimeStamp `tlv:"96,mandatory"`
+    UnknownIEs        []tlv.RawIE        `tlv:"unknown"`
+    IEOrder           tlv.Order          `tlv:"order"`
 }
 
 type HeartbeatResponse struct {
-    RecoveryTimeStamp *RecoveryTimeStamp `tlv:"96"`
+    RecoveryTimeStamp *RecoveryTimeStamp `tlv:"96,mandatory"`
+    UnknownIEs        []tlv.RawIE        `tlv:"unknown"`
+    IEOrder           tlv.Order          `tlv:"order"`
+}
+
+type PFCPPFDManagementResponse struct {
+    Cause       *Cause       `tlv:"19,mandatory"`
+    OffendingIE *OffendingIE `tlv:"40,conditional"`
+    UnknownIEs  []tlv.RawIE  `tlv:"unknown"`
+    IEOrder     tlv.Order    `tlv:"order"`
+}
+
+type PFCPNodeReportResponse struct {
+    NodeID      *NodeID      `tlv:"60,mandatory"`
+    Cause       *Cause       `tlv:"19,mandatory"`
+    OffendingIE *OffendingIE `tlv:"40,conditional"`
+    UnknownIEs  []tlv.RawIE  `tlv:"unknown"`
+    IEOrder     tlv.Order    `tlv:"order"`
+}
+
+type PFCPSessionSetDeletionResponse struct {
+    NodeID      *NodeID      `tlv:"60,mandatory"`
+    Cause       *Cause       `tlv:"19,mandatory"`
+    OffendingIE *OffendingIE `tlv:"40,conditional"`
+    UnknownIEs  []tlv.RawIE  `tlv:"unknown"`
+    IEOrder     tlv.Order    `tlv:"order"`
+}
+
+type PFCPSessionReportResponse struct {
+    Cause       *Cause       `tlv:"19,mandatory"`
+    OffendingIE *OffendingIE `tlv:"40,conditional"`
+    UnknownIEs  []tlv.RawIE  `tlv:"unknown"`
+    IEOrder     tlv.Order    `tlv:"order"`
 }
 
 
 type Header struct {
     Version         uint8
+    FO              uint8
     MP              uint8
     S               uint8
     MessageType     MessageType
@@ -0,0 +1,209 @@
+package pfcpgolb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+)
+
+// Future is the pending response to a request sent by SendRequestAsync.
+type Future struct {
+	done chan struct{}
+	res  *Message
+	err  error
+}
+
+// Done is closed once the response arrived or the request failed.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the response to the request.
+func (f *Future) Result() (*Message, error) {
+	<-f.done
+	return f.res, f.err
+}
+
+type asyncRequest struct {
+	ctx      context.Context
+	reqMsg   *PFCPMessage
+	addr     *net.UDPAddr
+	future   *Future
+	callback func(*Message, error)
+	// stopQueued stops the removal of the request from the queue of its
+	// window once ctx is done
+	stopQueued func() bool
+}
+
+// peerWindow holds the requests in flight to a peer and the ones waiting for
+// room in the window.
+type peerWindow struct {
+	inFlight int
+	queue    []*asyncRequest
+}
+
+// SendRequestAsync sends reqMsg to addr without waiting for its response. The
+// response or the failure of the request completes the returned Future and,
+// when callback is not nil, is passed to callback. callback must not block:
+// it runs on the goroutine receiving the response or retransmitting the
+// requests.
+//
+// With Config.MaxInFlightPerPeer, the requests beyond that number of requests
+// waiting for their response from addr are queued, and sent in order as
+// responses arrive. A queued request fails as soon as its ctx is done.
+func (pfcpServer *PfcpServer) SendRequestAsync(ctx context.Context, reqMsg *PFCPMessage, addr *net.UDPAddr,
+	callback func(*Message, error),
+) *Future {
+	req := &asyncRequest{
+		ctx:      ctx,
+		reqMsg:   reqMsg,
+		addr:     addr,
+		future:   &Future{done: make(chan struct{})},
+		callback: callback,
+	}
+	if !reqMsg.IsRequest() {
+		req.complete(nil, errors.New("not a request message"))
+		return req.future
+	}
+
+	limit := pfcpServer.Config.MaxInFlightPerPeer
+	if limit <= 0 {
+		pfcpServer.startAsync(req, false)
+		return req.future
+	}
+
+	key := addr.String()
+	pfcpServer.windowMu.Lock()
+	if pfcpServer.windows == nil {
+		pfcpServer.windows = make(map[string]*peerWindow)
+	}
+	window, ok := pfcpServer.windows[key]
+	if !ok {
+		window = &peerWindow{}
+		pfcpServer.windows[key] = window
+	}
+	if window.inFlight >= limit {
+		window.queue = append(window.queue, req)
+		req.stopQueued = context.AfterFunc(ctx, func() { pfcpServer.dequeue(req) })
+		pfcpServer.windowMu.Unlock()
+		return req.future
+	}
+	window.inFlight++
+	pfcpServer.windowMu.Unlock()
+
+	pfcpServer.startAsync(req, true)
+	return req.future
+}
+
+// startAsync sends req, or the next request queued in the window of its peer
+// when req can't be sent.
+func (pfcpServer *PfcpServer) startAsync(req *asyncRequest, windowed bool) {
+	for req != nil {
+		err := pfcpServer.sendAsync(req, windowed)
+		if err == nil {
+			return
+		}
+		if !windowed {
+			req.complete(nil, err)
+			return
+		}
+		next := pfcpServer.nextInWindow(req.addr.String())
+		req.complete(nil, err)
+		req = next
+	}
+}
+
+// sendAsync starts the transaction of req, which starts the next request of
+// the window when it ends.
+func (pfcpServer *PfcpServer) sendAsync(req *asyncRequest, windowed bool) error {
+	if err := req.ctx.Err(); err != nil {
+		return err
+	}
+	var stop func() bool
+	stopSet := make(chan struct{})
+	tx, err := pfcpServer.newRequestTransaction(req.ctx, req.reqMsg, req.addr, func(resMsg *Message, err error) {
+		<-stopSet
+		stop()
+		if !windowed {
+			req.complete(resMsg, err)
+			return
+		}
+		// The slot is passed on or freed before the request completes
+		next := pfcpServer.nextInWindow(req.addr.String())
+		req.complete(resMsg, err)
+		pfcpServer.startAsync(next, true)
+	})
+	if err != nil {
+		return err
+	}
+	stop = context.AfterFunc(req.ctx, func() {
+		tx.finish(nil, fmt.Errorf("Request Transaction [%d]: %w", tx.SequenceNumber, req.ctx.Err()))
+	})
+	close(stopSet)
+	pfcpServer.startRequest(tx)
+	return nil
+}
+
+// nextInWindow returns the next queued request to send to a peer, or frees
+// the slot of the request that ended.
+func (pfcpServer *PfcpServer) nextInWindow(key string) *asyncRequest {
+	var expired []*asyncRequest
+	pfcpServer.windowMu.Lock()
+	window := pfcpServer.windows[key]
+	var next *asyncRequest
+	for next == nil && len(window.queue) > 0 {
+		req := window.queue[0]
+		window.queue[0] = nil
+		window.queue = window.queue[1:]
+		req.stopQueued()
+		if req.ctx.Err() != nil {
+			// Given up while queued, its removal pending
+			expired = append(expired, req)
+			continue
+		}
+		next = req
+	}
+	if next == nil {
+		window.inFlight--
+		if window.inFlight == 0 {
+			delete(pfcpServer.windows, key)
+		}
+	}
+	pfcpServer.windowMu.Unlock()
+
+	// Out of the lock, the callbacks may send requests
+	for _, req := range expired {
+		req.complete(nil, req.ctx.Err())
+	}
+	return next
+}
+
+// dequeue removes req, given up while queued, from the window of its peer.
+func (pfcpServer *PfcpServer) dequeue(req *asyncRequest) {
+	pfcpServer.windowMu.Lock()
+	window := pfcpServer.windows[req.addr.String()]
+	i := -1
+	if window != nil {
+		i = slices.Index(window.queue, req)
+	}
+	if i >= 0 {
+		copy(window.queue[i:], window.queue[i+1:])
+		window.queue[len(window.queue)-1] = nil
+		window.queue = window.queue[:len(window.queue)-1]
+	}
+	pfcpServer.windowMu.Unlock()
+	// Otherwise taken out of the queue by nextInWindow
+	if i >= 0 {
+		req.complete(nil, req.ctx.Err())
+	}
+}
+
+func (req *asyncRequest) complete(res *Message, err error) {
+	req.future.res, req.future.err = res, err
+	close(req.future.done)
+	if req.callback != nil {
+		req.callback(res, err)
+	}
+}
@@ -0,0 +1,217 @@
+package pfcpgolb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// blockingPeer is a UP function server holding the heartbeat requests it
+// receives until released, one per value sent on release.
+type blockingPeer struct {
+	arrived chan int64
+	release chan struct{}
+	stopped chan struct{}
+}
+
+func newBlockingPeer(t *testing.T, server *PfcpServer) *blockingPeer {
+	t.Helper()
+	p := &blockingPeer{arrived: make(chan int64, 16), release: make(chan struct{}), stopped: make(chan struct{})}
+	server.Config.Workers = 16
+	serve(t, server, HandlerFunc(func(w ResponseWriter, req *Message) {
+		p.arrived <- req.PfcpMessage.Body.(HeartbeatRequest).RecoveryTimeStamp.RecoveryTimeStamp.Unix()
+		select {
+		case <-p.release:
+		case <-p.stopped:
+			return
+		}
+		w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: server.RecoveryTimeStamp}})
+	}))
+	// Let Serve return at the end of a failed test
+	t.Cleanup(func() { close(p.stopped) })
+	return p
+}
+
+// next returns the number of the next request arrived.
+func (p *blockingPeer) next(t *testing.T) int64 {
+	t.Helper()
+	select {
+	case i := <-p.arrived:
+		return i
+	case <-time.After(5 * time.Second):
+		t.Fatal("no request arrived")
+		return 0
+	}
+}
+
+// idle checks that no request arrives for a while.
+func (p *blockingPeer) idle(t *testing.T) {
+	t.Helper()
+	select {
+	case i := <-p.arrived:
+		t.Fatalf("request %d arrived beyond the window", i)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// numberedHeartbeat returns a heartbeat request numbered i by its recovery
+// time stamp.
+func numberedHeartbeat(i int) *PFCPMessage {
+	req := heartbeatRequest()
+	req.Body = HeartbeatRequest{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: time.Unix(int64(i), 0)}}
+	return req
+}
+
+func TestSendRequestAsyncWindow(t *testing.T) {
+	const window, requests = 2, 5
+	_, cp, up := newTestServers(t)
+	cp.Config.MaxInFlightPerPeer = window
+	peer := newBlockingPeer(t, up)
+	serve(t, cp, NewServeMux())
+
+	var futures []*Future
+	for i := 0; i < requests; i++ {
+		futures = append(futures, cp.SendRequestAsync(context.Background(), numberedHeartbeat(i), testUPAddr, nil))
+	}
+
+	first := map[int64]bool{peer.next(t): true, peer.next(t): true}
+	if !first[0] || !first[1] {
+		t.Fatalf("requests %v sent first, want 0 and 1", first)
+	}
+	peer.idle(t)
+	// Each response makes room for the next queued request, in order
+	for i := window; i < requests; i++ {
+		peer.release <- struct{}{}
+		if got := peer.next(t); got != int64(i) {
+			t.Fatalf("request %d sent, want %d", got, i)
+		}
+		peer.idle(t)
+	}
+	for i := 0; i < window; i++ {
+		peer.release <- struct{}{}
+	}
+
+	for i, future := range futures {
+		if _, err := future.Result(); err != nil {
+			t.Errorf("request %d: %v", i, err)
+		}
+	}
+	cp.windowMu.Lock()
+	defer cp.windowMu.Unlock()
+	if len(cp.windows) != 0 {
+		t.Errorf("%d windows left, want 0", len(cp.windows))
+	}
+}
+
+func TestSendRequestAsyncWindowPerPeer(t *testing.T) {
+	network, cp, up := newTestServers(t)
+	otherAddr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 3).To4(), Port: PFCP_PORT}
+	other := newTestServer(t, network, otherAddr)
+	cp.Config.MaxInFlightPerPeer = 1
+	peer := newBlockingPeer(t, up)
+	serve(t, other, NewServeMux())
+	serve(t, cp, NewServeMux())
+
+	blocked := cp.SendRequestAsync(context.Background(), numberedHeartbeat(0), testUPAddr, nil)
+	peer.next(t)
+	// The full window of up doesn't hold the requests to other
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := cp.SendRequestAsync(ctx, heartbeatRequest(), otherAddr, nil).Result(); err != nil {
+		t.Fatal(err)
+	}
+	peer.release <- struct{}{}
+	if _, err := blocked.Result(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSendRequestAsyncQueuedCancel(t *testing.T) {
+	_, cp, up := newTestServers(t)
+	cp.Config.MaxInFlightPerPeer = 1
+	peer := newBlockingPeer(t, up)
+	serve(t, cp, NewServeMux())
+
+	sent := cp.SendRequestAsync(context.Background(), numberedHeartbeat(0), testUPAddr, nil)
+	peer.next(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	callback := make(chan error, 1)
+	canceled := cp.SendRequestAsync(ctx, numberedHeartbeat(1), testUPAddr, func(_ *Message, err error) {
+		callback <- err
+	})
+	queued := cp.SendRequestAsync(context.Background(), numberedHeartbeat(2), testUPAddr, nil)
+	cancel()
+
+	// Completed without waiting for room in the window
+	select {
+	case err := <-callback:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("canceled request callback error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("canceled request still queued")
+	}
+	if _, err := canceled.Result(); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled request error = %v, want %v", err, context.Canceled)
+	}
+
+	// The canceled request gives its turn to the next one
+	peer.release <- struct{}{}
+	if got := peer.next(t); got != 2 {
+		t.Fatalf("request %d sent, want 2", got)
+	}
+	peer.release <- struct{}{}
+
+	if _, err := sent.Result(); err != nil {
+		t.Error(err)
+	}
+	if _, err := queued.Result(); err != nil {
+		t.Error(err)
+	}
+	cp.windowMu.Lock()
+	defer cp.windowMu.Unlock()
+	if len(cp.windows) != 0 {
+		t.Errorf("%d windows left, want 0", len(cp.windows))
+	}
+}
+
+func TestSendRequestAsyncCallbackReentry(t *testing.T) {
+	_, cp, up := newTestServers(t)
+	cp.Config.MaxInFlightPerPeer = 1
+	peer := newBlockingPeer(t, up)
+	serve(t, cp, NewServeMux())
+
+	// The callbacks send another request to the same peer
+	futures := make(chan *Future, 2)
+	resend := func(i int) func(*Message, error) {
+		return func(*Message, error) {
+			futures <- cp.SendRequestAsync(context.Background(), numberedHeartbeat(i), testUPAddr, nil)
+		}
+	}
+	sent := cp.SendRequestAsync(context.Background(), numberedHeartbeat(0), testUPAddr, resend(2))
+	peer.next(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := cp.SendRequestAsync(ctx, numberedHeartbeat(1), testUPAddr, resend(3))
+	cancel()
+	if _, err := canceled.Result(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled request error = %v, want %v", err, context.Canceled)
+	}
+
+	peer.release <- struct{}{}
+	for _, want := range []int64{3, 2} {
+		if got := peer.next(t); got != want {
+			t.Fatalf("request %d sent, want %d", got, want)
+		}
+		peer.release <- struct{}{}
+	}
+	if _, err := sent.Result(); err != nil {
+		t.Error(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := (<-futures).Result(); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package pfcpgolb
+
+import "github.com/Nikhil690/pfcpgolb/tlv"
+
+// The conditions of TS 29.244 that depend on the other IEs of the message
+// only. Those depending on the features of the peers or on the session
+// state are left to the caller.
+
+// CheckConditions requires the FAR ID of a PDR activating no predefined
+// rules.
+func (p *CreatePDR) CheckConditions() error {
+	if p.FARID == nil && p.ActivatePredefinedRules == nil {
+		return tlv.MissingConditionalIE(p, "FARID")
+	}
+	return nil
+}
+
+// CheckConditions requires the Forwarding Parameters of a FAR forwarding the
+// packets, and the Duplicating Parameters of a FAR duplicating them.
+func (f *CreateFAR) CheckConditions() error {
+	if f.ApplyAction.Forw && f.ForwardingParameters == nil {
+		return tlv.MissingConditionalIE(f, "ForwardingParameters")
+	}
+	if f.ApplyAction.Dupl && f.DuplicatingParameters == nil {
+		return tlv.MissingConditionalIE(f, "DuplicatingParameters")
+	}
+	return nil
+}
+
+// CheckConditions requires the UP F-SEID of an accepted establishment.
+func (r *PFCPSessionEstablishmentResponse) CheckConditions() error {
+	if r.Cause.CauseValue == CauseRequestAccepted && r.UPFSEID == nil {
+		return tlv.MissingConditionalIE(r, "UPFSEID")
+	}
+	return nil
+}
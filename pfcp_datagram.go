@@ -0,0 +1,69 @@
+package pfcpgolb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// FO (follow on) flag in the first octet of the header
+const headerFlagFO = 0x04
+
+// SplitDatagram returns the messages concatenated in a UDP datagram. A
+// message with the FO flag set is followed by another one (TS 29.244
+// 7.2.2.1). The messages share the memory of b.
+//
+// Messages decoded before an error are returned along with it.
+func SplitDatagram(b []byte) ([][]byte, error) {
+	var msgs [][]byte
+	for {
+		if len(b) < 4 {
+			return msgs, fmt.Errorf("pfcp: datagram: %d bytes too short for a message header", len(b))
+		}
+		length := int(binary.BigEndian.Uint16(b[2:])) + 4
+		if length > len(b) {
+			return msgs, fmt.Errorf("pfcp: datagram: message length %d overruns the %d bytes left", length, len(b))
+		}
+		msgs = append(msgs, b[:length:length])
+		followOn := b[0]&headerFlagFO != 0
+		b = b[length:]
+		if !followOn || len(b) == 0 {
+			break
+		}
+	}
+	if len(b) != 0 {
+		return msgs, fmt.Errorf("pfcp: datagram: %d bytes after the last message", len(b))
+	}
+	return msgs, nil
+}
+
+// packDatagrams concatenates encoded messages into as few datagrams of at most
+// PFCP_MAX_UDP_LEN bytes as possible, setting the FO flag of every message
+// followed by another one. The encoded messages are left untouched.
+func packDatagrams(msgs [][]byte) ([][]byte, error) {
+	var datagrams [][]byte
+	var current []byte
+	lastStart := -1
+	for _, msg := range msgs {
+		if len(msg) == 0 {
+			return nil, errors.New("pfcp: datagram: empty message")
+		}
+		if len(msg) > PFCP_MAX_UDP_LEN {
+			return nil, fmt.Errorf("pfcp: datagram: %d bytes message exceeds %d bytes", len(msg), PFCP_MAX_UDP_LEN)
+		}
+		if len(current)+len(msg) > PFCP_MAX_UDP_LEN {
+			datagrams = append(datagrams, current)
+			current, lastStart = nil, -1
+		}
+		if lastStart >= 0 {
+			current[lastStart] |= headerFlagFO
+		}
+		lastStart = len(current)
+		current = append(current, msg...)
+		current[lastStart] &^= headerFlagFO
+	}
+	if len(current) > 0 {
+		datagrams = append(datagrams, current)
+	}
+	return datagrams, nil
+}
@@ -0,0 +1,196 @@
+package pfcpgolb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// rawMessage returns a node related message of n bytes.
+func rawMessage(n int) []byte {
+	b := make([]byte, n)
+	b[0], b[1] = PfcpVersion<<5, byte(PFCP_HEARTBEAT_REQUEST)
+	binary.BigEndian.PutUint16(b[2:], uint16(n-4))
+	return b
+}
+
+func TestDatagramRoundTrip(t *testing.T) {
+	var msgs []*PFCPMessage
+	var encoded [][]byte
+	for i := 0; i < 3; i++ {
+		msg := heartbeatRequest()
+		msg.Header.SequenceNumber = uint32(i + 1)
+		buf, err := msg.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs, encoded = append(msgs, msg), append(encoded, buf)
+	}
+	originals := bytes.Join(encoded, nil)
+
+	datagrams, err := packDatagrams(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datagrams) != 1 {
+		t.Fatalf("%d datagrams, want 1", len(datagrams))
+	}
+	if !bytes.Equal(bytes.Join(encoded, nil), originals) {
+		t.Error("packDatagrams modified the messages")
+	}
+	split, err := SplitDatagram(datagrams[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(split) != len(msgs) {
+		t.Fatalf("%d messages, want %d", len(split), len(msgs))
+	}
+	for i, b := range split {
+		var msg PFCPMessage
+		if err := msg.Unmarshal(b); err != nil {
+			t.Fatal(err)
+		}
+		// Set on all but the last message
+		wantFO := uint8(1)
+		if i == len(split)-1 {
+			wantFO = 0
+		}
+		if msg.Header.FO != wantFO {
+			t.Errorf("message %d FO = %d, want %d", i, msg.Header.FO, wantFO)
+		}
+		if msg.Header.SequenceNumber != msgs[i].Header.SequenceNumber {
+			t.Errorf("message %d SEQ %d, want %d", i, msg.Header.SequenceNumber, msgs[i].Header.SequenceNumber)
+		}
+	}
+}
+
+func TestSplitDatagramErrors(t *testing.T) {
+	datagrams, err := packDatagrams([][]byte{rawMessage(20), rawMessage(30)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name     string
+		datagram []byte
+		msgs     int
+	}{
+		{"truncated second message", datagrams[0][:45], 1},
+		{"truncated second header", datagrams[0][:22], 1},
+		{"trailing bytes", append(rawMessage(20), 0, 0), 1},
+		{"truncated header", []byte{0x20, 1}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs, err := SplitDatagram(tc.datagram)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if len(msgs) != tc.msgs {
+				t.Errorf("%d messages returned, want %d", len(msgs), tc.msgs)
+			}
+		})
+	}
+}
+
+func TestPackDatagramsSplit(t *testing.T) {
+	size := PFCP_MAX_UDP_LEN/2 + 100
+	msgs := [][]byte{rawMessage(size), rawMessage(size), rawMessage(100), rawMessage(size)}
+	datagrams, err := packDatagrams(msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCounts := []int{1, 2, 1}
+	if len(datagrams) != len(wantCounts) {
+		t.Fatalf("%d datagrams, want %d", len(datagrams), len(wantCounts))
+	}
+	for i, datagram := range datagrams {
+		if len(datagram) > PFCP_MAX_UDP_LEN {
+			t.Errorf("datagram %d of %d bytes", i, len(datagram))
+		}
+		split, err := SplitDatagram(datagram)
+		if err != nil {
+			t.Fatalf("datagram %d: %v", i, err)
+		}
+		if len(split) != wantCounts[i] {
+			t.Errorf("datagram %d holds %d messages, want %d", i, len(split), wantCounts[i])
+		}
+		if last := split[len(split)-1]; last[0]&headerFlagFO != 0 {
+			t.Errorf("datagram %d ends with the FO flag set", i)
+		}
+	}
+
+	if _, err := packDatagrams([][]byte{rawMessage(PFCP_MAX_UDP_LEN + 1)}); err == nil {
+		t.Error("message longer than a datagram packed")
+	}
+	if _, err := packDatagrams([][]byte{{}}); err == nil {
+		t.Error("empty message packed")
+	}
+}
+
+func TestWriteBatchTo(t *testing.T) {
+	network, cp, up := newTestServers(t)
+	serve(t, up, HandlerFunc(func(w ResponseWriter, req *Message) {
+		w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: up.RecoveryTimeStamp}})
+	}))
+	serve(t, cp, NewServeMux())
+
+	t.Run("requests", func(t *testing.T) {
+		res, err := cp.WriteBatchTo([]*PFCPMessage{heartbeatRequest(), heartbeatRequest()}, testUPAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, msg := range res {
+			if _, ok := msg.PfcpMessage.Body.(HeartbeatResponse); !ok {
+				t.Errorf("response %d body %T, want HeartbeatResponse", i, msg.PfcpMessage.Body)
+			}
+		}
+	})
+
+	t.Run("responses", func(t *testing.T) {
+		conn, err := network.Listen(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 3).To4(), Port: PFCP_PORT})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		addr := conn.LocalAddr().(*net.UDPAddr)
+		var responses []*PFCPMessage
+		for seq := uint32(1); seq <= 2; seq++ {
+			responses = append(responses, &PFCPMessage{
+				Header: Header{Version: PfcpVersion, MessageType: PFCP_HEARTBEAT_RESPONSE, SequenceNumber: seq},
+				Body:   HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: up.RecoveryTimeStamp}},
+			})
+		}
+		if _, err := up.WriteBatchTo(responses, addr); err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, PFCP_MAX_UDP_LEN)
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs, err := SplitDatagram(buf[:n])
+		if err != nil || len(msgs) != 2 {
+			t.Fatalf("datagram of %d messages, %v, want 2", len(msgs), err)
+		}
+
+		// Each response is kept without the FO flag, and replayed to the
+		// retransmission of its request
+		for i, msg := range msgs {
+			data, ok := up.responses.get(addr.String(), uint32(i+1))
+			if !ok || data[0] != msg[0]&^headerFlagFO || !bytes.Equal(data[1:], msg[1:]) {
+				t.Errorf("response %d kept as %x, want %x", i+1, data, msg)
+			}
+		}
+		req := heartbeatRequest()
+		req.Header.SequenceNumber = 2
+		data, err := req.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if replayed := exchange(t, conn, testUPAddr, data); replayed.Header.SequenceNumber != 2 {
+			t.Errorf("replayed response SEQ %d, want 2", replayed.Header.SequenceNumber)
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package pfcpgolb
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb/tlv"
+)
+
+// IEError reports a message that was decoded but cannot be accepted because of
+// one of its IEs. Cause and OffendingIE hold the values to put in the
+// response to the peer.
+type IEError struct {
+	MessageType MessageType
+	Cause       uint8
+	OffendingIE uint16
+	Err         error
+}
+
+func (e *IEError) Error() string {
+	return fmt.Sprintf("pfcp: message type %d rejected with cause %d (offending IE %d): %s",
+		e.MessageType, e.Cause, e.OffendingIE, e.Err)
+}
+
+func (e *IEError) Unwrap() error {
+	return e.Err
+}
+
+// CauseIE returns the Cause IE to send back to the peer.
+func (e *IEError) CauseIE() *Cause {
+	return &Cause{CauseValue: e.Cause}
+}
+
+// OffendingIEValue returns the Offending IE to send back to the peer.
+func (e *IEError) OffendingIEValue() *OffendingIE {
+	return &OffendingIE{TypeOfOffendingIe: e.OffendingIE}
+}
+
+// Reasons of an UnmatchedResponseError
+var (
+	ErrNoTransaction          = errors.New("no request in flight with this sequence number")
+	ErrUnexpectedResponseType = errors.New("message type doesn't answer the request")
+	ErrUnexpectedSEID         = errors.New("SEID doesn't match the CP F-SEID of the request")
+)
+
+// UnmatchedResponseError reports a response received from a peer that
+// answers none of the requests sent to it.
+type UnmatchedResponseError struct {
+	RemoteAddr     *net.UDPAddr
+	MessageType    MessageType
+	SequenceNumber uint32
+	SEID           uint64
+	Err            error
+}
+
+func (e *UnmatchedResponseError) Error() string {
+	return fmt.Sprintf("pfcp: unmatched response type %d SEQ[%d] from %s: %s", e.MessageType,
+		e.SequenceNumber, e.RemoteAddr, e.Err)
+}
+
+func (e *UnmatchedResponseError) Unwrap() error {
+	return e.Err
+}
+
+// newIEError maps an error returned by the tlv package while decoding or
+// validating the body of m to the Cause that rejects it. Decode errors are
+// completed with the message type and their offset from the start of the
+// message.
+func (m *PFCPMessage) newIEError(err error) error {
+	var missing *tlv.MissingIEError
+	var decodeErr *tlv.DecodeError
+	switch {
+	case errors.As(err, &missing):
+		cause := CauseMandatoryIeMissing
+		if missing.Conditional {
+			cause = CauseConditionalIeMissing
+		}
+		return &IEError{
+			MessageType: m.Header.MessageType,
+			Cause:       cause,
+			OffendingIE: uint16(missing.Type),
+			Err:         err,
+		}
+	case errors.As(err, &decodeErr):
+		decodeErr.MessageType = uint8(m.Header.MessageType)
+		decodeErr.Offset += m.Header.Len()
+
+		cause := CauseRequestRejected
+		if tlv.IsLengthError(err) {
+			cause = CauseInvalidLength
+		} else if decodeErr.Mandatory {
+			cause = CauseMandatoryIeIncorrect
+		}
+		return &IEError{
+			MessageType: m.Header.MessageType,
+			Cause:       cause,
+			OffendingIE: uint16(decodeErr.Type),
+			Err:         err,
+		}
+	}
+	return err
+}
+
+// NewErrorResponse builds the response rejecting req with the given Cause and
+// Offending IE (0 when there is none). nodeID and recoveryTimeStamp identify
+// the local PFCP entity in the responses where they are mandatory.
+//
+// The SEID of a session related response is taken from the CP F-SEID of req
+// when it could be decoded, and is 0 otherwise.
+func NewErrorResponse(req *PFCPMessage, cause uint8, offendingIE uint16, nodeID *NodeID,
+	recoveryTimeStamp time.Time,
+) (*PFCPMessage, error) {
+	var offending *OffendingIE
+	if offendingIE != 0 {
+		offending = &OffendingIE{TypeOfOffendingIe: offendingIE}
+	}
+	causeIE := &Cause{CauseValue: cause}
+
+	res := &PFCPMessage{
+		Header: Header{
+			Version:        PfcpVersion,
+			MessageType:    req.Header.MessageType + 1,
+			SequenceNumber: req.Header.SequenceNumber,
+		},
+	}
+
+	switch req.Header.MessageType {
+	case PFCP_HEARTBEAT_REQUEST:
+		// A heartbeat has no Cause: the response just reports the recovery
+		res.Body = HeartbeatResponse{
+			RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: recoveryTimeStamp},
+		}
+	case PFCP_PFD_MANAGEMENT_REQUEST:
+		res.Body = PFCPPFDManagementResponse{
+			Cause:       causeIE,
+			OffendingIE: offending,
+		}
+	case PFCP_ASSOCIATION_SETUP_REQUEST:
+		res.Body = PFCPAssociationSetupResponse{
+			NodeID:            nodeID,
+			Cause:             causeIE,
+			RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: recoveryTimeStamp},
+		}
+	case PFCP_ASSOCIATION_UPDATE_REQUEST:
+		res.Body = PFCPAssociationUpdateResponse{
+			NodeID: nodeID,
+			Cause:  causeIE,
+		}
+	case PFCP_ASSOCIATION_RELEASE_REQUEST:
+		res.Body = PFCPAssociationReleaseResponse{
+			NodeID: nodeID,
+			Cause:  causeIE,
+		}
+	case PFCP_NODE_REPORT_REQUEST:
+		res.Body = PFCPNodeReportResponse{
+			NodeID:      nodeID,
+			Cause:       causeIE,
+			OffendingIE: offending,
+		}
+	case PFCP_SESSION_SET_DELETION_REQUEST:
+		res.Body = PFCPSessionSetDeletionResponse{
+			NodeID:      nodeID,
+			Cause:       causeIE,
+			OffendingIE: offending,
+		}
+	case PFCP_SESSION_ESTABLISHMENT_REQUEST:
+		res.Header.S = SEID_PRESENT
+		if body, ok := req.Body.(PFCPSessionEstablishmentRequest); ok && body.CPFSEID != nil {
+			res.Header.SEID = body.CPFSEID.Seid
+		}
+		res.Body = PFCPSessionEstablishmentResponse{
+			NodeID:      nodeID,
+			Cause:       causeIE,
+			OffendingIE: offending,
+		}
+	case PFCP_SESSION_MODIFICATION_REQUEST:
+		res.Header.S = SEID_PRESENT
+		if body, ok := req.Body.(PFCPSessionModificationRequest); ok && body.CPFSEID != nil {
+			res.Header.SEID = body.CPFSEID.Seid
+		}
+		res.Body = PFCPSessionModificationResponse{
+			Cause:       causeIE,
+			OffendingIE: offending,
+		}
+	case PFCP_SESSION_DELETION_REQUEST:
+		res.Header.S = SEID_PRESENT
+		res.Body = PFCPSessionDeletionResponse{
+			Cause:       causeIE,
+			OffendingIE: offending,
+		}
+	case PFCP_SESSION_REPORT_REQUEST:
+		res.Header.S = SEID_PRESENT
+		res.Body = PFCPSessionReportResponse{
+			Cause:       causeIE,
+			OffendingIE: offending,
+		}
+	default:
+		return nil, fmt.Errorf("pfcp: can't build error response to message type %d", req.Header.MessageType)
+	}
+	return res, nil
+}
+
+// rejectCause returns the Cause and Offending IE answering a request that
+// failed to decode with err.
+func rejectCause(err error) (uint8, uint16) {
+	var ieErr *IEError
+	if errors.As(err, &ieErr) {
+		return ieErr.Cause, ieErr.OffendingIE
+	}
+	return CauseRequestRejected, 0
+}
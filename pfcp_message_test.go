@@ -0,0 +1,386 @@
+package pfcpgolb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb/tlv"
+)
+
+// testEstablishmentRequest returns the establishment request of an IPv4 PDU
+// session as sent by an SMF: an uplink and a downlink PDR, their FARs and a
+// session QER.
+func testEstablishmentRequest() PFCPSessionEstablishmentRequest {
+	return PFCPSessionEstablishmentRequest{
+		NodeID: &NodeID{NodeIdType: NodeIdTypeIpv4Address, IP: net.IPv4(10, 0, 0, 1).To4()},
+		CPFSEID: &FSEID{
+			V4:          true,
+			Seid:        0x1122334455667788,
+			Ipv4Address: net.IPv4(10, 0, 0, 1).To4(),
+		},
+		CreatePDR: []*CreatePDR{
+			{
+				PDRID:      &PacketDetectionRuleID{RuleId: 1},
+				Precedence: &Precedence{PrecedenceValue: 255},
+				PDI: &PDI{
+					SourceInterface: &SourceInterface{InterfaceValue: SourceInterfaceAccess},
+					LocalFTEID:      &FTEID{V4: true, Ch: true, Chid: true, ChooseId: 1},
+					NetworkInstance: &NetworkInstance{NetworkInstance: "internet"},
+					UEIPAddress:     &UEIPAddress{V4: true, Ipv4Address: net.IPv4(10, 60, 0, 1).To4()},
+					SDFFilter: &SDFFilter{
+						Fd:                      true,
+						LengthOfFlowDescription: 34,
+						FlowDescription:         []byte("permit out ip from any to assigned"),
+					},
+					QFI: []*QFI{{QFI: 9}},
+				},
+				OuterHeaderRemoval: &OuterHeaderRemoval{OuterHeaderRemovalDescription: OuterHeaderRemovalGtpUUdpIpv4},
+				FARID:              &FARID{FarIdValue: 1},
+				QERID:              []*QERID{{QERID: 1}},
+			},
+			{
+				PDRID:      &PacketDetectionRuleID{RuleId: 2},
+				Precedence: &Precedence{PrecedenceValue: 255},
+				PDI: &PDI{
+					SourceInterface: &SourceInterface{InterfaceValue: SourceInterfaceCore},
+					NetworkInstance: &NetworkInstance{NetworkInstance: "internet"},
+					UEIPAddress:     &UEIPAddress{V4: true, Sd: true, Ipv4Address: net.IPv4(10, 60, 0, 1).To4()},
+				},
+				FARID: &FARID{FarIdValue: 2},
+				QERID: []*QERID{{QERID: 1}},
+			},
+		},
+		CreateFAR: []*CreateFAR{
+			{
+				FARID:       &FARID{FarIdValue: 1},
+				ApplyAction: &ApplyAction{Forw: true},
+				ForwardingParameters: &ForwardingParametersIEInFAR{
+					DestinationInterface: &DestinationInterface{InterfaceValue: DestinationInterfaceCore},
+					NetworkInstance:      &NetworkInstance{NetworkInstance: "internet"},
+				},
+			},
+			{
+				FARID:       &FARID{FarIdValue: 2},
+				ApplyAction: &ApplyAction{Forw: true},
+				ForwardingParameters: &ForwardingParametersIEInFAR{
+					DestinationInterface: &DestinationInterface{InterfaceValue: DestinationInterfaceAccess},
+					NetworkInstance:      &NetworkInstance{NetworkInstance: "internet"},
+					OuterHeaderCreation: &OuterHeaderCreation{
+						OuterHeaderCreationDescription: OuterHeaderCreationGtpUUdpIpv4,
+						Teid:                           0x00000042,
+						Ipv4Address:                    net.IPv4(192, 168, 1, 10).To4(),
+					},
+				},
+			},
+		},
+		CreateQER: []*CreateQER{
+			{
+				QERID:             &QERID{QERID: 1},
+				GateStatus:        &GateStatus{ULGate: GateOpen, DLGate: GateOpen},
+				MaximumBitrate:    &MBR{ULMBR: 100000, DLMBR: 200000},
+				QoSFlowIdentifier: &QFI{QFI: 9},
+			},
+		},
+		PDNType: &PDNType{PdnType: PDNTypeIpv4},
+	}
+}
+
+// testModificationRequest returns a modification request of the session of
+// testEstablishmentRequest, switching the downlink to a new tunnel.
+func testModificationRequest() PFCPSessionModificationRequest {
+	return PFCPSessionModificationRequest{
+		RemoveQER: []*RemoveQER{{QERID: &QERID{QERID: 2}}},
+		UpdatePDR: []*UpdatePDR{{PDRID: &PacketDetectionRuleID{RuleId: 2}, QERID: []*QERID{{QERID: 1}}}},
+		UpdateFAR: []*UpdateFAR{{
+			FARID:       &FARID{FarIdValue: 2},
+			ApplyAction: &ApplyAction{Forw: true},
+			UpdateForwardingParameters: &UpdateForwardingParametersIEInFAR{
+				OuterHeaderCreation: &OuterHeaderCreation{
+					OuterHeaderCreationDescription: OuterHeaderCreationGtpUUdpIpv4,
+					Teid:                           0x00000043,
+					Ipv4Address:                    net.IPv4(192, 168, 1, 11).To4(),
+				},
+				PFCPSMReqFlags: &PFCPSMReqFlags{Sndem: true},
+			},
+		}},
+		UpdateQER: []*UpdateQER{{QERID: &QERID{QERID: 1}, GateStatus: &GateStatus{DLGate: GateClose}}},
+	}
+}
+
+func TestSessionEstablishmentRequestRoundTrip(t *testing.T) {
+	msg := &PFCPMessage{
+		Header: Header{
+			Version:        PfcpVersion,
+			S:              SEID_PRESENT,
+			MessageType:    PFCP_SESSION_ESTABLISHMENT_REQUEST,
+			SequenceNumber: 7,
+		},
+		Body: testEstablishmentRequest(),
+	}
+	data, err := msg.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded PFCPMessage
+	if err := decoded.Unmarshal(data); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Body, msg.Body) {
+		t.Fatalf("Unmarshal = %+v, want %+v", decoded.Body, msg.Body)
+	}
+	reencoded, err := decoded.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal decoded: %v", err)
+	}
+	if !bytes.Equal(reencoded, data) {
+		t.Fatalf("Marshal decoded = %x, want %x", reencoded, data)
+	}
+
+	req := decoded.Body.(PFCPSessionEstablishmentRequest)
+	s, err := NewSession(&FSEID{Seid: 1}, &req)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	rules := s.Rules()
+	if len(rules.PDRs) != 2 || len(rules.FARs) != 2 || len(rules.QERs) != 1 {
+		t.Fatalf("rules = %+v", rules)
+	}
+}
+
+func TestSessionModificationRequestRoundTrip(t *testing.T) {
+	msg := &PFCPMessage{
+		Header: Header{
+			Version:        PfcpVersion,
+			S:              SEID_PRESENT,
+			MessageType:    PFCP_SESSION_MODIFICATION_REQUEST,
+			SEID:           1,
+			SequenceNumber: 8,
+		},
+		Body: testModificationRequest(),
+	}
+	data, err := msg.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded PFCPMessage
+	if err := decoded.Unmarshal(data); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Body, msg.Body) {
+		t.Fatalf("Unmarshal = %+v, want %+v", decoded.Body, msg.Body)
+	}
+}
+
+func TestUnmarshalConditionalIEMissing(t *testing.T) {
+	est := testEstablishmentRequest()
+	est.CreateFAR[1].ForwardingParameters = nil
+	tests := []struct {
+		name        string
+		msg         *PFCPMessage
+		offendingIE uint16
+	}{
+		{
+			"forwarding FAR without forwarding parameters",
+			&PFCPMessage{
+				Header: Header{Version: PfcpVersion, S: SEID_PRESENT, MessageType: PFCP_SESSION_ESTABLISHMENT_REQUEST},
+				Body:   est,
+			},
+			4,
+		},
+		{
+			"accepted establishment without UP F-SEID",
+			&PFCPMessage{
+				Header: Header{Version: PfcpVersion, S: SEID_PRESENT, MessageType: PFCP_SESSION_ESTABLISHMENT_RESPONSE},
+				Body: PFCPSessionEstablishmentResponse{
+					NodeID: est.NodeID,
+					Cause:  &Cause{CauseValue: CauseRequestAccepted},
+				},
+			},
+			57,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.msg.Marshal()
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var decoded PFCPMessage
+			err = decoded.Unmarshal(data)
+			cause, offendingIE := rejectCause(err)
+			if cause != CauseConditionalIeMissing || offendingIE != tt.offendingIE {
+				t.Fatalf("Unmarshal = %v, want cause %d offending IE %d", err, CauseConditionalIeMissing, tt.offendingIE)
+			}
+		})
+	}
+}
+
+// reverseIEs returns the IEs of b in reverse order, as well as the IEs
+// grouped in the IEs of type group.
+func reverseIEs(t *testing.T, b []byte, group uint16) []byte {
+	t.Helper()
+	var ies [][]byte
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ie.Type == group {
+			ie.Value = reverseIEs(t, ie.Value, 0)
+		}
+		data, _ := ie.MarshalBinary()
+		ies = append([][]byte{data}, ies...)
+	}
+	return bytes.Join(ies, nil)
+}
+
+func TestSessionEstablishmentRequestOutOfOrder(t *testing.T) {
+	msg := &PFCPMessage{
+		Header: Header{
+			Version:        PfcpVersion,
+			S:              SEID_PRESENT,
+			MessageType:    PFCP_SESSION_ESTABLISHMENT_REQUEST,
+			SequenceNumber: 1,
+		},
+		Body: testEstablishmentRequest(),
+	}
+	data, err := msg.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	// The IEs and those of the PDRs reversed, with an unknown IE among them
+	unknown, _ := (&tlv.RawIE{Type: 300, Value: []byte{1, 2}}).MarshalBinary()
+	body := reverseIEs(t, data[16:], 1)
+	data = append(append(data[:16:16], unknown...), body...)
+	binary.BigEndian.PutUint16(data[2:], uint16(len(data)-4))
+
+	var decoded PFCPMessage
+	if err := decoded.Unmarshal(data); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	est := decoded.Body.(PFCPSessionEstablishmentRequest)
+	if est.IEOrder.Len() == 0 || est.CreatePDR[0].IEOrder.Len() == 0 {
+		t.Fatal("order of the IEs not recorded")
+	}
+	reencoded, err := decoded.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal decoded: %v", err)
+	}
+	if !bytes.Equal(reencoded, data) {
+		t.Errorf("re-encoded message differs:\n got %x\nwant %x", reencoded, data)
+	}
+
+	// Same through reflection only
+	var reflected PFCPSessionEstablishmentRequest
+	if err := tlv.UnmarshalReflect(data[16:], &reflected); err != nil {
+		t.Fatalf("UnmarshalReflect: %v", err)
+	}
+	if !reflect.DeepEqual(reflected, est) {
+		t.Errorf("UnmarshalReflect = %+v, want %+v", reflected, est)
+	}
+	if body, err := tlv.MarshalReflect(&reflected); err != nil || !bytes.Equal(body, data[16:]) {
+		t.Errorf("MarshalReflect = %x, %v, want %x", body, err, data[16:])
+	}
+}
+
+// testMessages returns valid encoded messages of the node and session
+// procedures, to seed the fuzz targets.
+func testMessages(tb testing.TB) [][]byte {
+	tb.Helper()
+	est := testEstablishmentRequest()
+	recovery := &RecoveryTimeStamp{RecoveryTimeStamp: time.Unix(1700000000, 0)}
+	msgs := []*PFCPMessage{
+		{
+			Header: Header{MessageType: PFCP_HEARTBEAT_REQUEST, SequenceNumber: 1},
+			Body:   HeartbeatRequest{RecoveryTimeStamp: recovery},
+		},
+		{
+			Header: Header{MessageType: PFCP_HEARTBEAT_RESPONSE, SequenceNumber: 1},
+			Body:   HeartbeatResponse{RecoveryTimeStamp: recovery},
+		},
+		{
+			Header: Header{MessageType: PFCP_ASSOCIATION_SETUP_REQUEST, SequenceNumber: 2},
+			Body: PFCPAssociationSetupRequest{
+				NodeID:             est.NodeID,
+				RecoveryTimeStamp:  recovery,
+				CPFunctionFeatures: &CPFunctionFeatures{SupportedFeatures: 1},
+			},
+		},
+		{
+			Header: Header{MessageType: PFCP_ASSOCIATION_SETUP_RESPONSE, SequenceNumber: 2},
+			Body: PFCPAssociationSetupResponse{
+				NodeID:             &NodeID{NodeIdType: NodeIdTypeFqdn, FQDN: "upf.example.org"},
+				Cause:              &Cause{CauseValue: CauseRequestAccepted},
+				RecoveryTimeStamp:  recovery,
+				UPFunctionFeatures: &UPFunctionFeatures{SupportedFeatures: 0x0110},
+			},
+		},
+		{
+			Header: Header{MessageType: PFCP_SESSION_ESTABLISHMENT_REQUEST, S: SEID_PRESENT, SequenceNumber: 3},
+			Body:   est,
+		},
+		{
+			Header: Header{MessageType: PFCP_SESSION_ESTABLISHMENT_RESPONSE, S: SEID_PRESENT, SEID: est.CPFSEID.Seid,
+				SequenceNumber: 3},
+			Body: PFCPSessionEstablishmentResponse{
+				NodeID:  est.NodeID,
+				Cause:   &Cause{CauseValue: CauseRequestAccepted},
+				UPFSEID: &FSEID{V4: true, Seid: 1, Ipv4Address: testUPAddr.IP},
+			},
+		},
+		{
+			Header: Header{MessageType: PFCP_SESSION_MODIFICATION_REQUEST, S: SEID_PRESENT, SEID: 1, SequenceNumber: 4},
+			Body:   testModificationRequest(),
+		},
+		{
+			Header: Header{MessageType: PFCP_SESSION_DELETION_REQUEST, S: SEID_PRESENT, SEID: 1, MP: 1,
+				MessagePriority: 3, SequenceNumber: 5},
+			Body: PFCPSessionDeletionRequest{},
+		},
+	}
+	var data [][]byte
+	for _, msg := range msgs {
+		msg.Header.Version = PfcpVersion
+		b, err := msg.Marshal()
+		if err != nil {
+			tb.Fatalf("Marshal type %d: %v", msg.Header.MessageType, err)
+		}
+		data = append(data, b)
+	}
+	return data
+}
+
+func FuzzPFCPMessageUnmarshal(f *testing.F) {
+	for _, data := range testMessages(f) {
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var msg PFCPMessage
+		if err := msg.Unmarshal(data); err != nil {
+			return
+		}
+		// A message accepted once is accepted again once re-encoded, and is
+		// then encoded the same
+		reencoded, err := msg.Marshal()
+		if err != nil {
+			t.Fatalf("Marshal of decoded %+v: %v", msg.Body, err)
+		}
+		var decoded PFCPMessage
+		if err := decoded.Unmarshal(reencoded); err != nil {
+			t.Fatalf("Unmarshal of re-encoded %x: %v", reencoded, err)
+		}
+		again, err := decoded.Marshal()
+		if err != nil {
+			t.Fatalf("Marshal of %+v: %v", decoded.Body, err)
+		}
+		if !bytes.Equal(again, reencoded) {
+			t.Fatalf("Marshal = %x, want %x", again, reencoded)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package pfcpgolb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/Nikhil690/pfcpgolb/tlv"
+)
+
+// IE types looked up on the fast path
+const (
+	ieTypeNodeID uint16 = 60
+	ieTypeFSEID  uint16 = 57
+)
+
+// MessageView gives access to the header and the IEs of an encoded message
+// without decoding its body. The IEs are indexed over the original buffer:
+// nothing is copied, and the fixed-width fields patched through the view are
+// written to that buffer, ready to be forwarded.
+type MessageView struct {
+	Header Header
+	buf    []byte
+	ies    []IEView
+}
+
+// IEView is an IE of a MessageView. Value shares the memory of the message.
+type IEView struct {
+	Type         uint16
+	EnterpriseID uint16
+	Value        []byte
+	// Offset of the IE from the start of the message
+	Offset int
+}
+
+// IEIterator iterates over the IEs grouped in an IE.
+type IEIterator struct {
+	r    tlv.Reader
+	base int
+	ie   IEView
+	err  error
+}
+
+// NewMessageView indexes the top-level IEs of the encoded message b.
+func NewMessageView(b []byte) (*MessageView, error) {
+	v := &MessageView{buf: b}
+	if err := v.Header.UnmarshalBinary(b); err != nil {
+		return nil, fmt.Errorf("pfcp: message view: %s", err)
+	}
+	headerLen := v.Header.Len()
+	if len(b) < headerLen {
+		return nil, fmt.Errorf("pfcp: message view: %d bytes too short for header", len(b))
+	}
+	if int(v.Header.MessageLength) != len(b)-4 {
+		return nil, fmt.Errorf("Message Length Incorrect: Expected %d, got %d", v.Header.MessageLength, len(b)-4)
+	}
+
+	it := IEIterator{r: tlv.NewReader(b[headerLen:]), base: headerLen}
+	for it.Next() {
+		v.ies = append(v.ies, it.IE())
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("pfcp: message view: %s", err)
+	}
+	return v, nil
+}
+
+// Bytes returns the encoded message, including the patched fields.
+func (v *MessageView) Bytes() []byte {
+	return v.buf
+}
+
+// IEs returns the top-level IEs of the message in the order they were received.
+func (v *MessageView) IEs() []IEView {
+	return v.ies
+}
+
+// Find returns the first top-level IE of the given type.
+func (v *MessageView) Find(ieType uint16) (IEView, bool) {
+	for _, ie := range v.ies {
+		if ie.Type == ieType {
+			return ie, true
+		}
+	}
+	return IEView{}, false
+}
+
+// NodeID decodes the Node ID IE of the message.
+func (v *MessageView) NodeID() (*NodeID, error) {
+	ie, ok := v.Find(ieTypeNodeID)
+	if !ok {
+		return nil, errors.New("pfcp: message view: no Node ID")
+	}
+	nodeID := &NodeID{}
+	if err := nodeID.UnmarshalBinary(ie.Value); err != nil {
+		return nil, err
+	}
+	return nodeID, nil
+}
+
+// FSEID decodes the F-SEID IE of the message.
+func (v *MessageView) FSEID() (*FSEID, error) {
+	ie, ok := v.Find(ieTypeFSEID)
+	if !ok {
+		return nil, errors.New("pfcp: message view: no F-SEID")
+	}
+	fseid := &FSEID{}
+	if err := fseid.UnmarshalBinary(ie.Value); err != nil {
+		return nil, err
+	}
+	return fseid, nil
+}
+
+// SetSEID rewrites the SEID of the header in place.
+func (v *MessageView) SetSEID(seid uint64) error {
+	if v.Header.S&1 == 0 {
+		return errors.New("pfcp: message view: header has no SEID")
+	}
+	binary.BigEndian.PutUint64(v.buf[4:], seid)
+	v.Header.SEID = seid
+	return nil
+}
+
+// SetSequenceNumber rewrites the sequence number of the header in place.
+func (v *MessageView) SetSequenceNumber(sequenceNumber uint32) error {
+	if sequenceNumber > MaxSequenceNumber {
+		return fmt.Errorf("pfcp: message view: sequence number %d exceeds 24 bits", sequenceNumber)
+	}
+	offset := 4
+	if v.Header.S&1 != 0 {
+		offset = 12
+	}
+	v.buf[offset] = uint8(sequenceNumber >> 16)
+	v.buf[offset+1] = uint8(sequenceNumber >> 8)
+	v.buf[offset+2] = uint8(sequenceNumber)
+	v.Header.SequenceNumber = sequenceNumber
+	return nil
+}
+
+// Children returns an iterator over the IEs grouped in ie.
+func (ie IEView) Children() IEIterator {
+	return IEIterator{r: tlv.NewReader(ie.Value), base: ie.valueOffset()}
+}
+
+// Find returns the first IE of the given type grouped in ie.
+func (ie IEView) Find(ieType uint16) (IEView, bool) {
+	for it := ie.Children(); it.Next(); {
+		if child := it.IE(); child.Type == ieType {
+			return child, true
+		}
+	}
+	return IEView{}, false
+}
+
+// PatchFSEID rewrites in place the SEID of an F-SEID IE and, when ipv4 is not
+// nil, its IPv4 address.
+func (ie IEView) PatchFSEID(seid uint64, ipv4 net.IP) error {
+	if ie.Type != ieTypeFSEID || len(ie.Value) < 9 {
+		return errors.New("pfcp: message view: not an F-SEID")
+	}
+	binary.BigEndian.PutUint64(ie.Value[1:], seid)
+	if ipv4 == nil {
+		return nil
+	}
+	if ie.Value[0]&0x02 == 0 || len(ie.Value) < 9+net.IPv4len {
+		return errors.New("pfcp: message view: F-SEID has no IPv4 address")
+	}
+	ip := ipv4.To4()
+	if ip == nil {
+		return fmt.Errorf("pfcp: message view: invalid IPv4 address %s", ipv4)
+	}
+	copy(ie.Value[9:], ip)
+	return nil
+}
+
+// PatchNodeIDIPv4 rewrites in place the address of an IPv4 Node ID IE.
+func (ie IEView) PatchNodeIDIPv4(ipv4 net.IP) error {
+	if ie.Type != ieTypeNodeID || len(ie.Value) < 1+net.IPv4len || ie.Value[0]&0x0F != NodeIdTypeIpv4Address {
+		return errors.New("pfcp: message view: not an IPv4 Node ID")
+	}
+	ip := ipv4.To4()
+	if ip == nil {
+		return fmt.Errorf("pfcp: message view: invalid IPv4 address %s", ipv4)
+	}
+	copy(ie.Value[1:], ip)
+	return nil
+}
+
+func (ie IEView) valueOffset() int {
+	if tlv.IsVendorSpecific(ie.Type) {
+		return ie.Offset + 6
+	}
+	return ie.Offset + 4
+}
+
+// Next advances to the next IE, returning false when there are no more IEs
+// or an IE is malformed.
+func (it *IEIterator) Next() bool {
+	if it.err != nil || !it.r.More() {
+		return false
+	}
+	offset := it.base + it.r.Offset()
+	raw, err := it.r.Next()
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.ie = IEView{Type: raw.Type, EnterpriseID: raw.EnterpriseID, Value: raw.Value, Offset: offset}
+	return true
+}
+
+// IE returns the current IE.
+func (it *IEIterator) IE() IEView {
+	return it.ie
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *IEIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,110 @@
+package pfcpgolb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// viewedIDs returns the Node ID and the first F-SEID of a decoded body, as
+// found by the accessors of MessageView.
+func viewedIDs(body interface{}) (*NodeID, *FSEID) {
+	switch body := body.(type) {
+	case PFCPAssociationSetupRequest:
+		return body.NodeID, nil
+	case PFCPAssociationSetupResponse:
+		return body.NodeID, nil
+	case PFCPSessionEstablishmentRequest:
+		return body.NodeID, body.CPFSEID
+	case PFCPSessionEstablishmentResponse:
+		return body.NodeID, body.UPFSEID
+	case PFCPSessionModificationRequest:
+		return nil, body.CPFSEID
+	}
+	return nil, nil
+}
+
+func TestMessageViewMatchesUnmarshal(t *testing.T) {
+	for _, data := range testMessages(t) {
+		var msg PFCPMessage
+		if err := msg.Unmarshal(data); err != nil {
+			t.Fatalf("Unmarshal %x: %v", data, err)
+		}
+		v, err := NewMessageView(data)
+		if err != nil {
+			t.Fatalf("NewMessageView type %d: %v", msg.Header.MessageType, err)
+		}
+		if v.Header != msg.Header {
+			t.Errorf("Header = %+v, want %+v", v.Header, msg.Header)
+		}
+		if !bytes.Equal(v.Bytes(), data) {
+			t.Errorf("type %d: Bytes() differ from the message", msg.Header.MessageType)
+		}
+
+		// The IEs cover the body back to back
+		offset := msg.Header.Len()
+		for _, ie := range v.IEs() {
+			if ie.Offset != offset {
+				t.Errorf("type %d: IE %d at offset %d, want %d", msg.Header.MessageType, ie.Type, ie.Offset, offset)
+			}
+			if !bytes.Equal(data[ie.valueOffset():ie.valueOffset()+len(ie.Value)], ie.Value) {
+				t.Errorf("type %d: IE %d value is not at its offset", msg.Header.MessageType, ie.Type)
+			}
+			if found, ok := v.Find(ie.Type); !ok || found.Offset > ie.Offset {
+				t.Errorf("type %d: Find(%d) = %+v, %v", msg.Header.MessageType, ie.Type, found, ok)
+			}
+			offset = ie.valueOffset() + len(ie.Value)
+		}
+		if offset != len(data) {
+			t.Errorf("type %d: IEs end at %d, want %d", msg.Header.MessageType, offset, len(data))
+		}
+		if n := bodyIECount(msg.Body); n != len(v.IEs()) {
+			t.Errorf("type %d: %d IEs, want %d", msg.Header.MessageType, len(v.IEs()), n)
+		}
+
+		wantNodeID, wantFSEID := viewedIDs(msg.Body)
+		nodeID, err := v.NodeID()
+		if wantNodeID == nil {
+			if err == nil {
+				t.Errorf("type %d: NodeID() = %+v, want error", msg.Header.MessageType, nodeID)
+			}
+		} else if err != nil || !reflect.DeepEqual(nodeID, wantNodeID) {
+			t.Errorf("type %d: NodeID() = %+v, %v, want %+v", msg.Header.MessageType, nodeID, err, wantNodeID)
+		}
+		fseid, err := v.FSEID()
+		if wantFSEID == nil {
+			if err == nil {
+				t.Errorf("type %d: FSEID() = %+v, want error", msg.Header.MessageType, fseid)
+			}
+		} else if err != nil || !reflect.DeepEqual(fseid, wantFSEID) {
+			t.Errorf("type %d: FSEID() = %+v, %v, want %+v", msg.Header.MessageType, fseid, err, wantFSEID)
+		}
+	}
+}
+
+// bodyIECount returns the number of top-level IEs a decoded body holds.
+func bodyIECount(body interface{}) int {
+	n := 0
+	v := reflect.ValueOf(body)
+	for i := 0; i < v.NumField(); i++ {
+		switch f := v.Field(i); f.Kind() {
+		case reflect.Pointer:
+			if !f.IsNil() {
+				n++
+			}
+		case reflect.Slice:
+			n += f.Len()
+		}
+	}
+	return n
+}
+
+func TestMessageViewTruncated(t *testing.T) {
+	for _, data := range testMessages(t) {
+		for n := 0; n < len(data); n++ {
+			if v, err := NewMessageView(data[:n]); err == nil {
+				t.Errorf("NewMessageView of %d of %d bytes = %+v, want error", n, len(data), v.Header)
+			}
+		}
+	}
+}
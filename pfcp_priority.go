@@ -0,0 +1,226 @@
+package pfcpgolb
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// MaxMessagePriority is the lowest message priority; 0 is the highest
+// (TS 29.244 5.22).
+const MaxMessagePriority uint8 = 15
+
+// Ranks of the messages in the receive queue, lower first
+const (
+	// Responses, node related requests and messages that failed to decode
+	rankImmediate = -1
+	// Session related requests without a message priority
+	rankUnprioritized = int(MaxMessagePriority) + 1
+)
+
+// SetPriority sets the message priority of a session related message, 0
+// being the highest priority.
+func (m *PFCPMessage) SetPriority(priority uint8) error {
+	if m.Header.Len() != 16 {
+		return fmt.Errorf("pfcp: message type %d has no message priority", m.Header.MessageType)
+	}
+	if priority > MaxMessagePriority {
+		return fmt.Errorf("pfcp: message priority %d exceeds %d", priority, MaxMessagePriority)
+	}
+	m.Header.MP = 1
+	m.Header.MessagePriority = priority
+	return nil
+}
+
+// ClearPriority removes the message priority of the message.
+func (m *PFCPMessage) ClearPriority() {
+	m.Header.MP = 0
+	m.Header.MessagePriority = 0
+}
+
+// Priority returns the message priority of the message, if it has one.
+func (m *PFCPMessage) Priority() (uint8, bool) {
+	if m.Header.MP&1 == 0 {
+		return 0, false
+	}
+	return m.Header.MessagePriority, true
+}
+
+type queuedMessage struct {
+	msg   *Message
+	err   error
+	rank  int
+	order uint64
+	index int
+}
+
+// receiveQueue is a heap of the received messages ordered by rank, then by
+// arrival.
+type receiveQueue []*queuedMessage
+
+func (q receiveQueue) Len() int { return len(q) }
+
+func (q receiveQueue) Less(i, j int) bool {
+	if q[i].rank != q[j].rank {
+		return q[i].rank < q[j].rank
+	}
+	return q[i].order < q[j].order
+}
+
+func (q receiveQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *receiveQueue) Push(x interface{}) {
+	item := x.(*queuedMessage)
+	item.index = len(*q)
+	*q = append(*q, item)
+}
+
+func (q *receiveQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return item
+}
+
+// worst returns the index of the request that would be returned last, or -1
+// when the queue holds no request that can be dropped.
+func (q receiveQueue) worst() int {
+	index := -1
+	for i, item := range q {
+		if item.rank == rankImmediate {
+			continue
+		}
+		if index < 0 || item.rank > q[index].rank ||
+			(item.rank == q[index].rank && item.order > q[index].order) {
+			index = i
+		}
+	}
+	return index
+}
+
+// messageRank returns the rank of a received message in the receive queue.
+func messageRank(msg *Message, err error) int {
+	pfcpMsg := msg.PfcpMessage
+	if err != nil || !pfcpMsg.IsRequest() || pfcpMsg.Header.Len() != 16 {
+		return rankImmediate
+	}
+	if priority, ok := pfcpMsg.Priority(); ok {
+		return int(priority)
+	}
+	return rankUnprioritized
+}
+
+// readQueued returns the next message of the receive queue, starting the
+// goroutine receiving the messages on the first call. It gives up when ctx is
+// done.
+func (pfcpServer *PfcpServer) readQueued(ctx context.Context) (*Message, error) {
+	pfcpServer.queueOnce.Do(func() {
+		pfcpServer.queueCond = sync.NewCond(&pfcpServer.queueMu)
+		go pfcpServer.receiveLoop()
+	})
+	stop := context.AfterFunc(ctx, func() {
+		pfcpServer.queueMu.Lock()
+		pfcpServer.queueCond.Broadcast()
+		pfcpServer.queueMu.Unlock()
+	})
+	defer stop()
+
+	pfcpServer.queueMu.Lock()
+	defer pfcpServer.queueMu.Unlock()
+	for len(pfcpServer.queue) == 0 && pfcpServer.queueErr == nil && ctx.Err() == nil {
+		pfcpServer.queueCond.Wait()
+	}
+	if len(pfcpServer.queue) == 0 {
+		if pfcpServer.queueErr != nil {
+			return nil, pfcpServer.queueErr
+		}
+		return nil, ctx.Err()
+	}
+	item := heap.Pop(&pfcpServer.queue).(*queuedMessage)
+	return item.msg, item.err
+}
+
+// receiveRetryDelay is the wait before reading again after a temporary
+// error, so that a read deadline left in the past does not spin the loop.
+const receiveRetryDelay = 10 * time.Millisecond
+
+// receiveLoop reads the messages as they arrive, so that the responses reach
+// their transactions at once, and queues them for readQueued until the
+// connection is closed or fails for good.
+func (pfcpServer *PfcpServer) receiveLoop() {
+	for {
+		msg, err := pfcpServer.readMessage()
+		if msg == nil {
+			if temporaryError(err) {
+				logger.Debugf("Read error: %+v", err)
+				time.Sleep(receiveRetryDelay)
+				continue
+			}
+			pfcpServer.queueMu.Lock()
+			pfcpServer.queueErr = err
+			pfcpServer.queueCond.Broadcast()
+			pfcpServer.queueMu.Unlock()
+			return
+		}
+		pfcpServer.enqueue(msg, err)
+	}
+}
+
+// enqueue adds a received message to the receive queue. When the queue is
+// full, the request returned last is dropped, leaving the peer to retransmit
+// it.
+func (pfcpServer *PfcpServer) enqueue(msg *Message, err error) {
+	item := &queuedMessage{msg: msg, err: err, rank: messageRank(msg, err)}
+
+	pfcpServer.queueMu.Lock()
+	defer pfcpServer.queueMu.Unlock()
+	pfcpServer.queueOrder++
+	item.order = pfcpServer.queueOrder
+
+	if item.rank != rankImmediate && len(pfcpServer.queue) >= pfcpServer.PriorityQueueSize {
+		worst := pfcpServer.queue.worst()
+		if worst < 0 || pfcpServer.queue[worst].rank <= item.rank {
+			pfcpServer.responses.forget(msg.RemoteAddr.String(), msg.PfcpMessage.Header.SequenceNumber)
+			logger.Warnf("Receive queue full: drop request type %d SEQ[%d] from %s",
+				msg.PfcpMessage.Header.MessageType, msg.PfcpMessage.Header.SequenceNumber, msg.RemoteAddr)
+			return
+		}
+		dropped := heap.Remove(&pfcpServer.queue, worst).(*queuedMessage)
+		// Let its retransmission through
+		pfcpServer.responses.forget(dropped.msg.RemoteAddr.String(), dropped.msg.PfcpMessage.Header.SequenceNumber)
+		logger.Warnf("Receive queue full: drop request type %d SEQ[%d] from %s",
+			dropped.msg.PfcpMessage.Header.MessageType, dropped.msg.PfcpMessage.Header.SequenceNumber,
+			dropped.msg.RemoteAddr)
+	}
+	heap.Push(&pfcpServer.queue, item)
+	pfcpServer.queueCond.Signal()
+}
+
+// temporaryError reports whether reading may succeed again after err, as
+// after a timeout or an ICMP error reported on a UDP socket.
+func temporaryError(err error) bool {
+	if errors.Is(err, net.ErrClosed) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
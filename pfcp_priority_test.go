@@ -0,0 +1,146 @@
+package pfcpgolb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newTestQueue returns a server with a receive queue of size requests, fed
+// by enqueue only.
+func newTestQueue(size int) *PfcpServer {
+	server := &PfcpServer{PriorityQueueSize: size}
+	server.queueOnce.Do(func() { server.queueCond = sync.NewCond(&server.queueMu) })
+	return server
+}
+
+// queuedRequest returns a session modification request from testCPAddr, with
+// message priority priority unless negative.
+func queuedRequest(seq uint32, priority int) *Message {
+	msg := &PFCPMessage{Header: Header{
+		Version:        PfcpVersion,
+		MessageType:    PFCP_SESSION_MODIFICATION_REQUEST,
+		S:              SEID_PRESENT,
+		SequenceNumber: seq,
+	}}
+	if priority >= 0 {
+		msg.SetPriority(uint8(priority))
+	}
+	return NewMessage(testCPAddr, msg)
+}
+
+// drain returns the sequence numbers of the messages of the queue of
+// server, in the order they are read.
+func drain(t *testing.T, server *PfcpServer) []uint32 {
+	t.Helper()
+	var seqs []uint32
+	for len(server.queue) > 0 {
+		msg, _ := server.readQueued(context.Background())
+		seqs = append(seqs, msg.PfcpMessage.Header.SequenceNumber)
+	}
+	return seqs
+}
+
+func TestReceiveQueueOrder(t *testing.T) {
+	server := newTestQueue(10)
+	heartbeat := heartbeatRequest()
+	heartbeat.Header.SequenceNumber = 3
+	for _, msg := range []*Message{
+		queuedRequest(1, 5),
+		queuedRequest(2, -1),
+		NewMessage(testCPAddr, heartbeat),
+		queuedRequest(4, 0),
+		queuedRequest(5, 5),
+	} {
+		server.enqueue(msg, nil)
+	}
+	// The node related requests first, then by priority and arrival, the
+	// requests without priority last
+	if seqs, want := drain(t, server), []uint32{3, 4, 1, 5, 2}; !slices.Equal(seqs, want) {
+		t.Errorf("messages read %v, want %v", seqs, want)
+	}
+}
+
+func TestReceiveQueueFull(t *testing.T) {
+	server := newTestQueue(2)
+	peer := testCPAddr.String()
+	enqueue := func(msg *Message) {
+		// Recorded on receipt as being processed
+		server.responses.begin(peer, msg.PfcpMessage.Header.SequenceNumber, time.Minute)
+		server.enqueue(msg, nil)
+	}
+	enqueue(queuedRequest(1, 5))
+	enqueue(queuedRequest(2, 3))
+	// Drops request 1, of the lowest priority
+	enqueue(queuedRequest(3, 0))
+	// Dropped itself, of a lower priority than the ones queued
+	enqueue(queuedRequest(4, 9))
+	// Queued beyond the size, not droppable
+	heartbeat := heartbeatRequest()
+	heartbeat.Header.SequenceNumber = 5
+	server.enqueue(NewMessage(testCPAddr, heartbeat), nil)
+
+	// The retransmissions of the dropped requests are let through
+	for seq, known := range map[uint32]bool{1: false, 2: true, 3: true, 4: false} {
+		if _, ok := server.responses.get(peer, seq); ok != known {
+			t.Errorf("request %d known %t, want %t", seq, ok, known)
+		}
+	}
+	if seqs, want := drain(t, server), []uint32{5, 3, 2}; !slices.Equal(seqs, want) {
+		t.Errorf("messages read %v, want %v", seqs, want)
+	}
+}
+
+// flakyConn fails the reads with temporary errors, then reads conn.
+type flakyConn struct {
+	net.PacketConn
+	failures atomic.Int32
+}
+
+func (c *flakyConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	switch c.failures.Add(1) {
+	case 1:
+		return 0, nil, &net.OpError{Op: "read", Net: "udp", Err: os.ErrDeadlineExceeded}
+	case 2:
+		return 0, nil, &net.OpError{Op: "read", Net: "udp", Err: os.NewSyscallError("recvfrom", syscall.ECONNREFUSED)}
+	}
+	return c.PacketConn.ReadFrom(b)
+}
+
+func TestReceiveLoopTemporaryErrors(t *testing.T) {
+	network, cp, _ := newTestServers(t)
+	conn, err := network.Listen(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 3).To4(), Port: PFCP_PORT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	up := NewPfcpServerConn(&flakyConn{PacketConn: conn})
+	up.PriorityQueueSize = 10
+	done := make(chan error, 1)
+	go func() {
+		done <- up.Serve(context.Background(), HandlerFunc(func(w ResponseWriter, req *Message) {
+			w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: time.Unix(1, 0)}})
+		}))
+	}()
+	serve(t, cp, NewServeMux())
+
+	// Still receiving after the errors
+	sendRequest(t, cp, conn.LocalAddr().(*net.UDPAddr), Header{MessageType: PFCP_HEARTBEAT_REQUEST},
+		heartbeatRequest().Body)
+
+	up.Close()
+	select {
+	case err := <-done:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Errorf("Serve error = %v, want %v", err, net.ErrClosed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve still running after Close")
+	}
+}
@@ -0,0 +1,310 @@
+package pfcpgolb
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// Handler answers the requests received by Serve.
+type Handler interface {
+	ServePFCP(w ResponseWriter, req *Message)
+}
+
+// HandlerFunc adapts a function to a Handler.
+type HandlerFunc func(w ResponseWriter, req *Message)
+
+func (f HandlerFunc) ServePFCP(w ResponseWriter, req *Message) {
+	f(w, req)
+}
+
+// Middleware wraps a Handler with processing around its requests.
+type Middleware func(Handler) Handler
+
+// Chain wraps h with mws, the first one being the outermost.
+func Chain(h Handler, mws ...Middleware) Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// ResponseWriter sends the response to the request passed to a Handler.
+type ResponseWriter interface {
+	// Write sends body as the response to the request.
+	Write(body interface{}) error
+	// WriteMessage sends res as the response to the request. Its version,
+	// message type and sequence number are filled from the request. Unless
+	// set, the SEID of a session related response is the SEID of the CP
+	// F-SEID of an establishment request, else the remote SEID of the
+	// Session of the request, else 0 as for an unknown session.
+	WriteMessage(res *PFCPMessage) error
+	// Reject answers the request with an error response.
+	Reject(cause uint8, offendingIE uint16) error
+	// Written reports whether the response has been sent.
+	Written() bool
+}
+
+type responseWriter struct {
+	server  *PfcpServer
+	req     *Message
+	mu      sync.Mutex
+	written bool
+}
+
+func (w *responseWriter) Write(body interface{}) error {
+	return w.WriteMessage(&PFCPMessage{Body: body})
+}
+
+func (w *responseWriter) WriteMessage(res *PFCPMessage) error {
+	return w.write(res, true)
+}
+
+// write sends res, filling its SEID when unset and fillSEID is true.
+func (w *responseWriter) write(res *PFCPMessage, fillSEID bool) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.written {
+		return errors.New("pfcp: response already written")
+	}
+
+	reqHeader := &w.req.PfcpMessage.Header
+	if res.Header.Version == 0 {
+		res.Header.Version = PfcpVersion
+	}
+	if res.Header.MessageType == 0 {
+		res.Header.MessageType = reqHeader.MessageType + 1
+	}
+	res.Header.SequenceNumber = reqHeader.SequenceNumber
+	if res.Header.Len() == 16 {
+		res.Header.S = SEID_PRESENT
+		if res.Header.SEID == 0 && fillSEID {
+			res.Header.SEID = responseSEID(w.req)
+		}
+	}
+
+	if err := w.server.writeResponse(res, w.req.RemoteAddr); err != nil {
+		return err
+	}
+	w.written = true
+	return nil
+}
+
+func (w *responseWriter) Reject(cause uint8, offendingIE uint16) error {
+	res, err := NewErrorResponse(w.req.PfcpMessage, cause, offendingIE, w.server.NodeID,
+		w.server.RecoveryTimeStamp)
+	if err != nil {
+		return err
+	}
+	// No session to take the SEID of the peer from
+	return w.write(res, cause != CauseSessionContextNotFound)
+}
+
+func (w *responseWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written
+}
+
+// responseSEID returns the SEID of the response to a session related request.
+func responseSEID(req *Message) uint64 {
+	if body, ok := req.PfcpMessage.Body.(PFCPSessionEstablishmentRequest); ok && body.CPFSEID != nil {
+		return body.CPFSEID.Seid
+	}
+	if req.Session != nil {
+		if remote := req.Session.RemoteFSEID(); remote != nil {
+			return remote.Seid
+		}
+	}
+	// The SEID of the request is ours, not the one of the peer
+	return 0
+}
+
+// ServeMux dispatches the requests to the Handler registered for their
+// message type.
+type ServeMux struct {
+	mu          sync.RWMutex
+	handlers    map[MessageType]Handler
+	middlewares []Middleware
+	// NotFound answers the requests without a handler. By default, they are
+	// rejected with CauseServiceNotSupported.
+	NotFound Handler
+	// Sessions, when set, routes the session related requests other than
+	// establishments to the session of their SEID, in the Session of the
+	// request. The requests for an unknown SEID are rejected with
+	// CauseSessionContextNotFound, and those without a SEID with
+	// CauseMandatoryIeIncorrect, after the middlewares.
+	Sessions *SessionTable
+}
+
+func NewServeMux() *ServeMux {
+	return &ServeMux{handlers: make(map[MessageType]Handler)}
+}
+
+// Handle registers h for the requests of type msgType.
+func (mux *ServeMux) Handle(msgType MessageType, h Handler) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	mux.handlers[msgType] = h
+}
+
+func (mux *ServeMux) HandleFunc(msgType MessageType, f func(w ResponseWriter, req *Message)) {
+	mux.Handle(msgType, HandlerFunc(f))
+}
+
+// Use adds middlewares around all the handlers, the first one being the
+// outermost.
+func (mux *ServeMux) Use(mws ...Middleware) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	mux.middlewares = append(mux.middlewares, mws...)
+}
+
+// Handler returns the handler of a request, wrapped in the middlewares.
+func (mux *ServeMux) Handler(req *Message) Handler {
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+	h, ok := mux.handlers[req.MessageType()]
+	if !ok {
+		h = mux.NotFound
+		if h == nil {
+			h = HandlerFunc(notSupported)
+		}
+	}
+	if mux.Sessions != nil {
+		h = routeSession(mux.Sessions, h)
+	}
+	return Chain(h, mux.middlewares...)
+}
+
+func (mux *ServeMux) ServePFCP(w ResponseWriter, req *Message) {
+	mux.Handler(req).ServePFCP(w, req)
+}
+
+// routeSession sets the session of the session related requests passed to h.
+// A request without the SEID of its session, its S flag unset, is rejected
+// with CauseMandatoryIeIncorrect.
+func routeSession(sessions *SessionTable, h Handler) Handler {
+	return HandlerFunc(func(w ResponseWriter, req *Message) {
+		header := &req.PfcpMessage.Header
+		if header.Len() != 16 || header.MessageType == PFCP_SESSION_ESTABLISHMENT_REQUEST {
+			h.ServePFCP(w, req)
+			return
+		}
+		if header.S&1 == 0 {
+			if err := w.Reject(CauseMandatoryIeIncorrect, 0); err != nil {
+				logger.Debugf("No SEID in message type %d from %s: %+v", header.MessageType, req.RemoteAddr, err)
+			}
+			return
+		}
+		s, ok := sessions.Lookup(header.SEID)
+		if !ok {
+			if err := w.Reject(CauseSessionContextNotFound, 0); err != nil {
+				logger.Debugf("No session with SEID %#x from %s: %+v", header.SEID, req.RemoteAddr, err)
+			}
+			return
+		}
+		req.Session = s
+		h.ServePFCP(w, req)
+	})
+}
+
+func notSupported(w ResponseWriter, req *Message) {
+	if err := w.Reject(CauseServiceNotSupported, 0); err != nil {
+		logger.Debugf("No handler for message type %d from %s: %+v", req.MessageType(), req.RemoteAddr, err)
+	}
+}
+
+// Serve reads the messages received and passes the new requests to handler,
+// on Config.Workers goroutines. Responses reach their transactions and
+// retransmitted requests are answered by the server itself. A handler that
+// panics is recovered, and its request rejected with CauseSystemFailure. A
+// request left without response is passed to handler again when
+// retransmitted.
+//
+// Serve returns when ctx is done or reading fails, once the running handlers
+// returned.
+func (pfcpServer *PfcpServer) Serve(ctx context.Context, handler Handler) error {
+	workers := pfcpServer.Config.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	reqs := make(chan *Message)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range reqs {
+				pfcpServer.serveRequest(handler, req)
+			}
+		}()
+	}
+	defer func() {
+		close(reqs)
+		wg.Wait()
+	}()
+
+	read := func() (*Message, error) {
+		return pfcpServer.readQueued(ctx)
+	}
+	if pfcpServer.PriorityQueueSize <= 0 {
+		read = pfcpServer.readMessage
+		// Unblock the pending read
+		stop := context.AfterFunc(ctx, func() {
+			if err := pfcpServer.Conn.SetReadDeadline(time.Now()); err != nil {
+				logger.Warnf("SetReadDeadline error: %+v", err)
+			}
+		})
+		defer func() {
+			if !stop() {
+				pfcpServer.Conn.SetReadDeadline(time.Time{})
+			}
+		}()
+	}
+
+	for {
+		msg, err := read()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		switch {
+		case msg == nil:
+			return err
+		case err != nil:
+			logger.Debugf("Read from %s: %+v", msg.RemoteAddr, err)
+		case msg.PfcpMessage.IsRequest():
+			select {
+			case reqs <- msg:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (pfcpServer *PfcpServer) serveRequest(handler Handler, req *Message) {
+	w := &responseWriter{server: pfcpServer, req: req}
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("PFCP handler panic on message type %d from %s: %v\n%s", req.MessageType(),
+				req.RemoteAddr, r, debug.Stack())
+			if !w.Written() {
+				if err := w.Reject(CauseSystemFailure, 0); err != nil {
+					logger.Warnf("Reject request error: %+v", err)
+				}
+			}
+		}
+		if !w.Written() {
+			// Handled anew when retransmitted
+			pfcpServer.responses.forget(req.RemoteAddr.String(), req.PfcpMessage.Header.SequenceNumber)
+		}
+	}()
+	handler.ServePFCP(w, req)
+}
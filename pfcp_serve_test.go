@@ -0,0 +1,302 @@
+package pfcpgolb
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb/memconn"
+)
+
+var (
+	testCPAddr = &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1).To4(), Port: PFCP_PORT}
+	testUPAddr = &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2).To4(), Port: PFCP_PORT}
+)
+
+// newTestServer returns a server listening on addr of network, closed at the
+// end of the test.
+func newTestServer(t *testing.T, network *memconn.Network, addr *net.UDPAddr) *PfcpServer {
+	t.Helper()
+	conn, err := network.Listen(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewPfcpServerConn(conn)
+	server.NodeID = &NodeID{NodeIdType: NodeIdTypeIpv4Address, IP: addr.IP.To4()}
+	server.RecoveryTimeStamp = time.Unix(time.Now().Unix(), 0)
+	t.Cleanup(func() { server.Close() })
+	return server
+}
+
+// newTestServers returns a CP function and a UP function server linked by an
+// in-memory network.
+func newTestServers(t *testing.T) (network *memconn.Network, cp, up *PfcpServer) {
+	t.Helper()
+	network = memconn.NewNetwork()
+	return network, newTestServer(t, network, testCPAddr), newTestServer(t, network, testUPAddr)
+}
+
+// serve runs server.Serve with handler until the end of the test.
+func serve(t *testing.T, server *PfcpServer, handler Handler) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx, handler) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// sendRequest sends the request of header and body from server to addr
+// and returns the response.
+func sendRequest(t *testing.T, server *PfcpServer, addr *net.UDPAddr, header Header, body interface{}) *PFCPMessage {
+	t.Helper()
+	header.Version = PfcpVersion
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := server.SendRequest(ctx, &PFCPMessage{Header: header, Body: body}, addr)
+	if err != nil {
+		t.Fatalf("SendRequest type %d: %v", header.MessageType, err)
+	}
+	return res.PfcpMessage
+}
+
+func TestServeMuxSessions(t *testing.T) {
+	network, cp, up := newTestServers(t)
+	table := NewSessionTable()
+	mux := NewServeMux()
+	mux.Sessions = table
+	mux.HandleFunc(PFCP_SESSION_ESTABLISHMENT_REQUEST, func(w ResponseWriter, req *Message) {
+		body := req.PfcpMessage.Body.(PFCPSessionEstablishmentRequest)
+		s, err := table.Establish(FSEID{V4: true, Ipv4Address: testUPAddr.IP}, &body)
+		if err != nil {
+			res, _ := NewSessionErrorResponse(req.PfcpMessage, err, up.NodeID)
+			w.WriteMessage(res)
+			return
+		}
+		w.Write(PFCPSessionEstablishmentResponse{
+			NodeID:  up.NodeID,
+			Cause:   &Cause{CauseValue: CauseRequestAccepted},
+			UPFSEID: s.LocalFSEID(),
+		})
+	})
+	mux.HandleFunc(PFCP_SESSION_MODIFICATION_REQUEST, func(w ResponseWriter, req *Message) {
+		body := req.PfcpMessage.Body.(PFCPSessionModificationRequest)
+		if _, err := table.Modify(req.Session.LocalFSEID().Seid, &body); err != nil {
+			res, _ := NewSessionErrorResponse(req.PfcpMessage, err, up.NodeID)
+			w.WriteMessage(res)
+			return
+		}
+		w.Write(PFCPSessionModificationResponse{Cause: &Cause{CauseValue: CauseRequestAccepted}})
+	})
+	up.RejectMalformedRequests = true
+	serve(t, up, mux)
+	serve(t, cp, NewServeMux())
+
+	est := testEstablishmentRequest()
+	res := sendRequest(t, cp, testUPAddr, Header{MessageType: PFCP_SESSION_ESTABLISHMENT_REQUEST, S: SEID_PRESENT}, est)
+	estRes := res.Body.(PFCPSessionEstablishmentResponse)
+	if estRes.Cause.CauseValue != CauseRequestAccepted || estRes.UPFSEID == nil {
+		t.Fatalf("establishment response = %+v", estRes)
+	}
+	if res.Header.SEID != est.CPFSEID.Seid {
+		t.Errorf("establishment response SEID = %#x, want %#x", res.Header.SEID, est.CPFSEID.Seid)
+	}
+	seid := estRes.UPFSEID.Seid
+
+	t.Run("known SEID", func(t *testing.T) {
+		mod := PFCPSessionModificationRequest{
+			UpdateQER: []*UpdateQER{{QERID: &QERID{QERID: 1}, GateStatus: &GateStatus{DLGate: GateClose}}},
+		}
+		res := sendRequest(t, cp, testUPAddr, Header{MessageType: PFCP_SESSION_MODIFICATION_REQUEST, S: SEID_PRESENT, SEID: seid}, mod)
+		if cause := res.Body.(PFCPSessionModificationResponse).Cause.CauseValue; cause != CauseRequestAccepted {
+			t.Fatalf("cause = %d, want %d", cause, CauseRequestAccepted)
+		}
+		if res.Header.SEID != est.CPFSEID.Seid {
+			t.Errorf("response SEID = %#x, want %#x", res.Header.SEID, est.CPFSEID.Seid)
+		}
+		s, _ := table.Lookup(seid)
+		if gate := s.Rules().QERs[1].GateStatus.DLGate; gate != GateClose {
+			t.Errorf("DL gate = %d, want %d", gate, GateClose)
+		}
+	})
+
+	t.Run("unknown SEID", func(t *testing.T) {
+		res := sendRequest(t, cp, testUPAddr, Header{MessageType: PFCP_SESSION_MODIFICATION_REQUEST, S: SEID_PRESENT, SEID: seid + 1},
+			PFCPSessionModificationRequest{})
+		if cause := res.Body.(PFCPSessionModificationResponse).Cause.CauseValue; cause != CauseSessionContextNotFound {
+			t.Fatalf("cause = %d, want %d", cause, CauseSessionContextNotFound)
+		}
+		if res.Header.SEID != 0 {
+			t.Errorf("response SEID = %#x, want 0", res.Header.SEID)
+		}
+	})
+
+	t.Run("no SEID", func(t *testing.T) {
+		conn, err := network.Listen(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 3).To4(), Port: PFCP_PORT})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		// A modification request with S unset, padded with an IE to the
+		// length of a session message header, rejected on decoding
+		data := []byte{0x20, byte(PFCP_SESSION_MODIFICATION_REQUEST), 0, 12, 0, 0, 9, 0, 0x00, 0xFF, 0, 4, 0, 0, 0, 0}
+		if _, err := conn.WriteTo(data, testUPAddr); err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, PFCP_MAX_UDP_LEN)
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res PFCPMessage
+		if err := res.Unmarshal(buf[:n]); err != nil {
+			t.Fatal(err)
+		}
+		if cause := res.Body.(PFCPSessionModificationResponse).Cause.CauseValue; cause != CauseMandatoryIeIncorrect {
+			t.Fatalf("cause = %d, want %d", cause, CauseMandatoryIeIncorrect)
+		}
+	})
+}
+
+func TestServeWorkers(t *testing.T) {
+	const workers = 2
+	_, cp, up := newTestServers(t)
+	up.Config.Workers = workers
+	started := make(chan struct{}, workers+1)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var running, maxRunning int
+	serve(t, up, HandlerFunc(func(w ResponseWriter, req *Message) {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		started <- struct{}{}
+		<-release
+		mu.Lock()
+		running--
+		mu.Unlock()
+		w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: up.RecoveryTimeStamp}})
+	}))
+	serve(t, cp, NewServeMux())
+
+	errs := make(chan error, workers+1)
+	for i := 0; i < workers+1; i++ {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err := cp.SendRequest(ctx, heartbeatRequest(), testUPAddr)
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < workers; i++ {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%d requests handled, want %d", i, workers)
+		}
+	}
+	// All the workers are busy, the last request waits for one of them
+	select {
+	case <-started:
+		t.Fatalf("more than %d requests handled concurrently", workers)
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+
+	for i := 0; i < workers+1; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+	if maxRunning != workers {
+		t.Errorf("%d requests handled concurrently, want %d", maxRunning, workers)
+	}
+}
+
+func TestServePanic(t *testing.T) {
+	network, cp, up := newTestServers(t)
+	mux := NewServeMux()
+	mux.HandleFunc(PFCP_ASSOCIATION_SETUP_REQUEST, func(w ResponseWriter, req *Message) {
+		panic("setup")
+	})
+	mux.HandleFunc(PFCP_HEARTBEAT_REQUEST, func(w ResponseWriter, req *Message) {
+		w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: up.RecoveryTimeStamp}})
+		panic("heartbeat")
+	})
+	up.Config.Workers = 1
+	serve(t, up, mux)
+	serve(t, cp, NewServeMux())
+
+	t.Run("rejected", func(t *testing.T) {
+		// Twice, the worker surviving the first panic
+		for i := 0; i < 2; i++ {
+			res := sendRequest(t, cp, testUPAddr, Header{MessageType: PFCP_ASSOCIATION_SETUP_REQUEST},
+				PFCPAssociationSetupRequest{
+					NodeID:            cp.NodeID,
+					RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: cp.RecoveryTimeStamp},
+				})
+			if cause := res.Body.(PFCPAssociationSetupResponse).Cause.CauseValue; cause != CauseSystemFailure {
+				t.Fatalf("cause = %d, want %d", cause, CauseSystemFailure)
+			}
+		}
+	})
+
+	t.Run("after response", func(t *testing.T) {
+		conn, err := network.Listen(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 3).To4(), Port: PFCP_PORT})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		req := heartbeatRequest()
+		req.Header.SequenceNumber = 1
+		data, err := req.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := exchange(t, conn, testUPAddr, data)
+		if _, ok := res.Body.(HeartbeatResponse); !ok {
+			t.Fatalf("response body = %T, want HeartbeatResponse", res.Body)
+		}
+		// No error response follows the one written
+		conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		if n, _, err := conn.ReadFrom(make([]byte, PFCP_MAX_UDP_LEN)); err == nil {
+			t.Errorf("second response of %d bytes", n)
+		}
+	})
+}
+
+func TestResponseSEIDWithoutSession(t *testing.T) {
+	_, cp, up := newTestServers(t)
+	mux := NewServeMux()
+	mux.HandleFunc(PFCP_SESSION_MODIFICATION_REQUEST, func(w ResponseWriter, req *Message) {
+		w.Write(PFCPSessionModificationResponse{Cause: &Cause{CauseValue: CauseRequestAccepted}})
+	})
+	mux.HandleFunc(PFCP_SESSION_DELETION_REQUEST, func(w ResponseWriter, req *Message) {
+		w.Reject(CauseRequestRejected, 0)
+	})
+	serve(t, up, mux)
+	serve(t, cp, NewServeMux())
+
+	// Without the F-SEID of the peer, the SEID of the request, which is the
+	// one of the UP function, is not echoed
+	for _, tc := range []struct {
+		msgType MessageType
+		body    interface{}
+	}{
+		{PFCP_SESSION_MODIFICATION_REQUEST, PFCPSessionModificationRequest{}},
+		{PFCP_SESSION_DELETION_REQUEST, PFCPSessionDeletionRequest{}},
+	} {
+		res := sendRequest(t, cp, testUPAddr, Header{MessageType: tc.msgType, S: SEID_PRESENT, SEID: 0x55}, tc.body)
+		if res.Header.S != SEID_PRESENT || res.Header.SEID != 0 {
+			t.Errorf("message type %d: response S %d SEID %#x, want S 1 SEID 0", res.Header.MessageType,
+				res.Header.S, res.Header.SEID)
+		}
+	}
+}
@@ -0,0 +1,3906 @@
+// Code generated by tlvgen. DO NOT EDIT.
+
+package pfcpgolb
+
+import "github.com/Nikhil690/pfcpgolb/tlv"
+
+func (v *CreateBAR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.BARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 88, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *CreateBAR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 88:
+			order.Add(0)
+			if v.BARID == nil {
+				v.BARID = new(BARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.BARID); err != nil {
+				return tlv.WrapDecodeError(err, "BARID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *CreateFAR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.FARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 108, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ApplyAction; ie != nil {
+		if b, err = tlv.AppendIE(b, 44, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ForwardingParameters; ie != nil {
+		if b, err = tlv.AppendIE(b, 4, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.DuplicatingParameters; ie != nil {
+		if b, err = tlv.AppendIE(b, 5, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.BARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 88, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *CreateFAR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 108:
+			order.Add(0)
+			if v.FARID == nil {
+				v.FARID = new(FARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FARID); err != nil {
+				return tlv.WrapDecodeError(err, "FARID", -1, ie, true)
+			}
+		case 44:
+			order.Add(1)
+			if v.ApplyAction == nil {
+				v.ApplyAction = new(ApplyAction)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ApplyAction); err != nil {
+				return tlv.WrapDecodeError(err, "ApplyAction", -1, ie, true)
+			}
+		case 4:
+			order.Add(2)
+			if v.ForwardingParameters == nil {
+				v.ForwardingParameters = new(ForwardingParametersIEInFAR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ForwardingParameters); err != nil {
+				return tlv.WrapDecodeError(err, "ForwardingParameters", -1, ie, false)
+			}
+		case 5:
+			order.Add(3)
+			if v.DuplicatingParameters == nil {
+				v.DuplicatingParameters = new(DuplicatingParameters)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.DuplicatingParameters); err != nil {
+				return tlv.WrapDecodeError(err, "DuplicatingParameters", -1, ie, false)
+			}
+		case 88:
+			order.Add(4)
+			if v.BARID == nil {
+				v.BARID = new(BARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.BARID); err != nil {
+				return tlv.WrapDecodeError(err, "BARID", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *CreatePDR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.PDRID; ie != nil {
+		if b, err = tlv.AppendIE(b, 56, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Precedence; ie != nil {
+		if b, err = tlv.AppendIE(b, 29, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.PDI; ie != nil {
+		if b, err = tlv.AppendIE(b, 2, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OuterHeaderRemoval; ie != nil {
+		if b, err = tlv.AppendIE(b, 95, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 108, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.URRID {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 81, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.QERID {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 109, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ActivatePredefinedRules; ie != nil {
+		if b, err = tlv.AppendIE(b, 106, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *CreatePDR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 56:
+			order.Add(0)
+			if v.PDRID == nil {
+				v.PDRID = new(PacketDetectionRuleID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PDRID); err != nil {
+				return tlv.WrapDecodeError(err, "PDRID", -1, ie, true)
+			}
+		case 29:
+			order.Add(1)
+			if v.Precedence == nil {
+				v.Precedence = new(Precedence)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Precedence); err != nil {
+				return tlv.WrapDecodeError(err, "Precedence", -1, ie, true)
+			}
+		case 2:
+			order.Add(2)
+			if v.PDI == nil {
+				v.PDI = new(PDI)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PDI); err != nil {
+				return tlv.WrapDecodeError(err, "PDI", -1, ie, true)
+			}
+		case 95:
+			order.Add(3)
+			if v.OuterHeaderRemoval == nil {
+				v.OuterHeaderRemoval = new(OuterHeaderRemoval)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OuterHeaderRemoval); err != nil {
+				return tlv.WrapDecodeError(err, "OuterHeaderRemoval", -1, ie, false)
+			}
+		case 108:
+			order.Add(4)
+			if v.FARID == nil {
+				v.FARID = new(FARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FARID); err != nil {
+				return tlv.WrapDecodeError(err, "FARID", -1, ie, false)
+			}
+		case 81:
+			order.Add(5)
+			elem := new(URRID)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "URRID", len(v.URRID), ie, false)
+			}
+			v.URRID = append(v.URRID, elem)
+		case 109:
+			order.Add(6)
+			elem := new(QERID)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "QERID", len(v.QERID), ie, false)
+			}
+			v.QERID = append(v.QERID, elem)
+		case 106:
+			order.Add(7)
+			if v.ActivatePredefinedRules == nil {
+				v.ActivatePredefinedRules = new(ActivatePredefinedRules)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ActivatePredefinedRules); err != nil {
+				return tlv.WrapDecodeError(err, "ActivatePredefinedRules", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *CreateQER) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.QERID; ie != nil {
+		if b, err = tlv.AppendIE(b, 109, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.QERCorrelationID; ie != nil {
+		if b, err = tlv.AppendIE(b, 28, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.GateStatus; ie != nil {
+		if b, err = tlv.AppendIE(b, 25, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.MaximumBitrate; ie != nil {
+		if b, err = tlv.AppendIE(b, 26, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.GuaranteedBitrate; ie != nil {
+		if b, err = tlv.AppendIE(b, 27, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.PacketRate; ie != nil {
+		if b, err = tlv.AppendIE(b, 94, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.DLFlowLevelMarking; ie != nil {
+		if b, err = tlv.AppendIE(b, 97, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.QoSFlowIdentifier; ie != nil {
+		if b, err = tlv.AppendIE(b, 124, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ReflectiveQoS; ie != nil {
+		if b, err = tlv.AppendIE(b, 123, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *CreateQER) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 109:
+			order.Add(0)
+			if v.QERID == nil {
+				v.QERID = new(QERID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.QERID); err != nil {
+				return tlv.WrapDecodeError(err, "QERID", -1, ie, true)
+			}
+		case 28:
+			order.Add(1)
+			if v.QERCorrelationID == nil {
+				v.QERCorrelationID = new(QERCorrelationID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.QERCorrelationID); err != nil {
+				return tlv.WrapDecodeError(err, "QERCorrelationID", -1, ie, false)
+			}
+		case 25:
+			order.Add(2)
+			if v.GateStatus == nil {
+				v.GateStatus = new(GateStatus)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.GateStatus); err != nil {
+				return tlv.WrapDecodeError(err, "GateStatus", -1, ie, true)
+			}
+		case 26:
+			order.Add(3)
+			if v.MaximumBitrate == nil {
+				v.MaximumBitrate = new(MBR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.MaximumBitrate); err != nil {
+				return tlv.WrapDecodeError(err, "MaximumBitrate", -1, ie, false)
+			}
+		case 27:
+			order.Add(4)
+			if v.GuaranteedBitrate == nil {
+				v.GuaranteedBitrate = new(GBR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.GuaranteedBitrate); err != nil {
+				return tlv.WrapDecodeError(err, "GuaranteedBitrate", -1, ie, false)
+			}
+		case 94:
+			order.Add(5)
+			if v.PacketRate == nil {
+				v.PacketRate = new(PacketRate)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PacketRate); err != nil {
+				return tlv.WrapDecodeError(err, "PacketRate", -1, ie, false)
+			}
+		case 97:
+			order.Add(6)
+			if v.DLFlowLevelMarking == nil {
+				v.DLFlowLevelMarking = new(DLFlowLevelMarking)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.DLFlowLevelMarking); err != nil {
+				return tlv.WrapDecodeError(err, "DLFlowLevelMarking", -1, ie, false)
+			}
+		case 124:
+			order.Add(7)
+			if v.QoSFlowIdentifier == nil {
+				v.QoSFlowIdentifier = new(QFI)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.QoSFlowIdentifier); err != nil {
+				return tlv.WrapDecodeError(err, "QoSFlowIdentifier", -1, ie, false)
+			}
+		case 123:
+			order.Add(8)
+			if v.ReflectiveQoS == nil {
+				v.ReflectiveQoS = new(RQI)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ReflectiveQoS); err != nil {
+				return tlv.WrapDecodeError(err, "ReflectiveQoS", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *CreateTrafficEndpoint) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.TrafficEndpointID; ie != nil {
+		if b, err = tlv.AppendIE(b, 131, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LocalFTEID; ie != nil {
+		if b, err = tlv.AppendIE(b, 21, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.NetworkInstance; ie != nil {
+		if b, err = tlv.AppendIE(b, 22, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UEIPAddress; ie != nil {
+		if b, err = tlv.AppendIE(b, 93, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.EthernetPDUSessionInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 142, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FramedRoute; ie != nil {
+		if b, err = tlv.AppendIE(b, 153, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FramedRouting; ie != nil {
+		if b, err = tlv.AppendIE(b, 154, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FramedIPv6Route; ie != nil {
+		if b, err = tlv.AppendIE(b, 155, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *CreateTrafficEndpoint) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 131:
+			order.Add(0)
+			if v.TrafficEndpointID == nil {
+				v.TrafficEndpointID = new(TrafficEndpointID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.TrafficEndpointID); err != nil {
+				return tlv.WrapDecodeError(err, "TrafficEndpointID", -1, ie, true)
+			}
+		case 21:
+			order.Add(1)
+			if v.LocalFTEID == nil {
+				v.LocalFTEID = new(FTEID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LocalFTEID); err != nil {
+				return tlv.WrapDecodeError(err, "LocalFTEID", -1, ie, false)
+			}
+		case 22:
+			order.Add(2)
+			if v.NetworkInstance == nil {
+				v.NetworkInstance = new(NetworkInstance)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NetworkInstance); err != nil {
+				return tlv.WrapDecodeError(err, "NetworkInstance", -1, ie, false)
+			}
+		case 93:
+			order.Add(3)
+			if v.UEIPAddress == nil {
+				v.UEIPAddress = new(UEIPAddress)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UEIPAddress); err != nil {
+				return tlv.WrapDecodeError(err, "UEIPAddress", -1, ie, false)
+			}
+		case 142:
+			order.Add(4)
+			if v.EthernetPDUSessionInformation == nil {
+				v.EthernetPDUSessionInformation = new(EthernetPDUSessionInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.EthernetPDUSessionInformation); err != nil {
+				return tlv.WrapDecodeError(err, "EthernetPDUSessionInformation", -1, ie, false)
+			}
+		case 153:
+			order.Add(5)
+			if v.FramedRoute == nil {
+				v.FramedRoute = new(FramedRoute)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FramedRoute); err != nil {
+				return tlv.WrapDecodeError(err, "FramedRoute", -1, ie, false)
+			}
+		case 154:
+			order.Add(6)
+			if v.FramedRouting == nil {
+				v.FramedRouting = new(FramedRouting)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FramedRouting); err != nil {
+				return tlv.WrapDecodeError(err, "FramedRouting", -1, ie, false)
+			}
+		case 155:
+			order.Add(7)
+			if v.FramedIPv6Route == nil {
+				v.FramedIPv6Route = new(FramedIPv6Route)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FramedIPv6Route); err != nil {
+				return tlv.WrapDecodeError(err, "FramedIPv6Route", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *CreateURR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.URRID; ie != nil {
+		if b, err = tlv.AppendIE(b, 81, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *CreateURR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 81:
+			order.Add(0)
+			if v.URRID == nil {
+				v.URRID = new(URRID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.URRID); err != nil {
+				return tlv.WrapDecodeError(err, "URRID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *CreatedPDR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.PDRID; ie != nil {
+		if b, err = tlv.AppendIE(b, 56, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LocalFTEID; ie != nil {
+		if b, err = tlv.AppendIE(b, 21, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *CreatedPDR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 56:
+			order.Add(0)
+			if v.PDRID == nil {
+				v.PDRID = new(PacketDetectionRuleID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PDRID); err != nil {
+				return tlv.WrapDecodeError(err, "PDRID", -1, ie, true)
+			}
+		case 21:
+			order.Add(1)
+			if v.LocalFTEID == nil {
+				v.LocalFTEID = new(FTEID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LocalFTEID); err != nil {
+				return tlv.WrapDecodeError(err, "LocalFTEID", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *CreatedTrafficEndpoint) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.TrafficEndpointID; ie != nil {
+		if b, err = tlv.AppendIE(b, 131, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LocalFTEID; ie != nil {
+		if b, err = tlv.AppendIE(b, 21, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *CreatedTrafficEndpoint) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 131:
+			order.Add(0)
+			if v.TrafficEndpointID == nil {
+				v.TrafficEndpointID = new(TrafficEndpointID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.TrafficEndpointID); err != nil {
+				return tlv.WrapDecodeError(err, "TrafficEndpointID", -1, ie, true)
+			}
+		case 21:
+			order.Add(1)
+			if v.LocalFTEID == nil {
+				v.LocalFTEID = new(FTEID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LocalFTEID); err != nil {
+				return tlv.WrapDecodeError(err, "LocalFTEID", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *EthernetPacketFilter) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.EthernetFilterID; ie != nil {
+		if b, err = tlv.AppendIE(b, 138, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.EthernetFilterProperties; ie != nil {
+		if b, err = tlv.AppendIE(b, 139, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.MACAddress; ie != nil {
+		if b, err = tlv.AppendIE(b, 133, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Ethertype; ie != nil {
+		if b, err = tlv.AppendIE(b, 136, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CTAG; ie != nil {
+		if b, err = tlv.AppendIE(b, 134, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.STAG; ie != nil {
+		if b, err = tlv.AppendIE(b, 135, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.SDFFilter; ie != nil {
+		if b, err = tlv.AppendIE(b, 23, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *EthernetPacketFilter) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 138:
+			order.Add(0)
+			if v.EthernetFilterID == nil {
+				v.EthernetFilterID = new(EthernetFilterID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.EthernetFilterID); err != nil {
+				return tlv.WrapDecodeError(err, "EthernetFilterID", -1, ie, false)
+			}
+		case 139:
+			order.Add(1)
+			if v.EthernetFilterProperties == nil {
+				v.EthernetFilterProperties = new(EthernetFilterProperties)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.EthernetFilterProperties); err != nil {
+				return tlv.WrapDecodeError(err, "EthernetFilterProperties", -1, ie, false)
+			}
+		case 133:
+			order.Add(2)
+			if v.MACAddress == nil {
+				v.MACAddress = new(MACAddress)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.MACAddress); err != nil {
+				return tlv.WrapDecodeError(err, "MACAddress", -1, ie, false)
+			}
+		case 136:
+			order.Add(3)
+			if v.Ethertype == nil {
+				v.Ethertype = new(Ethertype)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Ethertype); err != nil {
+				return tlv.WrapDecodeError(err, "Ethertype", -1, ie, false)
+			}
+		case 134:
+			order.Add(4)
+			if v.CTAG == nil {
+				v.CTAG = new(CTAG)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CTAG); err != nil {
+				return tlv.WrapDecodeError(err, "CTAG", -1, ie, false)
+			}
+		case 135:
+			order.Add(5)
+			if v.STAG == nil {
+				v.STAG = new(STAG)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.STAG); err != nil {
+				return tlv.WrapDecodeError(err, "STAG", -1, ie, false)
+			}
+		case 23:
+			order.Add(6)
+			if v.SDFFilter == nil {
+				v.SDFFilter = new(SDFFilter)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.SDFFilter); err != nil {
+				return tlv.WrapDecodeError(err, "SDFFilter", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *ForwardingParametersIEInFAR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.DestinationInterface; ie != nil {
+		if b, err = tlv.AppendIE(b, 42, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.NetworkInstance; ie != nil {
+		if b, err = tlv.AppendIE(b, 22, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.RedirectInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 38, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OuterHeaderCreation; ie != nil {
+		if b, err = tlv.AppendIE(b, 84, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.TransportLevelMarking; ie != nil {
+		if b, err = tlv.AppendIE(b, 30, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ForwardingPolicy; ie != nil {
+		if b, err = tlv.AppendIE(b, 41, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.HeaderEnrichment; ie != nil {
+		if b, err = tlv.AppendIE(b, 98, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LinkedTrafficEndpointID; ie != nil {
+		if b, err = tlv.AppendIE(b, 131, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Proxying; ie != nil {
+		if b, err = tlv.AppendIE(b, 137, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *ForwardingParametersIEInFAR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 42:
+			order.Add(0)
+			if v.DestinationInterface == nil {
+				v.DestinationInterface = new(DestinationInterface)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.DestinationInterface); err != nil {
+				return tlv.WrapDecodeError(err, "DestinationInterface", -1, ie, true)
+			}
+		case 22:
+			order.Add(1)
+			if v.NetworkInstance == nil {
+				v.NetworkInstance = new(NetworkInstance)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NetworkInstance); err != nil {
+				return tlv.WrapDecodeError(err, "NetworkInstance", -1, ie, false)
+			}
+		case 38:
+			order.Add(2)
+			if v.RedirectInformation == nil {
+				v.RedirectInformation = new(RedirectInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.RedirectInformation); err != nil {
+				return tlv.WrapDecodeError(err, "RedirectInformation", -1, ie, false)
+			}
+		case 84:
+			order.Add(3)
+			if v.OuterHeaderCreation == nil {
+				v.OuterHeaderCreation = new(OuterHeaderCreation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OuterHeaderCreation); err != nil {
+				return tlv.WrapDecodeError(err, "OuterHeaderCreation", -1, ie, false)
+			}
+		case 30:
+			order.Add(4)
+			if v.TransportLevelMarking == nil {
+				v.TransportLevelMarking = new(TransportLevelMarking)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.TransportLevelMarking); err != nil {
+				return tlv.WrapDecodeError(err, "TransportLevelMarking", -1, ie, false)
+			}
+		case 41:
+			order.Add(5)
+			if v.ForwardingPolicy == nil {
+				v.ForwardingPolicy = new(ForwardingPolicy)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ForwardingPolicy); err != nil {
+				return tlv.WrapDecodeError(err, "ForwardingPolicy", -1, ie, false)
+			}
+		case 98:
+			order.Add(6)
+			if v.HeaderEnrichment == nil {
+				v.HeaderEnrichment = new(HeaderEnrichment)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.HeaderEnrichment); err != nil {
+				return tlv.WrapDecodeError(err, "HeaderEnrichment", -1, ie, false)
+			}
+		case 131:
+			order.Add(7)
+			if v.LinkedTrafficEndpointID == nil {
+				v.LinkedTrafficEndpointID = new(TrafficEndpointID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LinkedTrafficEndpointID); err != nil {
+				return tlv.WrapDecodeError(err, "LinkedTrafficEndpointID", -1, ie, false)
+			}
+		case 137:
+			order.Add(8)
+			if v.Proxying == nil {
+				v.Proxying = new(Proxying)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Proxying); err != nil {
+				return tlv.WrapDecodeError(err, "Proxying", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *HeartbeatRequest) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.RecoveryTimeStamp; ie != nil {
+		if b, err = tlv.AppendIE(b, 96, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *HeartbeatRequest) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 96:
+			order.Add(0)
+			if v.RecoveryTimeStamp == nil {
+				v.RecoveryTimeStamp = new(RecoveryTimeStamp)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.RecoveryTimeStamp); err != nil {
+				return tlv.WrapDecodeError(err, "RecoveryTimeStamp", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *HeartbeatResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.RecoveryTimeStamp; ie != nil {
+		if b, err = tlv.AppendIE(b, 96, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *HeartbeatResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 96:
+			order.Add(0)
+			if v.RecoveryTimeStamp == nil {
+				v.RecoveryTimeStamp = new(RecoveryTimeStamp)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.RecoveryTimeStamp); err != nil {
+				return tlv.WrapDecodeError(err, "RecoveryTimeStamp", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *LoadControlInformation) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.LoadControlSequenceNumber; ie != nil {
+		if b, err = tlv.AppendIE(b, 52, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *LoadControlInformation) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 52:
+			order.Add(0)
+			if v.LoadControlSequenceNumber == nil {
+				v.LoadControlSequenceNumber = new(SequenceNumber)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LoadControlSequenceNumber); err != nil {
+				return tlv.WrapDecodeError(err, "LoadControlSequenceNumber", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PDI) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.SourceInterface; ie != nil {
+		if b, err = tlv.AppendIE(b, 20, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LocalFTEID; ie != nil {
+		if b, err = tlv.AppendIE(b, 21, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.NetworkInstance; ie != nil {
+		if b, err = tlv.AppendIE(b, 22, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UEIPAddress; ie != nil {
+		if b, err = tlv.AppendIE(b, 93, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.TrafficEndpointID; ie != nil {
+		if b, err = tlv.AppendIE(b, 131, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.SDFFilter; ie != nil {
+		if b, err = tlv.AppendIE(b, 23, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ApplicationID; ie != nil {
+		if b, err = tlv.AppendIE(b, 24, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.EthernetPDUSessionInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 142, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.EthernetPacketFilter; ie != nil {
+		if b, err = tlv.AppendIE(b, 132, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.QFI {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 124, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FramedRoute; ie != nil {
+		if b, err = tlv.AppendIE(b, 153, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FramedRouting; ie != nil {
+		if b, err = tlv.AppendIE(b, 154, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FramedIPv6Route; ie != nil {
+		if b, err = tlv.AppendIE(b, 155, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PDI) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 20:
+			order.Add(0)
+			if v.SourceInterface == nil {
+				v.SourceInterface = new(SourceInterface)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.SourceInterface); err != nil {
+				return tlv.WrapDecodeError(err, "SourceInterface", -1, ie, true)
+			}
+		case 21:
+			order.Add(1)
+			if v.LocalFTEID == nil {
+				v.LocalFTEID = new(FTEID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LocalFTEID); err != nil {
+				return tlv.WrapDecodeError(err, "LocalFTEID", -1, ie, false)
+			}
+		case 22:
+			order.Add(2)
+			if v.NetworkInstance == nil {
+				v.NetworkInstance = new(NetworkInstance)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NetworkInstance); err != nil {
+				return tlv.WrapDecodeError(err, "NetworkInstance", -1, ie, false)
+			}
+		case 93:
+			order.Add(3)
+			if v.UEIPAddress == nil {
+				v.UEIPAddress = new(UEIPAddress)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UEIPAddress); err != nil {
+				return tlv.WrapDecodeError(err, "UEIPAddress", -1, ie, false)
+			}
+		case 131:
+			order.Add(4)
+			if v.TrafficEndpointID == nil {
+				v.TrafficEndpointID = new(TrafficEndpointID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.TrafficEndpointID); err != nil {
+				return tlv.WrapDecodeError(err, "TrafficEndpointID", -1, ie, false)
+			}
+		case 23:
+			order.Add(5)
+			if v.SDFFilter == nil {
+				v.SDFFilter = new(SDFFilter)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.SDFFilter); err != nil {
+				return tlv.WrapDecodeError(err, "SDFFilter", -1, ie, false)
+			}
+		case 24:
+			order.Add(6)
+			if v.ApplicationID == nil {
+				v.ApplicationID = new(ApplicationID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ApplicationID); err != nil {
+				return tlv.WrapDecodeError(err, "ApplicationID", -1, ie, false)
+			}
+		case 142:
+			order.Add(7)
+			if v.EthernetPDUSessionInformation == nil {
+				v.EthernetPDUSessionInformation = new(EthernetPDUSessionInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.EthernetPDUSessionInformation); err != nil {
+				return tlv.WrapDecodeError(err, "EthernetPDUSessionInformation", -1, ie, false)
+			}
+		case 132:
+			order.Add(8)
+			if v.EthernetPacketFilter == nil {
+				v.EthernetPacketFilter = new(EthernetPacketFilter)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.EthernetPacketFilter); err != nil {
+				return tlv.WrapDecodeError(err, "EthernetPacketFilter", -1, ie, false)
+			}
+		case 124:
+			order.Add(9)
+			elem := new(QFI)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "QFI", len(v.QFI), ie, false)
+			}
+			v.QFI = append(v.QFI, elem)
+		case 153:
+			order.Add(10)
+			if v.FramedRoute == nil {
+				v.FramedRoute = new(FramedRoute)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FramedRoute); err != nil {
+				return tlv.WrapDecodeError(err, "FramedRoute", -1, ie, false)
+			}
+		case 154:
+			order.Add(11)
+			if v.FramedRouting == nil {
+				v.FramedRouting = new(FramedRouting)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FramedRouting); err != nil {
+				return tlv.WrapDecodeError(err, "FramedRouting", -1, ie, false)
+			}
+		case 155:
+			order.Add(12)
+			if v.FramedIPv6Route == nil {
+				v.FramedIPv6Route = new(FramedIPv6Route)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FramedIPv6Route); err != nil {
+				return tlv.WrapDecodeError(err, "FramedIPv6Route", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPAssociationReleaseRequest) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPAssociationReleaseRequest) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPAssociationReleaseResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPAssociationReleaseResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		case 19:
+			order.Add(1)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPAssociationSetupRequest) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.RecoveryTimeStamp; ie != nil {
+		if b, err = tlv.AppendIE(b, 96, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UPFunctionFeatures; ie != nil {
+		if b, err = tlv.AppendIE(b, 43, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CPFunctionFeatures; ie != nil {
+		if b, err = tlv.AppendIE(b, 89, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UserPlaneIPResourceInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 116, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPAssociationSetupRequest) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		case 96:
+			order.Add(1)
+			if v.RecoveryTimeStamp == nil {
+				v.RecoveryTimeStamp = new(RecoveryTimeStamp)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.RecoveryTimeStamp); err != nil {
+				return tlv.WrapDecodeError(err, "RecoveryTimeStamp", -1, ie, true)
+			}
+		case 43:
+			order.Add(2)
+			if v.UPFunctionFeatures == nil {
+				v.UPFunctionFeatures = new(UPFunctionFeatures)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UPFunctionFeatures); err != nil {
+				return tlv.WrapDecodeError(err, "UPFunctionFeatures", -1, ie, false)
+			}
+		case 89:
+			order.Add(3)
+			if v.CPFunctionFeatures == nil {
+				v.CPFunctionFeatures = new(CPFunctionFeatures)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CPFunctionFeatures); err != nil {
+				return tlv.WrapDecodeError(err, "CPFunctionFeatures", -1, ie, false)
+			}
+		case 116:
+			order.Add(4)
+			if v.UserPlaneIPResourceInformation == nil {
+				v.UserPlaneIPResourceInformation = new(UserPlaneIPResourceInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UserPlaneIPResourceInformation); err != nil {
+				return tlv.WrapDecodeError(err, "UserPlaneIPResourceInformation", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPAssociationSetupResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.RecoveryTimeStamp; ie != nil {
+		if b, err = tlv.AppendIE(b, 96, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UPFunctionFeatures; ie != nil {
+		if b, err = tlv.AppendIE(b, 43, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CPFunctionFeatures; ie != nil {
+		if b, err = tlv.AppendIE(b, 89, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UserPlaneIPResourceInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 116, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPAssociationSetupResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		case 19:
+			order.Add(1)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		case 96:
+			order.Add(2)
+			if v.RecoveryTimeStamp == nil {
+				v.RecoveryTimeStamp = new(RecoveryTimeStamp)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.RecoveryTimeStamp); err != nil {
+				return tlv.WrapDecodeError(err, "RecoveryTimeStamp", -1, ie, true)
+			}
+		case 43:
+			order.Add(3)
+			if v.UPFunctionFeatures == nil {
+				v.UPFunctionFeatures = new(UPFunctionFeatures)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UPFunctionFeatures); err != nil {
+				return tlv.WrapDecodeError(err, "UPFunctionFeatures", -1, ie, false)
+			}
+		case 89:
+			order.Add(4)
+			if v.CPFunctionFeatures == nil {
+				v.CPFunctionFeatures = new(CPFunctionFeatures)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CPFunctionFeatures); err != nil {
+				return tlv.WrapDecodeError(err, "CPFunctionFeatures", -1, ie, false)
+			}
+		case 116:
+			order.Add(5)
+			if v.UserPlaneIPResourceInformation == nil {
+				v.UserPlaneIPResourceInformation = new(UserPlaneIPResourceInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UserPlaneIPResourceInformation); err != nil {
+				return tlv.WrapDecodeError(err, "UserPlaneIPResourceInformation", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPAssociationUpdateRequest) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UPFunctionFeatures; ie != nil {
+		if b, err = tlv.AppendIE(b, 43, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CPFunctionFeatures; ie != nil {
+		if b, err = tlv.AppendIE(b, 89, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UserPlaneIPResourceInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 116, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPAssociationUpdateRequest) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		case 43:
+			order.Add(1)
+			if v.UPFunctionFeatures == nil {
+				v.UPFunctionFeatures = new(UPFunctionFeatures)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UPFunctionFeatures); err != nil {
+				return tlv.WrapDecodeError(err, "UPFunctionFeatures", -1, ie, false)
+			}
+		case 89:
+			order.Add(2)
+			if v.CPFunctionFeatures == nil {
+				v.CPFunctionFeatures = new(CPFunctionFeatures)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CPFunctionFeatures); err != nil {
+				return tlv.WrapDecodeError(err, "CPFunctionFeatures", -1, ie, false)
+			}
+		case 116:
+			order.Add(3)
+			if v.UserPlaneIPResourceInformation == nil {
+				v.UserPlaneIPResourceInformation = new(UserPlaneIPResourceInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UserPlaneIPResourceInformation); err != nil {
+				return tlv.WrapDecodeError(err, "UserPlaneIPResourceInformation", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPAssociationUpdateResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UPFunctionFeatures; ie != nil {
+		if b, err = tlv.AppendIE(b, 43, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CPFunctionFeatures; ie != nil {
+		if b, err = tlv.AppendIE(b, 89, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPAssociationUpdateResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		case 19:
+			order.Add(1)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		case 43:
+			order.Add(2)
+			if v.UPFunctionFeatures == nil {
+				v.UPFunctionFeatures = new(UPFunctionFeatures)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UPFunctionFeatures); err != nil {
+				return tlv.WrapDecodeError(err, "UPFunctionFeatures", -1, ie, false)
+			}
+		case 89:
+			order.Add(3)
+			if v.CPFunctionFeatures == nil {
+				v.CPFunctionFeatures = new(CPFunctionFeatures)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CPFunctionFeatures); err != nil {
+				return tlv.WrapDecodeError(err, "CPFunctionFeatures", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPNodeReportResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OffendingIE; ie != nil {
+		if b, err = tlv.AppendIE(b, 40, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPNodeReportResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		case 19:
+			order.Add(1)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		case 40:
+			order.Add(2)
+			if v.OffendingIE == nil {
+				v.OffendingIE = new(OffendingIE)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OffendingIE); err != nil {
+				return tlv.WrapDecodeError(err, "OffendingIE", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPPFDManagementResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OffendingIE; ie != nil {
+		if b, err = tlv.AppendIE(b, 40, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPPFDManagementResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 19:
+			order.Add(0)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		case 40:
+			order.Add(1)
+			if v.OffendingIE == nil {
+				v.OffendingIE = new(OffendingIE)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OffendingIE); err != nil {
+				return tlv.WrapDecodeError(err, "OffendingIE", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPSessionDeletionRequest) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	return b, nil
+}
+
+func (v *PFCPSessionDeletionRequest) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		v.UnknownIEs = append(v.UnknownIEs, ie)
+	}
+	v.IEOrder = tlv.Order{}
+	return nil
+}
+
+func (v *PFCPSessionDeletionResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OffendingIE; ie != nil {
+		if b, err = tlv.AppendIE(b, 40, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LoadControlInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 51, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPSessionDeletionResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 19:
+			order.Add(0)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		case 40:
+			order.Add(1)
+			if v.OffendingIE == nil {
+				v.OffendingIE = new(OffendingIE)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OffendingIE); err != nil {
+				return tlv.WrapDecodeError(err, "OffendingIE", -1, ie, false)
+			}
+		case 51:
+			order.Add(2)
+			if v.LoadControlInformation == nil {
+				v.LoadControlInformation = new(LoadControlInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LoadControlInformation); err != nil {
+				return tlv.WrapDecodeError(err, "LoadControlInformation", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPSessionEstablishmentRequest) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CPFSEID; ie != nil {
+		if b, err = tlv.AppendIE(b, 57, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.CreatePDR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 1, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.CreateFAR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 3, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.CreateURR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 6, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.CreateQER {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 7, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CreateBAR; ie != nil {
+		if b, err = tlv.AppendIE(b, 85, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CreateTrafficEndpoint; ie != nil {
+		if b, err = tlv.AppendIE(b, 127, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.PDNType; ie != nil {
+		if b, err = tlv.AppendIE(b, 113, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UserPlaneInactivityTimer; ie != nil {
+		if b, err = tlv.AppendIE(b, 117, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UserID; ie != nil {
+		if b, err = tlv.AppendIE(b, 141, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.TraceInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 152, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPSessionEstablishmentRequest) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		case 57:
+			order.Add(1)
+			if v.CPFSEID == nil {
+				v.CPFSEID = new(FSEID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CPFSEID); err != nil {
+				return tlv.WrapDecodeError(err, "CPFSEID", -1, ie, true)
+			}
+		case 1:
+			order.Add(2)
+			elem := new(CreatePDR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "CreatePDR", len(v.CreatePDR), ie, true)
+			}
+			v.CreatePDR = append(v.CreatePDR, elem)
+		case 3:
+			order.Add(3)
+			elem := new(CreateFAR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "CreateFAR", len(v.CreateFAR), ie, true)
+			}
+			v.CreateFAR = append(v.CreateFAR, elem)
+		case 6:
+			order.Add(4)
+			elem := new(CreateURR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "CreateURR", len(v.CreateURR), ie, false)
+			}
+			v.CreateURR = append(v.CreateURR, elem)
+		case 7:
+			order.Add(5)
+			elem := new(CreateQER)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "CreateQER", len(v.CreateQER), ie, false)
+			}
+			v.CreateQER = append(v.CreateQER, elem)
+		case 85:
+			order.Add(6)
+			if v.CreateBAR == nil {
+				v.CreateBAR = new(CreateBAR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CreateBAR); err != nil {
+				return tlv.WrapDecodeError(err, "CreateBAR", -1, ie, false)
+			}
+		case 127:
+			order.Add(7)
+			if v.CreateTrafficEndpoint == nil {
+				v.CreateTrafficEndpoint = new(CreateTrafficEndpoint)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CreateTrafficEndpoint); err != nil {
+				return tlv.WrapDecodeError(err, "CreateTrafficEndpoint", -1, ie, false)
+			}
+		case 113:
+			order.Add(8)
+			if v.PDNType == nil {
+				v.PDNType = new(PDNType)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PDNType); err != nil {
+				return tlv.WrapDecodeError(err, "PDNType", -1, ie, false)
+			}
+		case 117:
+			order.Add(9)
+			if v.UserPlaneInactivityTimer == nil {
+				v.UserPlaneInactivityTimer = new(UserPlaneInactivityTimer)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UserPlaneInactivityTimer); err != nil {
+				return tlv.WrapDecodeError(err, "UserPlaneInactivityTimer", -1, ie, false)
+			}
+		case 141:
+			order.Add(10)
+			if v.UserID == nil {
+				v.UserID = new(UserID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UserID); err != nil {
+				return tlv.WrapDecodeError(err, "UserID", -1, ie, false)
+			}
+		case 152:
+			order.Add(11)
+			if v.TraceInformation == nil {
+				v.TraceInformation = new(TraceInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.TraceInformation); err != nil {
+				return tlv.WrapDecodeError(err, "TraceInformation", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPSessionEstablishmentResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OffendingIE; ie != nil {
+		if b, err = tlv.AppendIE(b, 40, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UPFSEID; ie != nil {
+		if b, err = tlv.AppendIE(b, 57, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CreatedPDR; ie != nil {
+		if b, err = tlv.AppendIE(b, 8, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LoadControlInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 51, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FailedRuleID; ie != nil {
+		if b, err = tlv.AppendIE(b, 114, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CreatedTrafficEndpoint; ie != nil {
+		if b, err = tlv.AppendIE(b, 128, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPSessionEstablishmentResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		case 19:
+			order.Add(1)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		case 40:
+			order.Add(2)
+			if v.OffendingIE == nil {
+				v.OffendingIE = new(OffendingIE)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OffendingIE); err != nil {
+				return tlv.WrapDecodeError(err, "OffendingIE", -1, ie, false)
+			}
+		case 57:
+			order.Add(3)
+			if v.UPFSEID == nil {
+				v.UPFSEID = new(FSEID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UPFSEID); err != nil {
+				return tlv.WrapDecodeError(err, "UPFSEID", -1, ie, false)
+			}
+		case 8:
+			order.Add(4)
+			if v.CreatedPDR == nil {
+				v.CreatedPDR = new(CreatedPDR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CreatedPDR); err != nil {
+				return tlv.WrapDecodeError(err, "CreatedPDR", -1, ie, false)
+			}
+		case 51:
+			order.Add(5)
+			if v.LoadControlInformation == nil {
+				v.LoadControlInformation = new(LoadControlInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LoadControlInformation); err != nil {
+				return tlv.WrapDecodeError(err, "LoadControlInformation", -1, ie, false)
+			}
+		case 114:
+			order.Add(6)
+			if v.FailedRuleID == nil {
+				v.FailedRuleID = new(FailedRuleID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FailedRuleID); err != nil {
+				return tlv.WrapDecodeError(err, "FailedRuleID", -1, ie, false)
+			}
+		case 128:
+			order.Add(7)
+			if v.CreatedTrafficEndpoint == nil {
+				v.CreatedTrafficEndpoint = new(CreatedTrafficEndpoint)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CreatedTrafficEndpoint); err != nil {
+				return tlv.WrapDecodeError(err, "CreatedTrafficEndpoint", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPSessionModificationRequest) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.CPFSEID; ie != nil {
+		if b, err = tlv.AppendIE(b, 57, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.RemovePDR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 15, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.RemoveFAR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 16, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.RemoveURR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 17, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.RemoveQER {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 18, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.RemoveBAR; ie != nil {
+		if b, err = tlv.AppendIE(b, 87, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.RemoveTrafficEndpoint; ie != nil {
+		if b, err = tlv.AppendIE(b, 130, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.CreatePDR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 1, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.CreateFAR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 3, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.CreateURR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 6, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.CreateQER {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 7, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CreateBAR; ie != nil {
+		if b, err = tlv.AppendIE(b, 85, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CreateTrafficEndpoint; ie != nil {
+		if b, err = tlv.AppendIE(b, 127, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.UpdatePDR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 9, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.UpdateFAR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 10, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.UpdateURR {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 13, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.UpdateQER {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 14, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UpdateBAR; ie != nil {
+		if b, err = tlv.AppendIE(b, 86, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UpdateTrafficEndpoint; ie != nil {
+		if b, err = tlv.AppendIE(b, 129, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.PFCPSMReqFlags; ie != nil {
+		if b, err = tlv.AppendIE(b, 49, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UserPlaneInactivityTimer; ie != nil {
+		if b, err = tlv.AppendIE(b, 117, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.TraceInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 152, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPSessionModificationRequest) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 57:
+			order.Add(0)
+			if v.CPFSEID == nil {
+				v.CPFSEID = new(FSEID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CPFSEID); err != nil {
+				return tlv.WrapDecodeError(err, "CPFSEID", -1, ie, false)
+			}
+		case 15:
+			order.Add(1)
+			elem := new(RemovePDR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "RemovePDR", len(v.RemovePDR), ie, false)
+			}
+			v.RemovePDR = append(v.RemovePDR, elem)
+		case 16:
+			order.Add(2)
+			elem := new(RemoveFAR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "RemoveFAR", len(v.RemoveFAR), ie, false)
+			}
+			v.RemoveFAR = append(v.RemoveFAR, elem)
+		case 17:
+			order.Add(3)
+			elem := new(RemoveURR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "RemoveURR", len(v.RemoveURR), ie, false)
+			}
+			v.RemoveURR = append(v.RemoveURR, elem)
+		case 18:
+			order.Add(4)
+			elem := new(RemoveQER)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "RemoveQER", len(v.RemoveQER), ie, false)
+			}
+			v.RemoveQER = append(v.RemoveQER, elem)
+		case 87:
+			order.Add(5)
+			if v.RemoveBAR == nil {
+				v.RemoveBAR = new(RemoveBAR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.RemoveBAR); err != nil {
+				return tlv.WrapDecodeError(err, "RemoveBAR", -1, ie, false)
+			}
+		case 130:
+			order.Add(6)
+			if v.RemoveTrafficEndpoint == nil {
+				v.RemoveTrafficEndpoint = new(RemoveTrafficEndpoint)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.RemoveTrafficEndpoint); err != nil {
+				return tlv.WrapDecodeError(err, "RemoveTrafficEndpoint", -1, ie, false)
+			}
+		case 1:
+			order.Add(7)
+			elem := new(CreatePDR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "CreatePDR", len(v.CreatePDR), ie, false)
+			}
+			v.CreatePDR = append(v.CreatePDR, elem)
+		case 3:
+			order.Add(8)
+			elem := new(CreateFAR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "CreateFAR", len(v.CreateFAR), ie, false)
+			}
+			v.CreateFAR = append(v.CreateFAR, elem)
+		case 6:
+			order.Add(9)
+			elem := new(CreateURR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "CreateURR", len(v.CreateURR), ie, false)
+			}
+			v.CreateURR = append(v.CreateURR, elem)
+		case 7:
+			order.Add(10)
+			elem := new(CreateQER)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "CreateQER", len(v.CreateQER), ie, false)
+			}
+			v.CreateQER = append(v.CreateQER, elem)
+		case 85:
+			order.Add(11)
+			if v.CreateBAR == nil {
+				v.CreateBAR = new(CreateBAR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CreateBAR); err != nil {
+				return tlv.WrapDecodeError(err, "CreateBAR", -1, ie, false)
+			}
+		case 127:
+			order.Add(12)
+			if v.CreateTrafficEndpoint == nil {
+				v.CreateTrafficEndpoint = new(CreateTrafficEndpoint)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CreateTrafficEndpoint); err != nil {
+				return tlv.WrapDecodeError(err, "CreateTrafficEndpoint", -1, ie, false)
+			}
+		case 9:
+			order.Add(13)
+			elem := new(UpdatePDR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "UpdatePDR", len(v.UpdatePDR), ie, false)
+			}
+			v.UpdatePDR = append(v.UpdatePDR, elem)
+		case 10:
+			order.Add(14)
+			elem := new(UpdateFAR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "UpdateFAR", len(v.UpdateFAR), ie, false)
+			}
+			v.UpdateFAR = append(v.UpdateFAR, elem)
+		case 13:
+			order.Add(15)
+			elem := new(UpdateURR)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "UpdateURR", len(v.UpdateURR), ie, false)
+			}
+			v.UpdateURR = append(v.UpdateURR, elem)
+		case 14:
+			order.Add(16)
+			elem := new(UpdateQER)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "UpdateQER", len(v.UpdateQER), ie, false)
+			}
+			v.UpdateQER = append(v.UpdateQER, elem)
+		case 86:
+			order.Add(17)
+			if v.UpdateBAR == nil {
+				v.UpdateBAR = new(UpdateBARPFCPSessionModificationRequest)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UpdateBAR); err != nil {
+				return tlv.WrapDecodeError(err, "UpdateBAR", -1, ie, false)
+			}
+		case 129:
+			order.Add(18)
+			if v.UpdateTrafficEndpoint == nil {
+				v.UpdateTrafficEndpoint = new(UpdateTrafficEndpoint)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UpdateTrafficEndpoint); err != nil {
+				return tlv.WrapDecodeError(err, "UpdateTrafficEndpoint", -1, ie, false)
+			}
+		case 49:
+			order.Add(19)
+			if v.PFCPSMReqFlags == nil {
+				v.PFCPSMReqFlags = new(PFCPSMReqFlags)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PFCPSMReqFlags); err != nil {
+				return tlv.WrapDecodeError(err, "PFCPSMReqFlags", -1, ie, false)
+			}
+		case 117:
+			order.Add(20)
+			if v.UserPlaneInactivityTimer == nil {
+				v.UserPlaneInactivityTimer = new(UserPlaneInactivityTimer)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UserPlaneInactivityTimer); err != nil {
+				return tlv.WrapDecodeError(err, "UserPlaneInactivityTimer", -1, ie, false)
+			}
+		case 152:
+			order.Add(21)
+			if v.TraceInformation == nil {
+				v.TraceInformation = new(TraceInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.TraceInformation); err != nil {
+				return tlv.WrapDecodeError(err, "TraceInformation", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPSessionModificationResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OffendingIE; ie != nil {
+		if b, err = tlv.AppendIE(b, 40, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CreatedPDR; ie != nil {
+		if b, err = tlv.AppendIE(b, 8, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LoadControlInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 51, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FailedRuleID; ie != nil {
+		if b, err = tlv.AppendIE(b, 114, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.CreatedUpdatedTrafficEndpoint; ie != nil {
+		if b, err = tlv.AppendIE(b, 128, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPSessionModificationResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 19:
+			order.Add(0)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		case 40:
+			order.Add(1)
+			if v.OffendingIE == nil {
+				v.OffendingIE = new(OffendingIE)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OffendingIE); err != nil {
+				return tlv.WrapDecodeError(err, "OffendingIE", -1, ie, false)
+			}
+		case 8:
+			order.Add(2)
+			if v.CreatedPDR == nil {
+				v.CreatedPDR = new(CreatedPDR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CreatedPDR); err != nil {
+				return tlv.WrapDecodeError(err, "CreatedPDR", -1, ie, false)
+			}
+		case 51:
+			order.Add(3)
+			if v.LoadControlInformation == nil {
+				v.LoadControlInformation = new(LoadControlInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LoadControlInformation); err != nil {
+				return tlv.WrapDecodeError(err, "LoadControlInformation", -1, ie, false)
+			}
+		case 114:
+			order.Add(4)
+			if v.FailedRuleID == nil {
+				v.FailedRuleID = new(FailedRuleID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FailedRuleID); err != nil {
+				return tlv.WrapDecodeError(err, "FailedRuleID", -1, ie, false)
+			}
+		case 128:
+			order.Add(5)
+			if v.CreatedUpdatedTrafficEndpoint == nil {
+				v.CreatedUpdatedTrafficEndpoint = new(CreatedTrafficEndpoint)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.CreatedUpdatedTrafficEndpoint); err != nil {
+				return tlv.WrapDecodeError(err, "CreatedUpdatedTrafficEndpoint", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPSessionReportResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OffendingIE; ie != nil {
+		if b, err = tlv.AppendIE(b, 40, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPSessionReportResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 19:
+			order.Add(0)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		case 40:
+			order.Add(1)
+			if v.OffendingIE == nil {
+				v.OffendingIE = new(OffendingIE)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OffendingIE); err != nil {
+				return tlv.WrapDecodeError(err, "OffendingIE", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *PFCPSessionSetDeletionResponse) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.NodeID; ie != nil {
+		if b, err = tlv.AppendIE(b, 60, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Cause; ie != nil {
+		if b, err = tlv.AppendIE(b, 19, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OffendingIE; ie != nil {
+		if b, err = tlv.AppendIE(b, 40, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *PFCPSessionSetDeletionResponse) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 60:
+			order.Add(0)
+			if v.NodeID == nil {
+				v.NodeID = new(NodeID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NodeID); err != nil {
+				return tlv.WrapDecodeError(err, "NodeID", -1, ie, true)
+			}
+		case 19:
+			order.Add(1)
+			if v.Cause == nil {
+				v.Cause = new(Cause)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Cause); err != nil {
+				return tlv.WrapDecodeError(err, "Cause", -1, ie, true)
+			}
+		case 40:
+			order.Add(2)
+			if v.OffendingIE == nil {
+				v.OffendingIE = new(OffendingIE)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OffendingIE); err != nil {
+				return tlv.WrapDecodeError(err, "OffendingIE", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *RemoveBAR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.BARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 88, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *RemoveBAR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 88:
+			order.Add(0)
+			if v.BARID == nil {
+				v.BARID = new(BARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.BARID); err != nil {
+				return tlv.WrapDecodeError(err, "BARID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *RemoveFAR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.FARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 108, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *RemoveFAR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 108:
+			order.Add(0)
+			if v.FARID == nil {
+				v.FARID = new(FARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FARID); err != nil {
+				return tlv.WrapDecodeError(err, "FARID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *RemovePDR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.PDRID; ie != nil {
+		if b, err = tlv.AppendIE(b, 56, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *RemovePDR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 56:
+			order.Add(0)
+			if v.PDRID == nil {
+				v.PDRID = new(PacketDetectionRuleID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PDRID); err != nil {
+				return tlv.WrapDecodeError(err, "PDRID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *RemoveQER) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.QERID; ie != nil {
+		if b, err = tlv.AppendIE(b, 109, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *RemoveQER) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 109:
+			order.Add(0)
+			if v.QERID == nil {
+				v.QERID = new(QERID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.QERID); err != nil {
+				return tlv.WrapDecodeError(err, "QERID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *RemoveTrafficEndpoint) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.TrafficEndpointID; ie != nil {
+		if b, err = tlv.AppendIE(b, 131, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *RemoveTrafficEndpoint) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 131:
+			order.Add(0)
+			if v.TrafficEndpointID == nil {
+				v.TrafficEndpointID = new(TrafficEndpointID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.TrafficEndpointID); err != nil {
+				return tlv.WrapDecodeError(err, "TrafficEndpointID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *RemoveURR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.URRID; ie != nil {
+		if b, err = tlv.AppendIE(b, 81, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *RemoveURR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 81:
+			order.Add(0)
+			if v.URRID == nil {
+				v.URRID = new(URRID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.URRID); err != nil {
+				return tlv.WrapDecodeError(err, "URRID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *UpdateBARPFCPSessionModificationRequest) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.BARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 88, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *UpdateBARPFCPSessionModificationRequest) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 88:
+			order.Add(0)
+			if v.BARID == nil {
+				v.BARID = new(BARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.BARID); err != nil {
+				return tlv.WrapDecodeError(err, "BARID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *UpdateFAR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.FARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 108, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ApplyAction; ie != nil {
+		if b, err = tlv.AppendIE(b, 44, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UpdateForwardingParameters; ie != nil {
+		if b, err = tlv.AppendIE(b, 11, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UpdateDuplicatingParameters; ie != nil {
+		if b, err = tlv.AppendIE(b, 105, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.BARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 88, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *UpdateFAR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 108:
+			order.Add(0)
+			if v.FARID == nil {
+				v.FARID = new(FARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FARID); err != nil {
+				return tlv.WrapDecodeError(err, "FARID", -1, ie, true)
+			}
+		case 44:
+			order.Add(1)
+			if v.ApplyAction == nil {
+				v.ApplyAction = new(ApplyAction)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ApplyAction); err != nil {
+				return tlv.WrapDecodeError(err, "ApplyAction", -1, ie, false)
+			}
+		case 11:
+			order.Add(2)
+			if v.UpdateForwardingParameters == nil {
+				v.UpdateForwardingParameters = new(UpdateForwardingParametersIEInFAR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UpdateForwardingParameters); err != nil {
+				return tlv.WrapDecodeError(err, "UpdateForwardingParameters", -1, ie, false)
+			}
+		case 105:
+			order.Add(3)
+			if v.UpdateDuplicatingParameters == nil {
+				v.UpdateDuplicatingParameters = new(UpdateDuplicatingParameters)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UpdateDuplicatingParameters); err != nil {
+				return tlv.WrapDecodeError(err, "UpdateDuplicatingParameters", -1, ie, false)
+			}
+		case 88:
+			order.Add(4)
+			if v.BARID == nil {
+				v.BARID = new(BARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.BARID); err != nil {
+				return tlv.WrapDecodeError(err, "BARID", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *UpdateForwardingParametersIEInFAR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.DestinationInterface; ie != nil {
+		if b, err = tlv.AppendIE(b, 42, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.NetworkInstance; ie != nil {
+		if b, err = tlv.AppendIE(b, 22, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.RedirectInformation; ie != nil {
+		if b, err = tlv.AppendIE(b, 38, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OuterHeaderCreation; ie != nil {
+		if b, err = tlv.AppendIE(b, 84, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.TransportLevelMarking; ie != nil {
+		if b, err = tlv.AppendIE(b, 30, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ForwardingPolicy; ie != nil {
+		if b, err = tlv.AppendIE(b, 41, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.HeaderEnrichment; ie != nil {
+		if b, err = tlv.AppendIE(b, 98, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.PFCPSMReqFlags; ie != nil {
+		if b, err = tlv.AppendIE(b, 49, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LinkedTrafficEndpointID; ie != nil {
+		if b, err = tlv.AppendIE(b, 131, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *UpdateForwardingParametersIEInFAR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 42:
+			order.Add(0)
+			if v.DestinationInterface == nil {
+				v.DestinationInterface = new(DestinationInterface)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.DestinationInterface); err != nil {
+				return tlv.WrapDecodeError(err, "DestinationInterface", -1, ie, false)
+			}
+		case 22:
+			order.Add(1)
+			if v.NetworkInstance == nil {
+				v.NetworkInstance = new(NetworkInstance)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NetworkInstance); err != nil {
+				return tlv.WrapDecodeError(err, "NetworkInstance", -1, ie, false)
+			}
+		case 38:
+			order.Add(2)
+			if v.RedirectInformation == nil {
+				v.RedirectInformation = new(RedirectInformation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.RedirectInformation); err != nil {
+				return tlv.WrapDecodeError(err, "RedirectInformation", -1, ie, false)
+			}
+		case 84:
+			order.Add(3)
+			if v.OuterHeaderCreation == nil {
+				v.OuterHeaderCreation = new(OuterHeaderCreation)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OuterHeaderCreation); err != nil {
+				return tlv.WrapDecodeError(err, "OuterHeaderCreation", -1, ie, false)
+			}
+		case 30:
+			order.Add(4)
+			if v.TransportLevelMarking == nil {
+				v.TransportLevelMarking = new(TransportLevelMarking)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.TransportLevelMarking); err != nil {
+				return tlv.WrapDecodeError(err, "TransportLevelMarking", -1, ie, false)
+			}
+		case 41:
+			order.Add(5)
+			if v.ForwardingPolicy == nil {
+				v.ForwardingPolicy = new(ForwardingPolicy)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ForwardingPolicy); err != nil {
+				return tlv.WrapDecodeError(err, "ForwardingPolicy", -1, ie, false)
+			}
+		case 98:
+			order.Add(6)
+			if v.HeaderEnrichment == nil {
+				v.HeaderEnrichment = new(HeaderEnrichment)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.HeaderEnrichment); err != nil {
+				return tlv.WrapDecodeError(err, "HeaderEnrichment", -1, ie, false)
+			}
+		case 49:
+			order.Add(7)
+			if v.PFCPSMReqFlags == nil {
+				v.PFCPSMReqFlags = new(PFCPSMReqFlags)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PFCPSMReqFlags); err != nil {
+				return tlv.WrapDecodeError(err, "PFCPSMReqFlags", -1, ie, false)
+			}
+		case 131:
+			order.Add(8)
+			if v.LinkedTrafficEndpointID == nil {
+				v.LinkedTrafficEndpointID = new(TrafficEndpointID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LinkedTrafficEndpointID); err != nil {
+				return tlv.WrapDecodeError(err, "LinkedTrafficEndpointID", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *UpdatePDR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.PDRID; ie != nil {
+		if b, err = tlv.AppendIE(b, 56, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.OuterHeaderRemoval; ie != nil {
+		if b, err = tlv.AppendIE(b, 95, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.Precedence; ie != nil {
+		if b, err = tlv.AppendIE(b, 29, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.PDI; ie != nil {
+		if b, err = tlv.AppendIE(b, 2, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FARID; ie != nil {
+		if b, err = tlv.AppendIE(b, 108, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.URRID {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 81, ie); err != nil {
+			return nil, err
+		}
+	}
+	for _, ie := range v.QERID {
+		if ie == nil {
+			continue
+		}
+		if b, err = tlv.AppendIE(b, 109, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ActivatePredefinedRules; ie != nil {
+		if b, err = tlv.AppendIE(b, 106, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.DeactivatePredefinedRules; ie != nil {
+		if b, err = tlv.AppendIE(b, 107, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *UpdatePDR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 56:
+			order.Add(0)
+			if v.PDRID == nil {
+				v.PDRID = new(PacketDetectionRuleID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PDRID); err != nil {
+				return tlv.WrapDecodeError(err, "PDRID", -1, ie, true)
+			}
+		case 95:
+			order.Add(1)
+			if v.OuterHeaderRemoval == nil {
+				v.OuterHeaderRemoval = new(OuterHeaderRemoval)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.OuterHeaderRemoval); err != nil {
+				return tlv.WrapDecodeError(err, "OuterHeaderRemoval", -1, ie, false)
+			}
+		case 29:
+			order.Add(2)
+			if v.Precedence == nil {
+				v.Precedence = new(Precedence)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.Precedence); err != nil {
+				return tlv.WrapDecodeError(err, "Precedence", -1, ie, false)
+			}
+		case 2:
+			order.Add(3)
+			if v.PDI == nil {
+				v.PDI = new(PDI)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PDI); err != nil {
+				return tlv.WrapDecodeError(err, "PDI", -1, ie, false)
+			}
+		case 108:
+			order.Add(4)
+			if v.FARID == nil {
+				v.FARID = new(FARID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FARID); err != nil {
+				return tlv.WrapDecodeError(err, "FARID", -1, ie, false)
+			}
+		case 81:
+			order.Add(5)
+			elem := new(URRID)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "URRID", len(v.URRID), ie, false)
+			}
+			v.URRID = append(v.URRID, elem)
+		case 109:
+			order.Add(6)
+			elem := new(QERID)
+			if err := tlv.Unmarshal(ie.Value, elem); err != nil {
+				return tlv.WrapDecodeError(err, "QERID", len(v.QERID), ie, false)
+			}
+			v.QERID = append(v.QERID, elem)
+		case 106:
+			order.Add(7)
+			if v.ActivatePredefinedRules == nil {
+				v.ActivatePredefinedRules = new(ActivatePredefinedRules)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ActivatePredefinedRules); err != nil {
+				return tlv.WrapDecodeError(err, "ActivatePredefinedRules", -1, ie, false)
+			}
+		case 107:
+			order.Add(8)
+			if v.DeactivatePredefinedRules == nil {
+				v.DeactivatePredefinedRules = new(DeactivatePredefinedRules)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.DeactivatePredefinedRules); err != nil {
+				return tlv.WrapDecodeError(err, "DeactivatePredefinedRules", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *UpdateQER) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.QERID; ie != nil {
+		if b, err = tlv.AppendIE(b, 109, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.QERCorrelationID; ie != nil {
+		if b, err = tlv.AppendIE(b, 28, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.GateStatus; ie != nil {
+		if b, err = tlv.AppendIE(b, 25, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.MaximumBitrate; ie != nil {
+		if b, err = tlv.AppendIE(b, 26, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.GuaranteedBitrate; ie != nil {
+		if b, err = tlv.AppendIE(b, 27, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.PacketRate; ie != nil {
+		if b, err = tlv.AppendIE(b, 94, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.DLFlowLevelMarking; ie != nil {
+		if b, err = tlv.AppendIE(b, 97, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.QoSFlowIdentifier; ie != nil {
+		if b, err = tlv.AppendIE(b, 124, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.ReflectiveQoS; ie != nil {
+		if b, err = tlv.AppendIE(b, 123, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *UpdateQER) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 109:
+			order.Add(0)
+			if v.QERID == nil {
+				v.QERID = new(QERID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.QERID); err != nil {
+				return tlv.WrapDecodeError(err, "QERID", -1, ie, true)
+			}
+		case 28:
+			order.Add(1)
+			if v.QERCorrelationID == nil {
+				v.QERCorrelationID = new(QERCorrelationID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.QERCorrelationID); err != nil {
+				return tlv.WrapDecodeError(err, "QERCorrelationID", -1, ie, false)
+			}
+		case 25:
+			order.Add(2)
+			if v.GateStatus == nil {
+				v.GateStatus = new(GateStatus)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.GateStatus); err != nil {
+				return tlv.WrapDecodeError(err, "GateStatus", -1, ie, false)
+			}
+		case 26:
+			order.Add(3)
+			if v.MaximumBitrate == nil {
+				v.MaximumBitrate = new(MBR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.MaximumBitrate); err != nil {
+				return tlv.WrapDecodeError(err, "MaximumBitrate", -1, ie, false)
+			}
+		case 27:
+			order.Add(4)
+			if v.GuaranteedBitrate == nil {
+				v.GuaranteedBitrate = new(GBR)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.GuaranteedBitrate); err != nil {
+				return tlv.WrapDecodeError(err, "GuaranteedBitrate", -1, ie, false)
+			}
+		case 94:
+			order.Add(5)
+			if v.PacketRate == nil {
+				v.PacketRate = new(PacketRate)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.PacketRate); err != nil {
+				return tlv.WrapDecodeError(err, "PacketRate", -1, ie, false)
+			}
+		case 97:
+			order.Add(6)
+			if v.DLFlowLevelMarking == nil {
+				v.DLFlowLevelMarking = new(DLFlowLevelMarking)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.DLFlowLevelMarking); err != nil {
+				return tlv.WrapDecodeError(err, "DLFlowLevelMarking", -1, ie, false)
+			}
+		case 124:
+			order.Add(7)
+			if v.QoSFlowIdentifier == nil {
+				v.QoSFlowIdentifier = new(QFI)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.QoSFlowIdentifier); err != nil {
+				return tlv.WrapDecodeError(err, "QoSFlowIdentifier", -1, ie, false)
+			}
+		case 123:
+			order.Add(8)
+			if v.ReflectiveQoS == nil {
+				v.ReflectiveQoS = new(RQI)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.ReflectiveQoS); err != nil {
+				return tlv.WrapDecodeError(err, "ReflectiveQoS", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *UpdateTrafficEndpoint) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.TrafficEndpointID; ie != nil {
+		if b, err = tlv.AppendIE(b, 131, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.LocalFTEID; ie != nil {
+		if b, err = tlv.AppendIE(b, 21, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.NetworkInstance; ie != nil {
+		if b, err = tlv.AppendIE(b, 22, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.UEIPAddress; ie != nil {
+		if b, err = tlv.AppendIE(b, 93, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FramedRoute; ie != nil {
+		if b, err = tlv.AppendIE(b, 153, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FramedRouting; ie != nil {
+		if b, err = tlv.AppendIE(b, 154, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := v.FramedIPv6Route; ie != nil {
+		if b, err = tlv.AppendIE(b, 155, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *UpdateTrafficEndpoint) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 131:
+			order.Add(0)
+			if v.TrafficEndpointID == nil {
+				v.TrafficEndpointID = new(TrafficEndpointID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.TrafficEndpointID); err != nil {
+				return tlv.WrapDecodeError(err, "TrafficEndpointID", -1, ie, true)
+			}
+		case 21:
+			order.Add(1)
+			if v.LocalFTEID == nil {
+				v.LocalFTEID = new(FTEID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.LocalFTEID); err != nil {
+				return tlv.WrapDecodeError(err, "LocalFTEID", -1, ie, false)
+			}
+		case 22:
+			order.Add(2)
+			if v.NetworkInstance == nil {
+				v.NetworkInstance = new(NetworkInstance)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.NetworkInstance); err != nil {
+				return tlv.WrapDecodeError(err, "NetworkInstance", -1, ie, false)
+			}
+		case 93:
+			order.Add(3)
+			if v.UEIPAddress == nil {
+				v.UEIPAddress = new(UEIPAddress)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.UEIPAddress); err != nil {
+				return tlv.WrapDecodeError(err, "UEIPAddress", -1, ie, false)
+			}
+		case 153:
+			order.Add(4)
+			if v.FramedRoute == nil {
+				v.FramedRoute = new(FramedRoute)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FramedRoute); err != nil {
+				return tlv.WrapDecodeError(err, "FramedRoute", -1, ie, false)
+			}
+		case 154:
+			order.Add(5)
+			if v.FramedRouting == nil {
+				v.FramedRouting = new(FramedRouting)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FramedRouting); err != nil {
+				return tlv.WrapDecodeError(err, "FramedRouting", -1, ie, false)
+			}
+		case 155:
+			order.Add(6)
+			if v.FramedIPv6Route == nil {
+				v.FramedIPv6Route = new(FramedIPv6Route)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.FramedIPv6Route); err != nil {
+				return tlv.WrapDecodeError(err, "FramedIPv6Route", -1, ie, false)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
+
+func (v *UpdateURR) AppendBinary(b []byte) ([]byte, error) {
+	if len(v.UnknownIEs) > 0 || v.IEOrder.Len() > 0 {
+		ies, err := tlv.MarshalReflect(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ies...), nil
+	}
+	var err error
+	if ie := v.URRID; ie != nil {
+		if b, err = tlv.AppendIE(b, 81, ie); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (v *UpdateURR) DecodeFrom(b []byte) error {
+	v.UnknownIEs = nil
+	var order tlv.OrderChecker
+	r := tlv.NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return err
+		}
+		switch ie.Type {
+		case 81:
+			order.Add(0)
+			if v.URRID == nil {
+				v.URRID = new(URRID)
+			}
+			if err := tlv.Unmarshal(ie.Value, v.URRID); err != nil {
+				return tlv.WrapDecodeError(err, "URRID", -1, ie, true)
+			}
+		default:
+			v.UnknownIEs = append(v.UnknownIEs, ie)
+		}
+	}
+	v.IEOrder = order.Order(b)
+	return nil
+}
@@ -0,0 +1,105 @@
+package pfcpgolb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb/tlv"
+)
+
+// benchmarkBody is a message body encoded and decoded by the benchmarks.
+type benchmarkBody struct {
+	name string
+	body interface{}
+	// empty returns an empty body of the type to decode into
+	empty func() interface{}
+}
+
+// benchmarkBodies returns representative message bodies, from a heartbeat to
+// the requests setting up and updating a PDU session.
+func benchmarkBodies() []benchmarkBody {
+	est := testEstablishmentRequest()
+	mod := testModificationRequest()
+	recovery := &RecoveryTimeStamp{RecoveryTimeStamp: time.Unix(1700000000, 0)}
+	return []benchmarkBody{
+		{
+			"HeartbeatRequest",
+			&HeartbeatRequest{RecoveryTimeStamp: recovery},
+			func() interface{} { return &HeartbeatRequest{} },
+		},
+		{
+			"AssociationSetupRequest",
+			&PFCPAssociationSetupRequest{NodeID: est.NodeID, RecoveryTimeStamp: recovery},
+			func() interface{} { return &PFCPAssociationSetupRequest{} },
+		},
+		{
+			"SessionEstablishmentRequest",
+			&est,
+			func() interface{} { return &PFCPSessionEstablishmentRequest{} },
+		},
+		{
+			"SessionEstablishmentResponse",
+			&PFCPSessionEstablishmentResponse{
+				NodeID:  est.NodeID,
+				Cause:   &Cause{CauseValue: CauseRequestAccepted},
+				UPFSEID: &FSEID{V4: true, Seid: 1, Ipv4Address: testUPAddr.IP},
+			},
+			func() interface{} { return &PFCPSessionEstablishmentResponse{} },
+		},
+		{
+			"SessionModificationRequest",
+			&mod,
+			func() interface{} { return &PFCPSessionModificationRequest{} },
+		},
+	}
+}
+
+// BenchmarkMarshal compares the generated encoders with the reflective one.
+func BenchmarkMarshal(b *testing.B) {
+	for _, c := range benchmarkBodies() {
+		b.Run(c.name+"/generated", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := tlv.Marshal(c.body); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(c.name+"/reflect", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := tlv.MarshalReflect(c.body); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkUnmarshal compares the generated decoders with the reflective one.
+func BenchmarkUnmarshal(b *testing.B) {
+	for _, c := range benchmarkBodies() {
+		data, err := tlv.Marshal(c.body)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(c.name+"/generated", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if err := tlv.Unmarshal(data, c.empty()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(c.name+"/reflect", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if err := tlv.UnmarshalReflect(data, c.empty()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
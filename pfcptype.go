@@ -3,6 +3,8 @@ package pfcpgolb
 import (
 	"net"
 	"time"
+
+	"github.com/Nikhil690/pfcpgolb/tlv"
 )
 
 const (
@@ -71,6 +73,15 @@ const (
 	NodeIdTypeFqdn
 )
 
+// Rule ID types of a Failed Rule ID
+const (
+	RuleIdTypePdr uint8 = iota
+	RuleIdTypeFar
+	RuleIdTypeQer
+	RuleIdTypeUrr
+	RuleIdTypeBar
+)
+
 const (
 	GateOpen uint8 = iota
 	GateClose
@@ -395,5 +406,7 @@ type EthernetPacketFilter struct {
 	CTAG                     *CTAG                     `tlv:"134"`
 	STAG                     *STAG                     `tlv:"135"`
 	SDFFilter                *SDFFilter                `tlv:"23"`
+	UnknownIEs               []tlv.RawIE               `tlv:"unknown"`
+	IEOrder                  tlv.Order                 `tlv:"order"`
 }
 
@@ -0,0 +1,1220 @@
+package pfcpgolb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb/tlv"
+)
+
+// Seconds between the NTP epoch (1900) and the Unix epoch (1970)
+const ntpEpochOffset = 2208988800
+
+func (c *Cause) MarshalBinary() ([]byte, error) {
+	return []byte{c.CauseValue}, nil
+}
+
+func (c *Cause) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("cause: %w", tlv.ErrShortValue)
+	}
+	c.CauseValue = data[0]
+	return nil
+}
+
+func (o *OffendingIE) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint16(nil, o.TypeOfOffendingIe), nil
+}
+
+func (o *OffendingIE) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("offending IE: %w", tlv.ErrShortValue)
+	}
+	o.TypeOfOffendingIe = binary.BigEndian.Uint16(data)
+	return nil
+}
+
+func (r *RecoveryTimeStamp) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(nil, uint32(r.RecoveryTimeStamp.Unix()+ntpEpochOffset)), nil
+}
+
+func (r *RecoveryTimeStamp) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("recovery time stamp: %w", tlv.ErrShortValue)
+	}
+	r.RecoveryTimeStamp = time.Unix(int64(binary.BigEndian.Uint32(data))-ntpEpochOffset, 0)
+	return nil
+}
+
+func (n *NodeID) MarshalBinary() ([]byte, error) {
+	data := []byte{n.NodeIdType & 0x0F}
+	switch n.NodeIdType {
+	case NodeIdTypeIpv4Address:
+		ip := n.IP.To4()
+		if ip == nil {
+			return nil, fmt.Errorf("node ID: invalid IPv4 address %s", n.IP)
+		}
+		data = append(data, ip...)
+	case NodeIdTypeIpv6Address:
+		ip := n.IP.To16()
+		if ip == nil {
+			return nil, fmt.Errorf("node ID: invalid IPv6 address %s", n.IP)
+		}
+		data = append(data, ip...)
+	case NodeIdTypeFqdn:
+		fqdn, err := encodeFQDN(n.FQDN)
+		if err != nil {
+			return nil, fmt.Errorf("node ID: %s", err)
+		}
+		data = append(data, fqdn...)
+	default:
+		return nil, fmt.Errorf("node ID: unknown type %d", n.NodeIdType)
+	}
+	return data, nil
+}
+
+func (n *NodeID) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("node ID: %w", tlv.ErrShortValue)
+	}
+	n.NodeIdType = data[0] & 0x0F
+	data = data[1:]
+	switch n.NodeIdType {
+	case NodeIdTypeIpv4Address:
+		if len(data) < net.IPv4len {
+			return fmt.Errorf("node ID: IPv4 address: %w", tlv.ErrShortValue)
+		}
+		n.IP = net.IP(append([]byte(nil), data[:net.IPv4len]...))
+	case NodeIdTypeIpv6Address:
+		if len(data) < net.IPv6len {
+			return fmt.Errorf("node ID: IPv6 address: %w", tlv.ErrShortValue)
+		}
+		n.IP = net.IP(append([]byte(nil), data[:net.IPv6len]...))
+	case NodeIdTypeFqdn:
+		fqdn, err := decodeFQDN(data)
+		if err != nil {
+			return fmt.Errorf("node ID: %s", err)
+		}
+		if fqdn == "" {
+			return fmt.Errorf("node ID: FQDN: %w", tlv.ErrEmptyValue)
+		}
+		n.FQDN = fqdn
+	default:
+		return fmt.Errorf("node ID: unknown type %d", n.NodeIdType)
+	}
+	return nil
+}
+
+// String returns the address or the FQDN held by the Node ID.
+func (n *NodeID) String() string {
+	if n.NodeIdType == NodeIdTypeFqdn {
+		return n.FQDN
+	}
+	return n.IP.String()
+}
+
+func (f *FSEID) MarshalBinary() ([]byte, error) {
+	var flags uint8
+	if f.V4 {
+		flags |= 0x02
+	}
+	if f.V6 {
+		flags |= 0x01
+	}
+	data := binary.BigEndian.AppendUint64([]byte{flags}, f.Seid)
+	if f.V4 {
+		ip := f.Ipv4Address.To4()
+		if ip == nil {
+			return nil, fmt.Errorf("F-SEID: invalid IPv4 address %s", f.Ipv4Address)
+		}
+		data = append(data, ip...)
+	}
+	if f.V6 {
+		ip := f.Ipv6Address.To16()
+		if ip == nil {
+			return nil, fmt.Errorf("F-SEID: invalid IPv6 address %s", f.Ipv6Address)
+		}
+		data = append(data, ip...)
+	}
+	return data, nil
+}
+
+func (f *FSEID) UnmarshalBinary(data []byte) error {
+	if len(data) < 9 {
+		return fmt.Errorf("F-SEID: %w", tlv.ErrShortValue)
+	}
+	f.V4, f.V6 = data[0]&0x02 != 0, data[0]&0x01 != 0
+	f.Seid = binary.BigEndian.Uint64(data[1:])
+	data = data[9:]
+	if f.V4 {
+		if len(data) < net.IPv4len {
+			return fmt.Errorf("F-SEID: IPv4 address: %w", tlv.ErrShortValue)
+		}
+		f.Ipv4Address = net.IP(append([]byte(nil), data[:net.IPv4len]...))
+		data = data[net.IPv4len:]
+	}
+	if f.V6 {
+		if len(data) < net.IPv6len {
+			return fmt.Errorf("F-SEID: IPv6 address: %w", tlv.ErrShortValue)
+		}
+		f.Ipv6Address = net.IP(append([]byte(nil), data[:net.IPv6len]...))
+	}
+	return nil
+}
+
+func (p *PacketDetectionRuleID) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint16(nil, p.RuleId), nil
+}
+
+func (p *PacketDetectionRuleID) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("PDR ID: %w", tlv.ErrShortValue)
+	}
+	p.RuleId = binary.BigEndian.Uint16(data)
+	return nil
+}
+
+func (f *FARID) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(nil, f.FarIdValue), nil
+}
+
+func (f *FARID) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("FAR ID: %w", tlv.ErrShortValue)
+	}
+	f.FarIdValue = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+func (q *QERID) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(nil, q.QERID), nil
+}
+
+func (q *QERID) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("QER ID: %w", tlv.ErrShortValue)
+	}
+	q.QERID = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+func (u *URRID) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(nil, u.UrrIdValue), nil
+}
+
+func (u *URRID) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("URR ID: %w", tlv.ErrShortValue)
+	}
+	u.UrrIdValue = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+func (b *BARID) MarshalBinary() ([]byte, error) {
+	return []byte{b.BarIdValue}, nil
+}
+
+func (b *BARID) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("BAR ID: %w", tlv.ErrShortValue)
+	}
+	b.BarIdValue = data[0]
+	return nil
+}
+
+// NewFailedRuleID returns the Failed Rule ID of the rule id of type
+// ruleIdType, one of the RuleIdType constants.
+func NewFailedRuleID(ruleIdType uint8, id uint32) *FailedRuleID {
+	f := &FailedRuleID{RuleIdType: ruleIdType}
+	switch ruleIdType {
+	case RuleIdTypePdr:
+		f.RuleIdValue = binary.BigEndian.AppendUint16(nil, uint16(id))
+	case RuleIdTypeBar:
+		f.RuleIdValue = []byte{uint8(id)}
+	default:
+		f.RuleIdValue = binary.BigEndian.AppendUint32(nil, id)
+	}
+	return f
+}
+
+// RuleID returns the ID of the failed rule.
+func (f *FailedRuleID) RuleID() uint32 {
+	var id uint32
+	for _, b := range f.RuleIdValue {
+		id = id<<8 | uint32(b)
+	}
+	return id
+}
+
+func (f *FailedRuleID) MarshalBinary() ([]byte, error) {
+	return append([]byte{f.RuleIdType & 0x0F}, f.RuleIdValue...), nil
+}
+
+func (f *FailedRuleID) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("failed rule ID: %w", tlv.ErrShortValue)
+	}
+	f.RuleIdType = data[0] & 0x0F
+	f.RuleIdValue = append([]byte(nil), data[1:]...)
+	return nil
+}
+
+// MarshalBinary encodes the first two octets of the UP function features;
+// the features defined in later octets are not supported.
+func (f *UPFunctionFeatures) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint16(nil, f.SupportedFeatures), nil
+}
+
+func (f *UPFunctionFeatures) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("UP function features: %w", tlv.ErrShortValue)
+	}
+	f.SupportedFeatures = binary.BigEndian.Uint16(data)
+	return nil
+}
+
+// MarshalBinary encodes the first octet of the CP function features; the
+// features defined in later octets are not supported.
+func (f *CPFunctionFeatures) MarshalBinary() ([]byte, error) {
+	return []byte{f.SupportedFeatures}, nil
+}
+
+func (f *CPFunctionFeatures) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("CP function features: %w", tlv.ErrShortValue)
+	}
+	f.SupportedFeatures = data[0]
+	return nil
+}
+
+func (p *Precedence) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(nil, p.PrecedenceValue), nil
+}
+
+func (p *Precedence) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("precedence: %w", tlv.ErrShortValue)
+	}
+	p.PrecedenceValue = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+func (s *SourceInterface) MarshalBinary() ([]byte, error) {
+	return []byte{s.InterfaceValue & 0x0F}, nil
+}
+
+func (s *SourceInterface) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("source interface: %w", tlv.ErrShortValue)
+	}
+	s.InterfaceValue = data[0] & 0x0F
+	return nil
+}
+
+func (d *DestinationInterface) MarshalBinary() ([]byte, error) {
+	return []byte{d.InterfaceValue & 0x0F}, nil
+}
+
+func (d *DestinationInterface) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("destination interface: %w", tlv.ErrShortValue)
+	}
+	d.InterfaceValue = data[0] & 0x0F
+	return nil
+}
+
+func (f *FTEID) MarshalBinary() ([]byte, error) {
+	var flags uint8
+	if f.V4 {
+		flags |= 0x01
+	}
+	if f.V6 {
+		flags |= 0x02
+	}
+	if f.Ch {
+		flags |= 0x04
+	}
+	if f.Chid {
+		flags |= 0x08
+	}
+	data := []byte{flags}
+	if f.Ch {
+		// The UP function chooses the TEID and the addresses
+		if f.Chid {
+			data = append(data, f.ChooseId)
+		}
+		return data, nil
+	}
+	data = binary.BigEndian.AppendUint32(data, f.Teid)
+	if f.V4 {
+		ip := f.Ipv4Address.To4()
+		if ip == nil {
+			return nil, fmt.Errorf("F-TEID: invalid IPv4 address %s", f.Ipv4Address)
+		}
+		data = append(data, ip...)
+	}
+	if f.V6 {
+		ip := f.Ipv6Address.To16()
+		if ip == nil {
+			return nil, fmt.Errorf("F-TEID: invalid IPv6 address %s", f.Ipv6Address)
+		}
+		data = append(data, ip...)
+	}
+	return data, nil
+}
+
+func (f *FTEID) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("F-TEID: %w", tlv.ErrShortValue)
+	}
+	f.V4, f.V6 = data[0]&0x01 != 0, data[0]&0x02 != 0
+	f.Ch, f.Chid = data[0]&0x04 != 0, data[0]&0x08 != 0
+	data = data[1:]
+	if f.Ch {
+		if f.Chid {
+			if len(data) < 1 {
+				return fmt.Errorf("F-TEID: choose ID: %w", tlv.ErrShortValue)
+			}
+			f.ChooseId = data[0]
+		}
+		return nil
+	}
+	if len(data) < 4 {
+		return fmt.Errorf("F-TEID: TEID: %w", tlv.ErrShortValue)
+	}
+	f.Teid = binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if f.V4 {
+		if len(data) < net.IPv4len {
+			return fmt.Errorf("F-TEID: IPv4 address: %w", tlv.ErrShortValue)
+		}
+		f.Ipv4Address = net.IP(append([]byte(nil), data[:net.IPv4len]...))
+		data = data[net.IPv4len:]
+	}
+	if f.V6 {
+		if len(data) < net.IPv6len {
+			return fmt.Errorf("F-TEID: IPv6 address: %w", tlv.ErrShortValue)
+		}
+		f.Ipv6Address = net.IP(append([]byte(nil), data[:net.IPv6len]...))
+	}
+	return nil
+}
+
+// MarshalBinary encodes the network instance as a domain name when
+// FQDNEncoding is set, as is common for DNNs, and as is otherwise.
+func (n *NetworkInstance) MarshalBinary() ([]byte, error) {
+	if !n.FQDNEncoding {
+		return []byte(n.NetworkInstance), nil
+	}
+	data, err := encodeFQDN(n.NetworkInstance)
+	if err != nil {
+		return nil, fmt.Errorf("network instance: %s", err)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary sets FQDNEncoding when data is a well-formed domain name,
+// which then encodes back to the same octets.
+func (n *NetworkInstance) UnmarshalBinary(data []byte) error {
+	if isFQDN(data) {
+		fqdn, err := decodeFQDN(data)
+		if err != nil {
+			return fmt.Errorf("network instance: %s", err)
+		}
+		n.NetworkInstance, n.FQDNEncoding = fqdn, true
+		return nil
+	}
+	n.NetworkInstance, n.FQDNEncoding = string(data), false
+	return nil
+}
+
+func (u *UEIPAddress) MarshalBinary() ([]byte, error) {
+	var flags uint8
+	if u.V6 {
+		flags |= 0x01
+	}
+	if u.V4 {
+		flags |= 0x02
+	}
+	if u.Sd {
+		flags |= 0x04
+	}
+	if u.Ipv6d {
+		flags |= 0x08
+	}
+	data := []byte{flags}
+	if u.V4 {
+		ip := u.Ipv4Address.To4()
+		if ip == nil {
+			return nil, fmt.Errorf("UE IP address: invalid IPv4 address %s", u.Ipv4Address)
+		}
+		data = append(data, ip...)
+	}
+	if u.V6 {
+		ip := u.Ipv6Address.To16()
+		if ip == nil {
+			return nil, fmt.Errorf("UE IP address: invalid IPv6 address %s", u.Ipv6Address)
+		}
+		data = append(data, ip...)
+	}
+	if u.Ipv6d {
+		data = append(data, u.Ipv6PrefixDelegationBits)
+	}
+	return data, nil
+}
+
+func (u *UEIPAddress) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("UE IP address: %w", tlv.ErrShortValue)
+	}
+	u.V6, u.V4 = data[0]&0x01 != 0, data[0]&0x02 != 0
+	u.Sd, u.Ipv6d = data[0]&0x04 != 0, data[0]&0x08 != 0
+	data = data[1:]
+	if u.V4 {
+		if len(data) < net.IPv4len {
+			return fmt.Errorf("UE IP address: IPv4 address: %w", tlv.ErrShortValue)
+		}
+		u.Ipv4Address = net.IP(append([]byte(nil), data[:net.IPv4len]...))
+		data = data[net.IPv4len:]
+	}
+	if u.V6 {
+		if len(data) < net.IPv6len {
+			return fmt.Errorf("UE IP address: IPv6 address: %w", tlv.ErrShortValue)
+		}
+		u.Ipv6Address = net.IP(append([]byte(nil), data[:net.IPv6len]...))
+		data = data[net.IPv6len:]
+	}
+	if u.Ipv6d {
+		if len(data) < 1 {
+			return fmt.Errorf("UE IP address: IPv6 prefix delegation bits: %w", tlv.ErrShortValue)
+		}
+		u.Ipv6PrefixDelegationBits = data[0]
+	}
+	return nil
+}
+
+func (t *TrafficEndpointID) MarshalBinary() ([]byte, error) {
+	return []byte{t.TrafficEndpointIdValue}, nil
+}
+
+func (t *TrafficEndpointID) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("traffic endpoint ID: %w", tlv.ErrShortValue)
+	}
+	t.TrafficEndpointIdValue = data[0]
+	return nil
+}
+
+// MarshalBinary takes the lengths of the fields from their values, ignoring
+// LengthOfFlowDescription.
+func (s *SDFFilter) MarshalBinary() ([]byte, error) {
+	var flags uint8
+	if s.Fd {
+		flags |= 0x01
+	}
+	if s.Ttc {
+		flags |= 0x02
+	}
+	if s.Spi {
+		flags |= 0x04
+	}
+	if s.Fl {
+		flags |= 0x08
+	}
+	if s.Bid {
+		flags |= 0x10
+	}
+	data := []byte{flags, 0}
+	if s.Fd {
+		if len(s.FlowDescription) > 0xFFFF {
+			return nil, fmt.Errorf("SDF filter: flow description too long: %d bytes", len(s.FlowDescription))
+		}
+		data = binary.BigEndian.AppendUint16(data, uint16(len(s.FlowDescription)))
+		data = append(data, s.FlowDescription...)
+	}
+	if s.Ttc {
+		if len(s.TosTrafficClass) != 2 {
+			return nil, fmt.Errorf("SDF filter: ToS traffic class of %d bytes", len(s.TosTrafficClass))
+		}
+		data = append(data, s.TosTrafficClass...)
+	}
+	if s.Spi {
+		if len(s.SecurityParameterIndex) != 4 {
+			return nil, fmt.Errorf("SDF filter: security parameter index of %d bytes", len(s.SecurityParameterIndex))
+		}
+		data = append(data, s.SecurityParameterIndex...)
+	}
+	if s.Fl {
+		if len(s.FlowLabel) != 3 {
+			return nil, fmt.Errorf("SDF filter: flow label of %d bytes", len(s.FlowLabel))
+		}
+		data = append(data, s.FlowLabel...)
+	}
+	if s.Bid {
+		data = binary.BigEndian.AppendUint32(data, s.SdfFilterId)
+	}
+	return data, nil
+}
+
+func (s *SDFFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("SDF filter: %w", tlv.ErrShortValue)
+	}
+	flags := data[0]
+	s.Fd, s.Ttc, s.Spi = flags&0x01 != 0, flags&0x02 != 0, flags&0x04 != 0
+	s.Fl, s.Bid = flags&0x08 != 0, flags&0x10 != 0
+	data = data[2:]
+	field := func(name string, n int) ([]byte, error) {
+		if len(data) < n {
+			return nil, fmt.Errorf("SDF filter: %s: %w", name, tlv.ErrShortValue)
+		}
+		value := append([]byte(nil), data[:n]...)
+		data = data[n:]
+		return value, nil
+	}
+	var err error
+	if s.Fd {
+		if len(data) < 2 {
+			return fmt.Errorf("SDF filter: flow description: %w", tlv.ErrShortValue)
+		}
+		s.LengthOfFlowDescription = binary.BigEndian.Uint16(data)
+		data = data[2:]
+		if s.FlowDescription, err = field("flow description", int(s.LengthOfFlowDescription)); err != nil {
+			return err
+		}
+	}
+	if s.Ttc {
+		if s.TosTrafficClass, err = field("ToS traffic class", 2); err != nil {
+			return err
+		}
+	}
+	if s.Spi {
+		if s.SecurityParameterIndex, err = field("security parameter index", 4); err != nil {
+			return err
+		}
+	}
+	if s.Fl {
+		if s.FlowLabel, err = field("flow label", 3); err != nil {
+			return err
+		}
+	}
+	if s.Bid {
+		if len(data) < 4 {
+			return fmt.Errorf("SDF filter: filter ID: %w", tlv.ErrShortValue)
+		}
+		s.SdfFilterId = binary.BigEndian.Uint32(data)
+	}
+	return nil
+}
+
+func (a *ApplicationID) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), a.ApplicationIdentifier...), nil
+}
+
+func (a *ApplicationID) UnmarshalBinary(data []byte) error {
+	a.ApplicationIdentifier = append([]byte(nil), data...)
+	return nil
+}
+
+func (q *QFI) MarshalBinary() ([]byte, error) {
+	return []byte{q.QFI & 0x3F}, nil
+}
+
+func (q *QFI) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("QFI: %w", tlv.ErrShortValue)
+	}
+	q.QFI = data[0] & 0x3F
+	return nil
+}
+
+func (g *GateStatus) MarshalBinary() ([]byte, error) {
+	return []byte{(g.ULGate&0x03)<<2 | g.DLGate&0x03}, nil
+}
+
+func (g *GateStatus) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("gate status: %w", tlv.ErrShortValue)
+	}
+	g.ULGate, g.DLGate = data[0]>>2&0x03, data[0]&0x03
+	return nil
+}
+
+// appendBitrate appends a bitrate in kbps on 5 octets.
+func appendBitrate(b []byte, bitrate uint64) []byte {
+	return append(b, byte(bitrate>>32), byte(bitrate>>24), byte(bitrate>>16), byte(bitrate>>8), byte(bitrate))
+}
+
+func readBitrate(b []byte) uint64 {
+	return uint64(b[0])<<32 | uint64(b[1])<<24 | uint64(b[2])<<16 | uint64(b[3])<<8 | uint64(b[4])
+}
+
+func (m *MBR) MarshalBinary() ([]byte, error) {
+	if m.ULMBR >= 1<<40 || m.DLMBR >= 1<<40 {
+		return nil, fmt.Errorf("MBR: bitrate exceeds 40 bits")
+	}
+	return appendBitrate(appendBitrate(nil, m.ULMBR), m.DLMBR), nil
+}
+
+func (m *MBR) UnmarshalBinary(data []byte) error {
+	if len(data) < 10 {
+		return fmt.Errorf("MBR: %w", tlv.ErrShortValue)
+	}
+	m.ULMBR, m.DLMBR = readBitrate(data), readBitrate(data[5:])
+	return nil
+}
+
+func (g *GBR) MarshalBinary() ([]byte, error) {
+	if g.ULGBR >= 1<<40 || g.DLGBR >= 1<<40 {
+		return nil, fmt.Errorf("GBR: bitrate exceeds 40 bits")
+	}
+	return appendBitrate(appendBitrate(nil, g.ULGBR), g.DLGBR), nil
+}
+
+func (g *GBR) UnmarshalBinary(data []byte) error {
+	if len(data) < 10 {
+		return fmt.Errorf("GBR: %w", tlv.ErrShortValue)
+	}
+	g.ULGBR, g.DLGBR = readBitrate(data), readBitrate(data[5:])
+	return nil
+}
+
+// MarshalBinary encodes the first octet of the Apply Action; the flags
+// defined in later octets are not supported.
+func (a *ApplyAction) MarshalBinary() ([]byte, error) {
+	var flags uint8
+	if a.Drop {
+		flags |= 0x01
+	}
+	if a.Forw {
+		flags |= 0x02
+	}
+	if a.Buff {
+		flags |= 0x04
+	}
+	if a.Nocp {
+		flags |= 0x08
+	}
+	if a.Dupl {
+		flags |= 0x10
+	}
+	return []byte{flags}, nil
+}
+
+func (a *ApplyAction) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("apply action: %w", tlv.ErrShortValue)
+	}
+	flags := data[0]
+	a.Drop, a.Forw, a.Buff = flags&0x01 != 0, flags&0x02 != 0, flags&0x04 != 0
+	a.Nocp, a.Dupl = flags&0x08 != 0, flags&0x10 != 0
+	return nil
+}
+
+func (o *OuterHeaderRemoval) MarshalBinary() ([]byte, error) {
+	return []byte{o.OuterHeaderRemovalDescription}, nil
+}
+
+func (o *OuterHeaderRemoval) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("outer header removal: %w", tlv.ErrShortValue)
+	}
+	o.OuterHeaderRemovalDescription = data[0]
+	return nil
+}
+
+// MarshalBinary encodes the description with its low byte, holding the
+// OuterHeaderCreation flags, in the first octet.
+func (o *OuterHeaderCreation) MarshalBinary() ([]byte, error) {
+	desc := o.OuterHeaderCreationDescription
+	data := []byte{byte(desc), byte(desc >> 8)}
+	if desc&(OuterHeaderCreationGtpUUdpIpv4|OuterHeaderCreationGtpUUdpIpv6) != 0 {
+		data = binary.BigEndian.AppendUint32(data, o.Teid)
+	}
+	if desc&(OuterHeaderCreationGtpUUdpIpv4|OuterHeaderCreationUdpIpv4) != 0 {
+		ip := o.Ipv4Address.To4()
+		if ip == nil {
+			return nil, fmt.Errorf("outer header creation: invalid IPv4 address %s", o.Ipv4Address)
+		}
+		data = append(data, ip...)
+	}
+	if desc&(OuterHeaderCreationGtpUUdpIpv6|OuterHeaderCreationUdpIpv6) != 0 {
+		ip := o.Ipv6Address.To16()
+		if ip == nil {
+			return nil, fmt.Errorf("outer header creation: invalid IPv6 address %s", o.Ipv6Address)
+		}
+		data = append(data, ip...)
+	}
+	if desc&(OuterHeaderCreationUdpIpv4|OuterHeaderCreationUdpIpv6) != 0 {
+		data = binary.BigEndian.AppendUint16(data, o.PortNumber)
+	}
+	return data, nil
+}
+
+func (o *OuterHeaderCreation) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("outer header creation: %w", tlv.ErrShortValue)
+	}
+	desc := uint16(data[0]) | uint16(data[1])<<8
+	o.OuterHeaderCreationDescription = desc
+	data = data[2:]
+	if desc&(OuterHeaderCreationGtpUUdpIpv4|OuterHeaderCreationGtpUUdpIpv6) != 0 {
+		if len(data) < 4 {
+			return fmt.Errorf("outer header creation: TEID: %w", tlv.ErrShortValue)
+		}
+		o.Teid = binary.BigEndian.Uint32(data)
+		data = data[4:]
+	}
+	if desc&(OuterHeaderCreationGtpUUdpIpv4|OuterHeaderCreationUdpIpv4) != 0 {
+		if len(data) < net.IPv4len {
+			return fmt.Errorf("outer header creation: IPv4 address: %w", tlv.ErrShortValue)
+		}
+		o.Ipv4Address = net.IP(append([]byte(nil), data[:net.IPv4len]...))
+		data = data[net.IPv4len:]
+	}
+	if desc&(OuterHeaderCreationGtpUUdpIpv6|OuterHeaderCreationUdpIpv6) != 0 {
+		if len(data) < net.IPv6len {
+			return fmt.Errorf("outer header creation: IPv6 address: %w", tlv.ErrShortValue)
+		}
+		o.Ipv6Address = net.IP(append([]byte(nil), data[:net.IPv6len]...))
+		data = data[net.IPv6len:]
+	}
+	if desc&(OuterHeaderCreationUdpIpv4|OuterHeaderCreationUdpIpv6) != 0 {
+		if len(data) < 2 {
+			return fmt.Errorf("outer header creation: port number: %w", tlv.ErrShortValue)
+		}
+		o.PortNumber = binary.BigEndian.Uint16(data)
+	}
+	return nil
+}
+
+// MarshalBinary takes the length of the address from its value, ignoring
+// RedirectServerAddressLength.
+func (r *RedirectInformation) MarshalBinary() ([]byte, error) {
+	if len(r.RedirectServerAddress) > 0xFFFF {
+		return nil, fmt.Errorf("redirect information: address too long: %d bytes", len(r.RedirectServerAddress))
+	}
+	data := []byte{r.RedirectAddressType & 0x0F}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(r.RedirectServerAddress)))
+	return append(data, r.RedirectServerAddress...), nil
+}
+
+func (r *RedirectInformation) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 {
+		return fmt.Errorf("redirect information: %w", tlv.ErrShortValue)
+	}
+	r.RedirectAddressType = data[0] & 0x0F
+	r.RedirectServerAddressLength = binary.BigEndian.Uint16(data[1:])
+	data = data[3:]
+	if len(data) < int(r.RedirectServerAddressLength) {
+		return fmt.Errorf("redirect information: address: %w", tlv.ErrShortValue)
+	}
+	r.RedirectServerAddress = append([]byte(nil), data[:r.RedirectServerAddressLength]...)
+	return nil
+}
+
+func (t *TransportLevelMarking) MarshalBinary() ([]byte, error) {
+	if len(t.TosTrafficClass) != 2 {
+		return nil, fmt.Errorf("transport level marking: ToS traffic class of %d bytes", len(t.TosTrafficClass))
+	}
+	return append([]byte(nil), t.TosTrafficClass...), nil
+}
+
+func (t *TransportLevelMarking) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("transport level marking: %w", tlv.ErrShortValue)
+	}
+	t.TosTrafficClass = append([]byte(nil), data[:2]...)
+	return nil
+}
+
+// MarshalBinary takes the length of the identifier from its value, ignoring
+// ForwardingPolicyIdentifierLength.
+func (f *ForwardingPolicy) MarshalBinary() ([]byte, error) {
+	if len(f.ForwardingPolicyIdentifier) > 0xFF {
+		return nil, fmt.Errorf("forwarding policy: identifier too long: %d bytes", len(f.ForwardingPolicyIdentifier))
+	}
+	return append([]byte{uint8(len(f.ForwardingPolicyIdentifier))}, f.ForwardingPolicyIdentifier...), nil
+}
+
+func (f *ForwardingPolicy) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("forwarding policy: %w", tlv.ErrShortValue)
+	}
+	f.ForwardingPolicyIdentifierLength = data[0]
+	if len(data)-1 < int(data[0]) {
+		return fmt.Errorf("forwarding policy: identifier: %w", tlv.ErrShortValue)
+	}
+	f.ForwardingPolicyIdentifier = append([]byte(nil), data[1:1+int(data[0])]...)
+	return nil
+}
+
+// MarshalBinary takes the lengths of the name and value from the fields,
+// ignoring LengthOfHeaderFieldName and LengthOfHeaderFieldValue.
+func (h *HeaderEnrichment) MarshalBinary() ([]byte, error) {
+	if len(h.HeaderFieldName) > 0xFF || len(h.HeaderFieldValue) > 0xFF {
+		return nil, fmt.Errorf("header enrichment: header field too long")
+	}
+	data := []byte{h.HeaderType & 0x1F, uint8(len(h.HeaderFieldName))}
+	data = append(data, h.HeaderFieldName...)
+	data = append(data, uint8(len(h.HeaderFieldValue)))
+	return append(data, h.HeaderFieldValue...), nil
+}
+
+func (h *HeaderEnrichment) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("header enrichment: %w", tlv.ErrShortValue)
+	}
+	h.HeaderType = data[0] & 0x1F
+	h.LengthOfHeaderFieldName = data[1]
+	data = data[2:]
+	if len(data) < int(h.LengthOfHeaderFieldName)+1 {
+		return fmt.Errorf("header enrichment: header field name: %w", tlv.ErrShortValue)
+	}
+	h.HeaderFieldName = append([]byte(nil), data[:h.LengthOfHeaderFieldName]...)
+	data = data[h.LengthOfHeaderFieldName:]
+	h.LengthOfHeaderFieldValue = data[0]
+	data = data[1:]
+	if len(data) < int(h.LengthOfHeaderFieldValue) {
+		return fmt.Errorf("header enrichment: header field value: %w", tlv.ErrShortValue)
+	}
+	h.HeaderFieldValue = append([]byte(nil), data[:h.LengthOfHeaderFieldValue]...)
+	return nil
+}
+
+func (p *PFCPSMReqFlags) MarshalBinary() ([]byte, error) {
+	var flags uint8
+	if p.Drobu {
+		flags |= 0x01
+	}
+	if p.Sndem {
+		flags |= 0x02
+	}
+	if p.Qaurr {
+		flags |= 0x04
+	}
+	return []byte{flags}, nil
+}
+
+func (p *PFCPSMReqFlags) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("PFCPSMReq-Flags: %w", tlv.ErrShortValue)
+	}
+	p.Drobu, p.Sndem, p.Qaurr = data[0]&0x01 != 0, data[0]&0x02 != 0, data[0]&0x04 != 0
+	return nil
+}
+
+func (q *QERCorrelationID) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(nil, q.QerCorrelationIdValue), nil
+}
+
+func (q *QERCorrelationID) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("QER correlation ID: %w", tlv.ErrShortValue)
+	}
+	q.QerCorrelationIdValue = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+func (p *PacketRate) MarshalBinary() ([]byte, error) {
+	var flags uint8
+	if p.ULPR {
+		flags |= 0x01
+	}
+	if p.DLPR {
+		flags |= 0x02
+	}
+	data := []byte{flags}
+	if p.ULPR {
+		data = append(data, uint8(p.ULTimeUnit)&0x07)
+		data = binary.BigEndian.AppendUint16(data, p.MaximumUL)
+	}
+	if p.DLPR {
+		data = append(data, uint8(p.DLTimeUnit)&0x07)
+		data = binary.BigEndian.AppendUint16(data, p.MaximumDL)
+	}
+	return data, nil
+}
+
+func (p *PacketRate) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("packet rate: %w", tlv.ErrShortValue)
+	}
+	p.ULPR, p.DLPR = data[0]&0x01 != 0, data[0]&0x02 != 0
+	data = data[1:]
+	if p.ULPR {
+		if len(data) < 3 {
+			return fmt.Errorf("packet rate: uplink: %w", tlv.ErrShortValue)
+		}
+		p.ULTimeUnit, p.MaximumUL = PacketRateTimeUnit(data[0]&0x07), binary.BigEndian.Uint16(data[1:])
+		data = data[3:]
+	}
+	if p.DLPR {
+		if len(data) < 3 {
+			return fmt.Errorf("packet rate: downlink: %w", tlv.ErrShortValue)
+		}
+		p.DLTimeUnit, p.MaximumDL = PacketRateTimeUnit(data[0]&0x07), binary.BigEndian.Uint16(data[1:])
+	}
+	return nil
+}
+
+func (r *RQI) MarshalBinary() ([]byte, error) {
+	if r.RQI {
+		return []byte{0x01}, nil
+	}
+	return []byte{0x00}, nil
+}
+
+func (r *RQI) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("RQI: %w", tlv.ErrShortValue)
+	}
+	r.RQI = data[0]&0x01 != 0
+	return nil
+}
+
+func (p *PDNType) MarshalBinary() ([]byte, error) {
+	return []byte{p.PdnType & 0x07}, nil
+}
+
+func (p *PDNType) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("PDN type: %w", tlv.ErrShortValue)
+	}
+	p.PdnType = data[0] & 0x07
+	return nil
+}
+
+func (a *ActivatePredefinedRules) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), a.PredefinedRulesName...), nil
+}
+
+func (a *ActivatePredefinedRules) UnmarshalBinary(data []byte) error {
+	a.PredefinedRulesName = append([]byte(nil), data...)
+	return nil
+}
+
+func (d *DeactivatePredefinedRules) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), d.PredefinedRulesName...), nil
+}
+
+func (d *DeactivatePredefinedRules) UnmarshalBinary(data []byte) error {
+	d.PredefinedRulesName = append([]byte(nil), data...)
+	return nil
+}
+
+// The IEs below are kept in their encoded form.
+
+func (e *EthernetPDUSessionInformation) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), e.EthernetPDUSessionInformationdata...), nil
+}
+
+func (e *EthernetPDUSessionInformation) UnmarshalBinary(data []byte) error {
+	e.EthernetPDUSessionInformationdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (e *EthernetFilterID) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), e.EthernetFilterIDdata...), nil
+}
+
+func (e *EthernetFilterID) UnmarshalBinary(data []byte) error {
+	e.EthernetFilterIDdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (e *EthernetFilterProperties) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), e.EthernetFilterPropertiesdata...), nil
+}
+
+func (e *EthernetFilterProperties) UnmarshalBinary(data []byte) error {
+	e.EthernetFilterPropertiesdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MACAddress) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), m.MACAddressdata...), nil
+}
+
+func (m *MACAddress) UnmarshalBinary(data []byte) error {
+	m.MACAddressdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (e *Ethertype) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), e.Ethertypedata...), nil
+}
+
+func (e *Ethertype) UnmarshalBinary(data []byte) error {
+	e.Ethertypedata = append([]byte(nil), data...)
+	return nil
+}
+
+func (c *CTAG) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), c.CTAGdata...), nil
+}
+
+func (c *CTAG) UnmarshalBinary(data []byte) error {
+	c.CTAGdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *STAG) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), s.STAGdata...), nil
+}
+
+func (s *STAG) UnmarshalBinary(data []byte) error {
+	s.STAGdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (f *FramedRoute) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), f.FramedRoutedata...), nil
+}
+
+func (f *FramedRoute) UnmarshalBinary(data []byte) error {
+	f.FramedRoutedata = append([]byte(nil), data...)
+	return nil
+}
+
+func (f *FramedRouting) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), f.FramedRoutingdata...), nil
+}
+
+func (f *FramedRouting) UnmarshalBinary(data []byte) error {
+	f.FramedRoutingdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (f *FramedIPv6Route) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), f.FramedIPv6Routedata...), nil
+}
+
+func (f *FramedIPv6Route) UnmarshalBinary(data []byte) error {
+	f.FramedIPv6Routedata = append([]byte(nil), data...)
+	return nil
+}
+
+func (d *DuplicatingParameters) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), d.DuplicatingParametersdata...), nil
+}
+
+func (d *DuplicatingParameters) UnmarshalBinary(data []byte) error {
+	d.DuplicatingParametersdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (u *UpdateDuplicatingParameters) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), u.UpdateDuplicatingParametersdata...), nil
+}
+
+func (u *UpdateDuplicatingParameters) UnmarshalBinary(data []byte) error {
+	u.UpdateDuplicatingParametersdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (p *Proxying) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), p.Proxyingdata...), nil
+}
+
+func (p *Proxying) UnmarshalBinary(data []byte) error {
+	p.Proxyingdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (d *DLFlowLevelMarking) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), d.DLFlowLevelMarkingdata...), nil
+}
+
+func (d *DLFlowLevelMarking) UnmarshalBinary(data []byte) error {
+	d.DLFlowLevelMarkingdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (u *UserPlaneInactivityTimer) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), u.UserPlaneInactivityTimerdata...), nil
+}
+
+func (u *UserPlaneInactivityTimer) UnmarshalBinary(data []byte) error {
+	u.UserPlaneInactivityTimerdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (u *UserID) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), u.UserIDdata...), nil
+}
+
+func (u *UserID) UnmarshalBinary(data []byte) error {
+	u.UserIDdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (t *TraceInformation) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), t.TraceInformationdata...), nil
+}
+
+func (t *TraceInformation) UnmarshalBinary(data []byte) error {
+	t.TraceInformationdata = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *SequenceNumber) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), s.SequenceNumberdata...), nil
+}
+
+func (s *SequenceNumber) UnmarshalBinary(data []byte) error {
+	s.SequenceNumberdata = append([]byte(nil), data...)
+	return nil
+}
+
+// encodeFQDN encodes a domain name as a sequence of length-prefixed labels
+// (RFC 1035 section 3.1) without the trailing root label.
+func encodeFQDN(fqdn string) ([]byte, error) {
+	var data []byte
+	for _, label := range strings.Split(strings.TrimSuffix(fqdn, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid FQDN %q", fqdn)
+		}
+		data = append(data, uint8(len(label)))
+		data = append(data, label...)
+	}
+	return data, nil
+}
+
+// isFQDN reports whether data is a sequence of length-prefixed labels that
+// decodeFQDN and encodeFQDN turn back into data.
+func isFQDN(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	for len(data) > 0 {
+		length := int(data[0])
+		if length == 0 || length > 63 || length+1 > len(data) {
+			return false
+		}
+		data = data[length+1:]
+	}
+	return true
+}
+
+func decodeFQDN(data []byte) (string, error) {
+	var labels []string
+	for len(data) > 0 {
+		length := int(data[0])
+		if length == 0 {
+			break
+		}
+		if length > 63 {
+			return "", fmt.Errorf("FQDN label of %d bytes", length)
+		}
+		if length+1 > len(data) {
+			return "", fmt.Errorf("FQDN label: %w", tlv.ErrShortValue)
+		}
+		labels = append(labels, string(data[1:length+1]))
+		data = data[length+1:]
+	}
+	return strings.Join(labels, "."), nil
+}
@@ -0,0 +1,121 @@
+package pfcpgolb
+
+import (
+	"bytes"
+	"encoding"
+	"net"
+	"reflect"
+	"testing"
+)
+
+type ieCodec interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+func TestIEEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		ie   ieCodec
+		data []byte
+	}{
+		{"Precedence", &Precedence{PrecedenceValue: 255}, []byte{0, 0, 0, 0xFF}},
+		{"SourceInterface", &SourceInterface{InterfaceValue: SourceInterfaceCore}, []byte{0x01}},
+		{"DestinationInterface", &DestinationInterface{InterfaceValue: DestinationInterfaceAccess}, []byte{0x00}},
+		{
+			"FTEID",
+			&FTEID{V4: true, Teid: 0x01020304, Ipv4Address: net.IPv4(10, 0, 0, 1).To4()},
+			[]byte{0x01, 1, 2, 3, 4, 10, 0, 0, 1},
+		},
+		{"FTEID choose", &FTEID{V4: true, Ch: true, Chid: true, ChooseId: 5}, []byte{0x0D, 5}},
+		{"NetworkInstance", &NetworkInstance{NetworkInstance: "internet"}, []byte("internet")},
+		{
+			"NetworkInstance FQDN",
+			&NetworkInstance{NetworkInstance: "internet.mnc001", FQDNEncoding: true},
+			append(append([]byte{8}, "internet"...), append([]byte{6}, "mnc001"...)...),
+		},
+		{
+			"UEIPAddress",
+			&UEIPAddress{V4: true, Sd: true, Ipv4Address: net.IPv4(10, 60, 0, 1).To4()},
+			[]byte{0x06, 10, 60, 0, 1},
+		},
+		{
+			"SDFFilter",
+			&SDFFilter{Fd: true, LengthOfFlowDescription: 3, FlowDescription: []byte("abc"), Bid: true, SdfFilterId: 7},
+			[]byte{0x11, 0, 0, 3, 'a', 'b', 'c', 0, 0, 0, 7},
+		},
+		{"QFI", &QFI{QFI: 9}, []byte{9}},
+		{"GateStatus", &GateStatus{ULGate: GateOpen, DLGate: GateClose}, []byte{0x01}},
+		{
+			"MBR",
+			&MBR{ULMBR: 0x0102030405, DLMBR: 1},
+			[]byte{1, 2, 3, 4, 5, 0, 0, 0, 0, 1},
+		},
+		{"ApplyAction", &ApplyAction{Forw: true, Nocp: true}, []byte{0x0A}},
+		{"OuterHeaderRemoval", &OuterHeaderRemoval{OuterHeaderRemovalDescription: OuterHeaderRemovalGtpUUdpIpv4}, []byte{0}},
+		{
+			"OuterHeaderCreation",
+			&OuterHeaderCreation{
+				OuterHeaderCreationDescription: OuterHeaderCreationGtpUUdpIpv4,
+				Teid:                           0x0A0B0C0D,
+				Ipv4Address:                    net.IPv4(192, 168, 1, 2).To4(),
+			},
+			[]byte{0x01, 0x00, 0x0A, 0x0B, 0x0C, 0x0D, 192, 168, 1, 2},
+		},
+		{
+			"ForwardingPolicy",
+			&ForwardingPolicy{ForwardingPolicyIdentifierLength: 2, ForwardingPolicyIdentifier: []byte("fp")},
+			[]byte{2, 'f', 'p'},
+		},
+		{"PFCPSMReqFlags", &PFCPSMReqFlags{Sndem: true}, []byte{0x02}},
+		{
+			"PacketRate",
+			&PacketRate{DLPR: true, DLTimeUnit: 2, MaximumDL: 1000},
+			[]byte{0x02, 2, 0x03, 0xE8},
+		},
+		{"RQI", &RQI{RQI: true}, []byte{1}},
+		{"PDNType", &PDNType{PdnType: PDNTypeIpv4}, []byte{1}},
+		{"UserID", &UserID{UserIDdata: []byte{1, 2}}, []byte{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.ie.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+			if !bytes.Equal(data, tt.data) {
+				t.Fatalf("MarshalBinary = %x, want %x", data, tt.data)
+			}
+			decoded := reflect.New(reflect.TypeOf(tt.ie).Elem()).Interface().(ieCodec)
+			if err := decoded.UnmarshalBinary(tt.data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tt.ie) {
+				t.Fatalf("UnmarshalBinary = %+v, want %+v", decoded, tt.ie)
+			}
+		})
+	}
+}
+
+func TestIEDecodingTruncated(t *testing.T) {
+	tests := []struct {
+		name string
+		ie   ieCodec
+		data []byte
+	}{
+		{"Precedence", &Precedence{}, []byte{0, 0}},
+		{"FTEID", &FTEID{}, []byte{0x01, 1, 2, 3, 4, 10}},
+		{"UEIPAddress", &UEIPAddress{}, []byte{0x02, 10}},
+		{"SDFFilter", &SDFFilter{}, []byte{0x01, 0, 0, 5, 'a'}},
+		{"GateStatus", &GateStatus{}, nil},
+		{"MBR", &MBR{}, []byte{1, 2, 3}},
+		{"OuterHeaderCreation", &OuterHeaderCreation{}, []byte{0x01, 0x00, 1, 2, 3, 4}},
+		{"HeaderEnrichment", &HeaderEnrichment{}, []byte{0, 4, 'a'}},
+		{"PacketRate", &PacketRate{}, []byte{0x01, 2}},
+	}
+	for _, tt := range tests {
+		if err := tt.ie.UnmarshalBinary(tt.data); err == nil {
+			t.Errorf("%s: decoded %x", tt.name, tt.data)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package pfcpgolb
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultResponseRetention is how long a response is kept to answer the
+// retransmissions of its request, unless set by Config.ResponseRetention.
+const DefaultResponseRetention = ResendResponseTimeOutPeriod * time.Second
+
+type responseKey struct {
+	peer string
+	seq  uint32
+}
+
+type cachedResponse struct {
+	key     responseKey
+	data    []byte
+	expires time.Time
+}
+
+// responseCache keeps the responses sent, by peer and sequence number of the
+// request, to replay them when the request is retransmitted. A request being
+// processed holds an entry without data.
+//
+// All the entries live for the same retention, so they expire in the order
+// they were added: the expired ones are evicted from the front of that order
+// as the cache is used, without a timer per entry.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[responseKey]*cachedResponse
+	order   []*cachedResponse
+}
+
+// begin records that the request seq from peer is being processed, returning
+// false when it is a retransmission.
+func (c *responseCache) begin(peer string, seq uint32, retention time.Duration) bool {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict(now)
+	key := responseKey{peer: peer, seq: seq}
+	if _, ok := c.entries[key]; ok {
+		return false
+	}
+	c.add(&cachedResponse{key: key, expires: now.Add(retention)})
+	return true
+}
+
+// put keeps the response to the request seq from peer.
+func (c *responseCache) put(peer string, seq uint32, data []byte, retention time.Duration) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict(now)
+	c.add(&cachedResponse{key: responseKey{peer: peer, seq: seq}, data: data, expires: now.Add(retention)})
+}
+
+// get returns the response to the request seq from peer, and whether the
+// request is known at all.
+func (c *responseCache) get(peer string, seq uint32) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict(time.Now())
+	entry, ok := c.entries[responseKey{peer: peer, seq: seq}]
+	if !ok {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// forget drops the request seq from peer while it has no response.
+func (c *responseCache) forget(peer string, seq uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := responseKey{peer: peer, seq: seq}
+	if entry, ok := c.entries[key]; ok && entry.data == nil {
+		delete(c.entries, key)
+	}
+}
+
+func (c *responseCache) add(entry *cachedResponse) {
+	if c.entries == nil {
+		c.entries = make(map[responseKey]*cachedResponse)
+	}
+	c.entries[entry.key] = entry
+	c.order = append(c.order, entry)
+}
+
+func (c *responseCache) evict(now time.Time) {
+	n := 0
+	for ; n < len(c.order) && !now.Before(c.order[n].expires); n++ {
+		entry := c.order[n]
+		// Replaced entries are left in the order
+		if c.entries[entry.key] == entry {
+			delete(c.entries, entry.key)
+		}
+		c.order[n] = nil
+	}
+	c.order = c.order[n:]
+}
+
+// responseRetention returns how long the responses are kept.
+func (pfcpServer *PfcpServer) responseRetention() time.Duration {
+	if pfcpServer.Config.ResponseRetention > 0 {
+		return pfcpServer.Config.ResponseRetention
+	}
+	return DefaultResponseRetention
+}
@@ -0,0 +1,130 @@
+package pfcpgolb
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	const retention = 50 * time.Millisecond
+	var c responseCache
+	if !c.begin("peer", 1, retention) {
+		t.Fatal("new request taken for a retransmission")
+	}
+	if c.begin("peer", 1, retention) {
+		t.Fatal("retransmission taken for a new request")
+	}
+	if data, ok := c.get("peer", 1); !ok || data != nil {
+		t.Fatalf("get of a request being processed = %v, %t, want nil, true", data, ok)
+	}
+	if _, ok := c.get("other", 1); ok {
+		t.Fatal("request of another peer known")
+	}
+
+	c.put("peer", 1, []byte{1}, retention)
+	if data, ok := c.get("peer", 1); !ok || !bytes.Equal(data, []byte{1}) {
+		t.Fatalf("get = %v, %t, want [1], true", data, ok)
+	}
+	c.forget("peer", 1)
+	if _, ok := c.get("peer", 1); !ok {
+		t.Fatal("forget dropped a response")
+	}
+
+	c.begin("peer", 2, retention)
+	c.forget("peer", 2)
+	if _, ok := c.get("peer", 2); ok {
+		t.Fatal("forget kept a request without response")
+	}
+
+	time.Sleep(2 * retention)
+	if _, ok := c.get("peer", 1); ok {
+		t.Fatal("expired response kept")
+	}
+	if len(c.entries) != 0 || len(c.order) != 0 {
+		t.Errorf("%d entries and %d ordered left after expiry, want 0", len(c.entries), len(c.order))
+	}
+	if !c.begin("peer", 1, retention) {
+		t.Error("request after the expiry of its response taken for a retransmission")
+	}
+}
+
+func TestServeReplaysResponse(t *testing.T) {
+	const retention = 100 * time.Millisecond
+	network, _, up := newTestServers(t)
+	up.Config.ResponseRetention = retention
+	var handled atomic.Int32
+	serve(t, up, HandlerFunc(func(w ResponseWriter, req *Message) {
+		handled.Add(1)
+		w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: up.RecoveryTimeStamp}})
+	}))
+
+	conn, err := network.Listen(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 3).To4(), Port: PFCP_PORT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	req := heartbeatRequest()
+	req.Header.SequenceNumber = 7
+	data, err := req.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := exchange(t, conn, testUPAddr, data)
+	replayed := exchange(t, conn, testUPAddr, data)
+	if !reflect.DeepEqual(replayed, res) {
+		t.Errorf("replayed response = %+v, want %+v", replayed, res)
+	}
+	if n := handled.Load(); n != 1 {
+		t.Errorf("request handled %d times, want 1", n)
+	}
+
+	// Past the retention, the request is handled anew
+	time.Sleep(2 * retention)
+	exchange(t, conn, testUPAddr, data)
+	if n := handled.Load(); n != 2 {
+		t.Errorf("request handled %d times after the retention, want 2", n)
+	}
+}
+
+func TestServeForgetsUnansweredRequest(t *testing.T) {
+	network, _, up := newTestServers(t)
+	var handled atomic.Int32
+	serve(t, up, HandlerFunc(func(w ResponseWriter, req *Message) {
+		// The first transmission is left without response
+		if handled.Add(1) > 1 {
+			w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: up.RecoveryTimeStamp}})
+		}
+	}))
+
+	conn, err := network.Listen(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 3).To4(), Port: PFCP_PORT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	req := heartbeatRequest()
+	req.Header.SequenceNumber = 7
+	data, err := req.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.WriteTo(data, testUPAddr); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if n, _, err := conn.ReadFrom(make([]byte, PFCP_MAX_UDP_LEN)); err == nil {
+		t.Fatalf("response of %d bytes to the unanswered request", n)
+	}
+
+	res := exchange(t, conn, testUPAddr, data)
+	if _, ok := res.Body.(HeartbeatResponse); !ok {
+		t.Fatalf("response body = %T, want HeartbeatResponse", res.Body)
+	}
+	if n := handled.Load(); n != 2 {
+		t.Errorf("request handled %d times, want 2", n)
+	}
+}
@@ -0,0 +1,269 @@
+package pfcpgolb
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// retransmitScheduler retransmits the requests of a server that wait for
+// their response, on a single goroutine. The transactions are kept in a heap
+// ordered by the time their T1 expires.
+type retransmitScheduler struct {
+	mu      sync.Mutex
+	txs     txHeap
+	wake    chan struct{}
+	stop    chan struct{}
+	started sync.Once
+	stopped sync.Once
+}
+
+// txHeap is a heap of transactions ordered by T1 expiry.
+type txHeap []*Transaction
+
+func (h txHeap) Len() int { return len(h) }
+
+func (h txHeap) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+
+func (h txHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *txHeap) Push(x interface{}) {
+	tx := x.(*Transaction)
+	tx.index = len(*h)
+	*h = append(*h, tx)
+}
+
+func (h *txHeap) Pop() interface{} {
+	old := *h
+	tx := old[len(old)-1]
+	old[len(old)-1] = nil
+	tx.index = -1
+	*h = old[:len(old)-1]
+	return tx
+}
+
+func (s *retransmitScheduler) init() {
+	s.started.Do(func() {
+		s.wake = make(chan struct{}, 1)
+		s.stop = make(chan struct{})
+		go s.run()
+	})
+}
+
+// schedule arms T1 of tx from now, as grown by the retransmissions made.
+func (s *retransmitScheduler) schedule(tx *Transaction) {
+	s.init()
+	s.mu.Lock()
+	if tx.ended() {
+		s.mu.Unlock()
+		return
+	}
+	if s.closed() {
+		s.mu.Unlock()
+		tx.finish(nil, fmt.Errorf("Request Transaction [%d]: %w", tx.SequenceNumber, net.ErrClosed))
+		return
+	}
+	tx.deadline = time.Now().Add(tx.timers.interval(tx.attempts))
+	heap.Push(&s.txs, tx)
+	first := tx.index == 0
+	s.mu.Unlock()
+	if first {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// cancel disarms T1 of tx.
+func (s *retransmitScheduler) cancel(tx *Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tx.index >= 0 && tx.index < len(s.txs) && s.txs[tx.index] == tx {
+		heap.Remove(&s.txs, tx.index)
+	}
+}
+
+// close stops the scheduler, failing the requests waiting for their
+// response.
+func (s *retransmitScheduler) close() {
+	s.init()
+	s.stopped.Do(func() {
+		close(s.stop)
+	})
+	s.mu.Lock()
+	txs := s.txs
+	s.txs = nil
+	for _, tx := range txs {
+		tx.index = -1
+	}
+	s.mu.Unlock()
+	for _, tx := range txs {
+		tx.finish(nil, fmt.Errorf("Request Transaction [%d]: %w", tx.SequenceNumber, net.ErrClosed))
+	}
+}
+
+func (s *retransmitScheduler) closed() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *retransmitScheduler) run() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		wait := time.Hour
+		if len(s.txs) > 0 {
+			wait = time.Until(s.txs[0].deadline)
+		}
+		s.mu.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		case <-s.stop:
+			return
+		}
+		s.expire(time.Now())
+	}
+}
+
+// expire retransmits the requests whose T1 expired, or fails them once N1
+// retransmissions went unanswered.
+func (s *retransmitScheduler) expire(now time.Time) {
+	var due []*Transaction
+	s.mu.Lock()
+	for len(s.txs) > 0 && !now.Before(s.txs[0].deadline) {
+		due = append(due, heap.Pop(&s.txs).(*Transaction))
+	}
+	s.mu.Unlock()
+
+	for _, tx := range due {
+		if tx.ended() {
+			continue
+		}
+		if tx.attempts >= tx.timers.N1 {
+			logger.Tracef("Request Transaction [%d]: retry-out", tx.SequenceNumber)
+			tx.finish(nil, fmt.Errorf("Request Transaction [%d]: retry-out", tx.SequenceNumber))
+			continue
+		}
+		tx.attempts++
+		logger.Tracef("Request Transaction [%d]: timeout expire, resend", tx.SequenceNumber)
+		if _, err := tx.Conn.WriteTo(tx.SendMsg, tx.DestAddr); err != nil {
+			tx.finish(nil, fmt.Errorf("Request Transaction [%d]: %s", tx.SequenceNumber, err))
+			continue
+		}
+		s.schedule(tx)
+	}
+}
+
+// bindRequest has the response or the failure of the request of tx passed to
+// complete. complete must not block: it runs on the goroutine receiving the
+// response or on the scheduler.
+func (pfcpServer *PfcpServer) bindRequest(tx *Transaction, complete func(*Message, error)) {
+	tx.complete = func(event *ReceiveEvent, err error) {
+		pfcpServer.retransmitter.cancel(tx)
+		if rmErr := pfcpServer.RemoveTransaction(tx); rmErr != nil {
+			logger.Warnf("RemoveTransaction error: %+v", rmErr)
+		}
+		if err != nil {
+			complete(nil, err)
+			return
+		}
+		complete(NewMessage(event.RemoteAddr, event.RcvMsg), nil)
+	}
+}
+
+// startRequest sends the request of tx, unless it went out in a batch, and
+// has the scheduler retransmit it until it ends.
+func (pfcpServer *PfcpServer) startRequest(tx *Transaction) {
+	logger.Tracef("Start Request Transaction [%d]", tx.SequenceNumber)
+	if !tx.sent {
+		if _, err := tx.Conn.WriteTo(tx.SendMsg, tx.DestAddr); err != nil {
+			tx.finish(nil, fmt.Errorf("Request Transaction [%d]: %s", tx.SequenceNumber, err))
+			return
+		}
+	}
+	pfcpServer.retransmitter.schedule(tx)
+}
+
+// interval returns the time waited for the response after the given number
+// of retransmissions.
+func (timers RetransmitTimers) interval(retransmissions int) time.Duration {
+	d := float64(timers.T1)
+	if timers.Backoff > 1 {
+		d *= math.Pow(timers.Backoff, float64(retransmissions))
+	}
+	if timers.MaxT1 > 0 && d > float64(timers.MaxT1) {
+		d = float64(timers.MaxT1)
+	}
+	if timers.Jitter > 0 {
+		d += d * timers.Jitter * (2*rand.Float64() - 1)
+	}
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+func (timers RetransmitTimers) validate() error {
+	switch {
+	case timers.T1 <= 0 || timers.N1 < 0:
+		return fmt.Errorf("invalid retransmission timers T1 %s N1 %d", timers.T1, timers.N1)
+	case timers.Backoff < 0 || math.IsNaN(timers.Backoff):
+		return fmt.Errorf("invalid retransmission backoff %g", timers.Backoff)
+	case timers.MaxT1 < 0:
+		return fmt.Errorf("invalid retransmission MaxT1 %s", timers.MaxT1)
+	case !(timers.Jitter >= 0 && timers.Jitter < 1):
+		return fmt.Errorf("invalid retransmission jitter %g", timers.Jitter)
+	}
+	return nil
+}
+
+type retransmitTimersKey struct{}
+
+// WithRetransmitTimers returns a copy of ctx that has the requests sent with
+// it retransmitted with timers, over those of the peer and the message type.
+func WithRetransmitTimers(ctx context.Context, timers RetransmitTimers) context.Context {
+	return context.WithValue(ctx, retransmitTimersKey{}, timers)
+}
+
+// requestTimers returns the retransmission timers of a request of msgType to
+// addr: those of the call, else of the peer, else of the message type.
+func (pfcpServer *PfcpServer) requestTimers(ctx context.Context, msgType MessageType,
+	addr *net.UDPAddr,
+) (RetransmitTimers, error) {
+	if timers, ok := ctx.Value(retransmitTimersKey{}).(RetransmitTimers); ok {
+		return timers, timers.validate()
+	}
+	if timers, ok := pfcpServer.peerTimers.Load(addr.String()); ok {
+		return timers.(RetransmitTimers), nil
+	}
+	if timers, ok := pfcpServer.Config.Retransmit[msgType]; ok {
+		return timers, timers.validate()
+	}
+	return DefaultRetransmitTimers, nil
+}
@@ -0,0 +1,375 @@
+package pfcpgolb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb/memconn"
+)
+
+func TestRetransmitTimersInterval(t *testing.T) {
+	timers := RetransmitTimers{T1: 100 * time.Millisecond, N1: 5, Backoff: 2, MaxT1: 350 * time.Millisecond}
+	for retransmissions, want := range []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 350 * time.Millisecond, 350 * time.Millisecond,
+	} {
+		if got := timers.interval(retransmissions); got != want {
+			t.Errorf("interval(%d) = %s, want %s", retransmissions, got, want)
+		}
+	}
+
+	timers = RetransmitTimers{T1: 100 * time.Millisecond, Jitter: 0.2}
+	low, high := 80*time.Millisecond, 120*time.Millisecond
+	minInterval, maxInterval := high, low
+	for i := 0; i < 1000; i++ {
+		got := timers.interval(0)
+		if got < low || got > high {
+			t.Fatalf("interval(0) = %s, want within [%s, %s]", got, low, high)
+		}
+		minInterval, maxInterval = min(minInterval, got), max(maxInterval, got)
+	}
+	if minInterval >= timers.T1 || maxInterval <= timers.T1 {
+		t.Errorf("intervals within [%s, %s], want spread around %s", minInterval, maxInterval, timers.T1)
+	}
+}
+
+func TestRetransmitTimersValidate(t *testing.T) {
+	for _, timers := range []RetransmitTimers{
+		{T1: 0},
+		{T1: time.Second, N1: -1},
+		{T1: time.Second, Backoff: -1},
+		{T1: time.Second, MaxT1: -1},
+		{T1: time.Second, Jitter: 1},
+	} {
+		if err := timers.validate(); err == nil {
+			t.Errorf("validate(%+v) = nil", timers)
+		}
+	}
+}
+
+func TestRequestTimersPrecedence(t *testing.T) {
+	server := &PfcpServer{}
+	byType := RetransmitTimers{T1: 1 * time.Second, N1: 1}
+	byPeer := RetransmitTimers{T1: 2 * time.Second, N1: 2}
+	byCall := RetransmitTimers{T1: 3 * time.Second, N1: 3}
+	server.Config.Retransmit = map[MessageType]RetransmitTimers{PFCP_SESSION_ESTABLISHMENT_REQUEST: byType}
+	otherPeer := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 3).To4(), Port: PFCP_PORT}
+	if err := server.SetPeerTimers(testUPAddr, byPeer); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		msgType MessageType
+		addr    *net.UDPAddr
+		want    RetransmitTimers
+	}{
+		{"call", WithRetransmitTimers(context.Background(), byCall), PFCP_SESSION_ESTABLISHMENT_REQUEST, testUPAddr, byCall},
+		{"peer", context.Background(), PFCP_SESSION_ESTABLISHMENT_REQUEST, testUPAddr, byPeer},
+		{"message type", context.Background(), PFCP_SESSION_ESTABLISHMENT_REQUEST, otherPeer, byType},
+		{"default", context.Background(), PFCP_HEARTBEAT_REQUEST, otherPeer, DefaultRetransmitTimers},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := server.requestTimers(tt.ctx, tt.msgType, tt.addr)
+			if err != nil || got != tt.want {
+				t.Errorf("requestTimers = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+
+	ctx := WithRetransmitTimers(context.Background(), RetransmitTimers{})
+	if _, err := server.requestTimers(ctx, PFCP_HEARTBEAT_REQUEST, testUPAddr); err == nil {
+		t.Error("requestTimers with invalid call timers = nil error")
+	}
+}
+
+// silentPeer listens on addr and records the datagrams it receives, never
+// answering them.
+type silentPeer struct {
+	mu       sync.Mutex
+	received []time.Time
+	seqs     []uint32
+}
+
+func newSilentPeer(t *testing.T, network *memconn.Network, addr *net.UDPAddr) *silentPeer {
+	t.Helper()
+	conn, err := network.Listen(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer := &silentPeer{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, PFCP_MAX_UDP_LEN)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var header Header
+			if err := header.UnmarshalBinary(buf[:n]); err != nil {
+				continue
+			}
+			peer.mu.Lock()
+			peer.received = append(peer.received, time.Now())
+			peer.seqs = append(peer.seqs, header.SequenceNumber)
+			peer.mu.Unlock()
+		}
+	}()
+	t.Cleanup(func() {
+		conn.Close()
+		<-done
+	})
+	return peer
+}
+
+// transmissions returns the times the datagrams were received at and their
+// sequence numbers.
+func (p *silentPeer) transmissions() ([]time.Time, []uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]time.Time(nil), p.received...), append([]uint32(nil), p.seqs...)
+}
+
+func heartbeatRequest() *PFCPMessage {
+	return &PFCPMessage{
+		Header: Header{Version: PfcpVersion, MessageType: PFCP_HEARTBEAT_REQUEST},
+		Body:   HeartbeatRequest{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: time.Unix(1700000000, 0)}},
+	}
+}
+
+// scheduled returns the number of transactions waiting for T1 to expire.
+func (s *retransmitScheduler) scheduled() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.txs)
+}
+
+func TestRetransmitBackoffAndRetryOut(t *testing.T) {
+	network := memconn.NewNetwork()
+	cp := newTestServer(t, network, testCPAddr)
+	peer := newSilentPeer(t, network, testUPAddr)
+
+	timers := RetransmitTimers{T1: 20 * time.Millisecond, N1: 2, Backoff: 2}
+	start := time.Now()
+	_, err := cp.SendRequest(WithRetransmitTimers(context.Background(), timers), heartbeatRequest(), testUPAddr)
+	elapsed := time.Since(start)
+	if err == nil || !strings.Contains(err.Error(), "retry-out") {
+		t.Fatalf("SendRequest error = %v, want retry-out", err)
+	}
+	// 20ms, then 40ms after the first retransmission and 80ms after the second
+	if want := 140 * time.Millisecond; elapsed < want {
+		t.Errorf("gave up after %s, want at least %s", elapsed, want)
+	}
+
+	received, seqs := peer.transmissions()
+	if len(received) != 1+timers.N1 {
+		t.Fatalf("peer received %d datagrams, want %d", len(received), 1+timers.N1)
+	}
+	for i := 1; i < len(received); i++ {
+		if seqs[i] != seqs[0] {
+			t.Errorf("retransmission %d SEQ[%d], want SEQ[%d]", i, seqs[i], seqs[0])
+		}
+		// Allow for the scheduling of the peer reading the datagrams
+		want := timers.interval(i-1) * 9 / 10
+		if gap := received[i].Sub(received[i-1]); gap < want {
+			t.Errorf("retransmission %d after %s, want at least %s", i, gap, want)
+		}
+	}
+	if n := cp.retransmitter.scheduled(); n != 0 {
+		t.Errorf("%d transactions still scheduled", n)
+	}
+}
+
+func TestRetransmitCancel(t *testing.T) {
+	network := memconn.NewNetwork()
+	cp := newTestServer(t, network, testCPAddr)
+	peer := newSilentPeer(t, network, testUPAddr)
+
+	timers := RetransmitTimers{T1: 20 * time.Millisecond, N1: 100}
+	ctx, cancel := context.WithTimeout(WithRetransmitTimers(context.Background(), timers), 50*time.Millisecond)
+	defer cancel()
+	req := heartbeatRequest()
+	_, err := cp.SendRequest(ctx, req, testUPAddr)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SendRequest error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := cp.retransmitter.scheduled(); n != 0 {
+		t.Errorf("%d transactions still scheduled", n)
+	}
+	if txTable, ok := cp.ConsumerTable.Load(testUPAddr.String()); ok {
+		if _, ok := txTable.Load(req.Header.SequenceNumber); ok {
+			t.Error("transaction still stored")
+		}
+	}
+
+	// No retransmission once cancelled
+	sent, _ := peer.transmissions()
+	time.Sleep(5 * timers.T1)
+	if received, _ := peer.transmissions(); len(received) != len(sent) {
+		t.Errorf("peer received %d datagrams after cancellation", len(received)-len(sent))
+	}
+}
+
+func TestRetransmitClose(t *testing.T) {
+	network := memconn.NewNetwork()
+	cp := newTestServer(t, network, testCPAddr)
+	newSilentPeer(t, network, testUPAddr)
+
+	future := cp.SendRequestAsync(context.Background(), heartbeatRequest(), testUPAddr, nil)
+	cp.Close()
+	select {
+	case <-future.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("request not ended by Close")
+	}
+	if _, err := future.Result(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Result error = %v, want %v", err, net.ErrClosed)
+	}
+}
+
+// BenchmarkRequestsInFlight measures the memory and the goroutines held per
+// request waiting for its response, sending inFlight requests per iteration
+// to a peer that never answers.
+func BenchmarkRequestsInFlight(b *testing.B) {
+	const inFlight = 10000
+	sends := []struct {
+		name string
+		send func(server *PfcpServer)
+	}{
+		{"SendRequestAsync", func(server *PfcpServer) {
+			server.SendRequestAsync(context.Background(), heartbeatRequest(), testUPAddr, nil)
+		}},
+		{"SendRequest", func(server *PfcpServer) {
+			go server.SendRequest(context.Background(), heartbeatRequest(), testUPAddr)
+		}},
+	}
+	for _, s := range sends {
+		b.Run(s.name, func(b *testing.B) {
+			var bytes uint64
+			var goroutines int
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				conn, err := memconn.NewNetwork().Listen(testCPAddr)
+				if err != nil {
+					b.Fatal(err)
+				}
+				server := NewPfcpServerConn(conn)
+				if err := server.SetPeerTimers(testUPAddr, RetransmitTimers{T1: time.Hour}); err != nil {
+					b.Fatal(err)
+				}
+				memBefore, goroutinesBefore := memUsage()
+				b.StartTimer()
+
+				for j := 0; j < inFlight; j++ {
+					s.send(server)
+				}
+
+				b.StopTimer()
+				// Let the goroutines of the blocking API reach their wait
+				time.Sleep(100 * time.Millisecond)
+				memAfter, goroutinesAfter := memUsage()
+				bytes += memAfter - memBefore
+				goroutines += goroutinesAfter - goroutinesBefore
+				server.Close()
+				b.StartTimer()
+			}
+			b.ReportMetric(float64(bytes)/float64(b.N*inFlight), "B/tx")
+			b.ReportMetric(float64(goroutines)/float64(b.N*inFlight), "goroutines/tx")
+		})
+	}
+}
+
+// memUsage returns the memory in use, heap and stacks, and the number of
+// goroutines.
+func memUsage() (uint64, int) {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc + stats.StackInuse, runtime.NumGoroutine()
+}
+
+// BenchmarkRoundTrip measures a request answered by a server running Serve.
+func BenchmarkRoundTrip(b *testing.B) {
+	network := memconn.NewNetwork()
+	clientConn, err := network.Listen(testCPAddr)
+	if err != nil {
+		b.Fatal(err)
+	}
+	serverConn, err := network.Listen(testUPAddr)
+	if err != nil {
+		b.Fatal(err)
+	}
+	client := NewPfcpServerConn(clientConn)
+	// Stay within the receive queue of the in-memory transport
+	client.Config.MaxInFlightPerPeer = memconn.QueueLen / 2
+	server := NewPfcpServerConn(serverConn)
+	defer client.Close()
+	defer server.Close()
+
+	mux := NewServeMux()
+	mux.HandleFunc(PFCP_HEARTBEAT_REQUEST, func(w ResponseWriter, req *Message) {
+		w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: time.Now()}})
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Serve(ctx, mux)
+	go client.Serve(ctx, NewServeMux())
+
+	b.Run("SendRequest", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.SendRequest(ctx, heartbeatRequest(), testUPAddr); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("SendRequestAsync", func(b *testing.B) {
+		b.ReportAllocs()
+		futures := make([]*Future, b.N)
+		for i := range futures {
+			futures[i] = client.SendRequestAsync(ctx, heartbeatRequest(), testUPAddr, nil)
+		}
+		for _, future := range futures {
+			if _, err := future.Result(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestRetransmitOverLossyLink(t *testing.T) {
+	network := memconn.NewNetwork()
+	cond := memconn.Conditions{Loss: 0.3, Latency: time.Millisecond, Jitter: time.Millisecond, Seed: 1}
+	newServer := func(addr *net.UDPAddr) *PfcpServer {
+		conn, err := network.Listen(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := NewPfcpServerConn(memconn.Impair(conn, cond))
+		t.Cleanup(func() { server.Close() })
+		return server
+	}
+	cp, up := newServer(testCPAddr), newServer(testUPAddr)
+	mux := NewServeMux()
+	mux.HandleFunc(PFCP_HEARTBEAT_REQUEST, func(w ResponseWriter, req *Message) {
+		w.Write(HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: time.Now()}})
+	})
+	serve(t, up, mux)
+	serve(t, cp, NewServeMux())
+
+	ctx := WithRetransmitTimers(context.Background(), RetransmitTimers{T1: 10 * time.Millisecond, N1: 20})
+	for i := 0; i < 20; i++ {
+		if _, err := cp.SendRequest(ctx, heartbeatRequest(), testUPAddr); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package pfcpgolb
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+// MaxSequenceNumber is the largest sequence number of the 24-bit field of
+// the header.
+const MaxSequenceNumber uint32 = 1<<24 - 1
+
+var errSequenceNumbersExhausted = errors.New("pfcp: all the sequence numbers are in flight")
+
+// sequenceAllocator hands out the sequence numbers of the requests sent from
+// a local endpoint, from 1 to MaxSequenceNumber and wrapping. 0 is left to
+// mean that a request has no sequence number yet.
+type sequenceAllocator struct {
+	mu   sync.Mutex
+	last uint32
+}
+
+// next returns the sequence number following the last one allocated,
+// skipping the ones still in flight.
+func (a *sequenceAllocator) next(inFlight func(uint32) bool) (uint32, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := uint32(0); i < MaxSequenceNumber; i++ {
+		a.last = a.last%MaxSequenceNumber + 1
+		if !inFlight(a.last) {
+			return a.last, nil
+		}
+	}
+	return 0, errSequenceNumbersExhausted
+}
+
+// localRouter is implemented by the connections sending from several local
+// addresses, such as the one of a server listening on several addresses.
+type localRouter interface {
+	// localAddrTo returns the local address the datagrams to addr are sent
+	// from.
+	localAddrTo(addr net.Addr) net.Addr
+}
+
+// sequenceAllocator returns the allocator of the sequence numbers of the
+// requests to addr. TS 29.244 has them unique per local endpoint, so each
+// local address of Conn has its own.
+func (pfcpServer *PfcpServer) sequenceAllocator(addr *net.UDPAddr) *sequenceAllocator {
+	var local string
+	if router, ok := pfcpServer.Conn.(localRouter); ok {
+		local = router.localAddrTo(addr).String()
+	}
+	allocator, _ := pfcpServer.seqAllocators.LoadOrStore(local, &sequenceAllocator{})
+	return allocator.(*sequenceAllocator)
+}
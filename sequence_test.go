@@ -0,0 +1,65 @@
+package pfcpgolb
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSequenceAllocatorPerLocalAddr(t *testing.T) {
+	var conns []*net.UDPConn
+	for i := 0; i < 2; i++ {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+	}
+	mc := newMultiConn(conns)
+	defer mc.Close()
+	server := &PfcpServer{Conn: mc}
+
+	// Each peer sends to one of the sockets, which then routes its requests
+	peers := make([]*net.UDPConn, 3)
+	for i := range peers {
+		peer, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer peer.Close()
+		peers[i] = peer
+	}
+	local := []*net.UDPConn{conns[0], conns[1], conns[1]}
+	for i, peer := range peers {
+		if _, err := peer.WriteTo([]byte{0}, local[i].LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		mc.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, _, err := mc.ReadFrom(make([]byte, PFCP_MAX_UDP_LEN)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	addr := func(i int) *net.UDPAddr { return peers[i].LocalAddr().(*net.UDPAddr) }
+	if server.sequenceAllocator(addr(0)) == server.sequenceAllocator(addr(1)) {
+		t.Error("peers routed through different sockets share a sequence allocator")
+	}
+	if server.sequenceAllocator(addr(1)) != server.sequenceAllocator(addr(2)) {
+		t.Error("peers routed through the same socket have different sequence allocators")
+	}
+
+	notInFlight := func(uint32) bool { return false }
+	for _, i := range []int{0, 1} {
+		seq, err := server.sequenceAllocator(addr(i)).next(notInFlight)
+		if err != nil || seq != 1 {
+			t.Errorf("first sequence number to peer %d = %d, %v, want 1", i, seq, err)
+		}
+	}
+}
+
+func TestSequenceAllocatorSingleConn(t *testing.T) {
+	_, cp, _ := newTestServers(t)
+	if cp.sequenceAllocator(testUPAddr) != cp.sequenceAllocator(testCPAddr) {
+		t.Error("a single socket has several sequence allocators")
+	}
+}
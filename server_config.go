@@ -0,0 +1,150 @@
+package pfcpgolb
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServerConfig configures the sockets opened by Listen.
+type ServerConfig struct {
+	// ListenAddrs are the local addresses to receive on, one socket each, as
+	// host, host:port, [IPv6] or [IPv6]:port. The host is an IP address or a
+	// name resolving to one, and is empty to listen on all the interfaces. The
+	// port defaults to PFCP_PORT.
+	ListenAddrs []string
+	// Network is "udp4", "udp6" or "udp", the default. With "udp", listening
+	// on all the interfaces opens a dual-stack socket.
+	Network string
+	// MaxInFlightPerPeer bounds the requests sent by SendRequestAsync that
+	// wait for their response from a peer. 0 sets no bound.
+	MaxInFlightPerPeer int
+	// ResponseRetention is how long a response is kept to answer the
+	// retransmissions of its request, by default DefaultResponseRetention.
+	ResponseRetention time.Duration
+	// Workers is the number of requests Serve handles concurrently, by
+	// default GOMAXPROCS.
+	Workers int
+	// Retransmit sets the retransmission timers of the requests by message
+	// type, for instance longer ones for session establishment than for
+	// heartbeats. The other requests use DefaultRetransmitTimers.
+	Retransmit map[MessageType]RetransmitTimers
+}
+
+// NewPfcpServerWithConfig returns a server listening as configured by cfg,
+// after checking that cfg is valid.
+func NewPfcpServerWithConfig(cfg ServerConfig) (*PfcpServer, error) {
+	if _, _, err := cfg.resolve(); err != nil {
+		return nil, err
+	}
+	server := &PfcpServer{Config: cfg}
+	if len(cfg.ListenAddrs) > 0 {
+		server.Addr = cfg.ListenAddrs[0]
+	}
+	return server, nil
+}
+
+// resolve returns the network and the local addresses to listen on.
+func (cfg ServerConfig) resolve() (string, []*net.UDPAddr, error) {
+	network := cfg.Network
+	switch network {
+	case "":
+		network = "udp"
+	case "udp", "udp4", "udp6":
+	default:
+		return "", nil, fmt.Errorf("pfcp: config: unsupported network %q", cfg.Network)
+	}
+
+	if cfg.MaxInFlightPerPeer < 0 {
+		return "", nil, fmt.Errorf("pfcp: config: negative MaxInFlightPerPeer %d", cfg.MaxInFlightPerPeer)
+	}
+
+	if cfg.ResponseRetention < 0 {
+		return "", nil, fmt.Errorf("pfcp: config: negative ResponseRetention %s", cfg.ResponseRetention)
+	}
+	if cfg.Workers < 0 {
+		return "", nil, fmt.Errorf("pfcp: config: negative Workers %d", cfg.Workers)
+	}
+	for msgType, timers := range cfg.Retransmit {
+		if err := timers.validate(); err != nil {
+			return "", nil, fmt.Errorf("pfcp: config: message type %d: %w", msgType, err)
+		}
+	}
+
+	listenAddrs := cfg.ListenAddrs
+	if len(listenAddrs) == 0 {
+		listenAddrs = []string{""}
+	}
+	addrs := make([]*net.UDPAddr, 0, len(listenAddrs))
+	seen := make(map[string]bool)
+	for _, listenAddr := range listenAddrs {
+		addr, err := ParseListenAddr(network, listenAddr)
+		if err != nil {
+			return "", nil, err
+		}
+		if seen[addr.String()] {
+			return "", nil, fmt.Errorf("pfcp: config: listen address %s given twice", addr)
+		}
+		seen[addr.String()] = true
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) > 1 {
+		for _, addr := range addrs {
+			if addr.IP == nil {
+				return "", nil, fmt.Errorf("pfcp: config: can't listen on all the interfaces along with other addresses")
+			}
+		}
+	}
+	return network, addrs, nil
+}
+
+// ParseListenAddr parses a listen address of ServerConfig for network "udp",
+// "udp4" or "udp6".
+func ParseListenAddr(network, listenAddr string) (*net.UDPAddr, error) {
+	host, port := listenAddr, ""
+	if h, p, err := net.SplitHostPort(listenAddr); err == nil {
+		host, port = h, p
+	} else if strings.HasPrefix(listenAddr, "[") && strings.HasSuffix(listenAddr, "]") {
+		host = listenAddr[1 : len(listenAddr)-1]
+	} else if strings.Count(listenAddr, ":") == 1 {
+		// host:port that SplitHostPort rejected
+		return nil, fmt.Errorf("pfcp: config: listen address %q: %w", listenAddr, err)
+	}
+
+	addr := &net.UDPAddr{Port: PFCP_PORT}
+	if port != "" {
+		portNum, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("pfcp: config: listen address %q: invalid port %q", listenAddr, port)
+		}
+		addr.Port = int(portNum)
+	}
+
+	if host != "" {
+		if i := strings.LastIndexByte(host, '%'); i >= 0 {
+			host, addr.Zone = host[:i], host[i+1:]
+		}
+		addr.IP = net.ParseIP(host)
+		if addr.IP == nil {
+			if addr.Zone != "" {
+				return nil, fmt.Errorf("pfcp: config: listen address %q: invalid IPv6 address", listenAddr)
+			}
+			ipAddr, err := net.ResolveIPAddr("ip"+strings.TrimPrefix(network, "udp"), host)
+			if err != nil {
+				return nil, fmt.Errorf("pfcp: config: listen address %q: %w", listenAddr, err)
+			}
+			addr.IP, addr.Zone = ipAddr.IP, ipAddr.Zone
+		}
+	}
+
+	isIPv4 := addr.IP != nil && addr.IP.To4() != nil
+	switch {
+	case network == "udp4" && addr.IP != nil && !isIPv4:
+		return nil, fmt.Errorf("pfcp: config: listen address %q is not an IPv4 address", listenAddr)
+	case network == "udp6" && isIPv4:
+		return nil, fmt.Errorf("pfcp: config: listen address %q is not an IPv6 address", listenAddr)
+	}
+	return addr, nil
+}
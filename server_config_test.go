@@ -0,0 +1,103 @@
+package pfcpgolb
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestParseListenAddr(t *testing.T) {
+	for _, tc := range []struct {
+		network, listenAddr string
+		want                *net.UDPAddr
+	}{
+		{"udp", "", &net.UDPAddr{Port: PFCP_PORT}},
+		{"udp", ":9000", &net.UDPAddr{Port: 9000}},
+		{"udp", "127.0.0.1", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: PFCP_PORT}},
+		{"udp4", "127.0.0.1:9000", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000}},
+		{"udp", "::1", &net.UDPAddr{IP: net.IPv6loopback, Port: PFCP_PORT}},
+		{"udp6", "[::1]", &net.UDPAddr{IP: net.IPv6loopback, Port: PFCP_PORT}},
+		{"udp6", "[::1]:9000", &net.UDPAddr{IP: net.IPv6loopback, Port: 9000}},
+		{"udp", "fe80::1%eth0", &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: PFCP_PORT, Zone: "eth0"}},
+		{"udp", "[fe80::1%eth0]:9000", &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 9000, Zone: "eth0"}},
+	} {
+		addr, err := ParseListenAddr(tc.network, tc.listenAddr)
+		if err != nil {
+			t.Errorf("%s %q: %v", tc.network, tc.listenAddr, err)
+			continue
+		}
+		if !addr.IP.Equal(tc.want.IP) || addr.Port != tc.want.Port || addr.Zone != tc.want.Zone {
+			t.Errorf("%s %q = %s, want %s", tc.network, tc.listenAddr, addr, tc.want)
+		}
+	}
+
+	for _, tc := range []struct{ network, listenAddr string }{
+		{"udp", "127.0.0.1:port"},
+		{"udp", "127.0.0.1:70000"},
+		{"udp", "[::1]:-1"},
+		{"udp", "host%eth0"},
+		{"udp", "no-such-host.invalid"},
+		{"udp4", "::1"},
+		{"udp4", "[::1]:9000"},
+		{"udp6", "127.0.0.1"},
+	} {
+		if addr, err := ParseListenAddr(tc.network, tc.listenAddr); err == nil {
+			t.Errorf("%s %q = %s, want an error", tc.network, tc.listenAddr, addr)
+		}
+	}
+}
+
+func TestServerConfigResolve(t *testing.T) {
+	network, addrs, err := ServerConfig{ListenAddrs: []string{"127.0.0.1:9000", "[::1]:9000"}}.resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network != "udp" || len(addrs) != 2 || addrs[0].IP.To4() == nil || addrs[1].IP.To4() != nil {
+		t.Errorf("resolved %s %v, want udp on an IPv4 and an IPv6 address", network, addrs)
+	}
+
+	for name, cfg := range map[string]ServerConfig{
+		"network":            {Network: "tcp"},
+		"window":             {MaxInFlightPerPeer: -1},
+		"retention":          {ResponseRetention: -time.Second},
+		"workers":            {Workers: -1},
+		"retransmit":         {Retransmit: map[MessageType]RetransmitTimers{PFCP_HEARTBEAT_REQUEST: {}}},
+		"address twice":      {ListenAddrs: []string{"127.0.0.1", "127.0.0.1:8805"}},
+		"all and others":     {ListenAddrs: []string{":9000", "127.0.0.1:9000"}},
+		"family of network":  {Network: "udp6", ListenAddrs: []string{"[::1]", "127.0.0.1"}},
+		"invalid among more": {ListenAddrs: []string{"127.0.0.1", "127.0.0.1:port"}},
+	} {
+		if _, _, err := cfg.resolve(); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+		if _, err := NewPfcpServerWithConfig(cfg); err == nil {
+			t.Errorf("%s: server created", name)
+		}
+	}
+}
+
+func TestListenMixedFamilies(t *testing.T) {
+	// Skipped without IPv6 loopback
+	listenLoopback(t, "udp6")
+	server, err := NewPfcpServerWithConfig(ServerConfig{ListenAddrs: []string{"127.0.0.1:0", "[::1]:0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Listen(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	conn, ok := server.Conn.(*multiConn)
+	if !ok {
+		t.Fatalf("Conn is a %T, want a *multiConn", server.Conn)
+	}
+	for _, peer := range []*net.UDPAddr{
+		{IP: net.IPv4(127, 0, 0, 1), Port: 9},
+		{IP: net.IPv6loopback, Port: 9},
+	} {
+		local := conn.localAddrTo(peer).(*net.UDPAddr)
+		if (local.IP.To4() != nil) != (peer.IP.To4() != nil) {
+			t.Errorf("peer %s routed through %s", peer, local)
+		}
+	}
+}
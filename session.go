@@ -0,0 +1,558 @@
+package pfcpgolb
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb/tlv"
+)
+
+// Types of the IEs rejected in a session related request
+const (
+	ieTypePDI         uint16 = 2
+	ieTypeGateStatus  uint16 = 25
+	ieTypePrecedence  uint16 = 29
+	ieTypeApplyAction uint16 = 44
+	ieTypePDRID       uint16 = 56
+	ieTypeURRID       uint16 = 81
+	ieTypeBARID       uint16 = 88
+	ieTypeFARID       uint16 = 108
+	ieTypeQERID       uint16 = 109
+)
+
+// SessionError is the failure of a session related request, with the IEs of
+// the response rejecting it.
+type SessionError struct {
+	Cause       uint8
+	OffendingIE uint16
+	// FailedRuleID is the rule that could not be created, updated or removed
+	FailedRuleID *FailedRuleID
+	Err          error
+}
+
+func (e *SessionError) Error() string {
+	return fmt.Sprintf("pfcp: session: cause %d: %s", e.Cause, e.Err)
+}
+
+func (e *SessionError) Unwrap() error {
+	return e.Err
+}
+
+// ruleFailure reports a rule of type ruleIdType that can't be applied.
+func ruleFailure(ruleIdType uint8, id uint32, format string, a ...interface{}) *SessionError {
+	return &SessionError{
+		Cause:        CauseRuleCreationModificationFailure,
+		FailedRuleID: NewFailedRuleID(ruleIdType, id),
+		Err:          fmt.Errorf(format, a...),
+	}
+}
+
+func missingIE(ieType uint16, name string) *SessionError {
+	return &SessionError{
+		Cause:       CauseMandatoryIeMissing,
+		OffendingIE: ieType,
+		Err:         fmt.Errorf("missing %s", name),
+	}
+}
+
+var errSessionDeleted = &SessionError{
+	Cause: CauseSessionContextNotFound,
+	Err:   errors.New("session deleted"),
+}
+
+// SessionRules are the rules of a session, by ID. The rules are shared and
+// must not be modified.
+type SessionRules struct {
+	PDRs map[uint16]*CreatePDR
+	FARs map[uint32]*CreateFAR
+	QERs map[uint32]*CreateQER
+	URRs map[uint32]*CreateURR
+	BARs map[uint8]*CreateBAR
+}
+
+func newSessionRules() SessionRules {
+	return SessionRules{
+		PDRs: make(map[uint16]*CreatePDR),
+		FARs: make(map[uint32]*CreateFAR),
+		QERs: make(map[uint32]*CreateQER),
+		URRs: make(map[uint32]*CreateURR),
+		BARs: make(map[uint8]*CreateBAR),
+	}
+}
+
+func (r SessionRules) clone() SessionRules {
+	c := newSessionRules()
+	maps.Copy(c.PDRs, r.PDRs)
+	maps.Copy(c.FARs, r.FARs)
+	maps.Copy(c.QERs, r.QERs)
+	maps.Copy(c.URRs, r.URRs)
+	maps.Copy(c.BARs, r.BARs)
+	return c
+}
+
+// Session is the state of a PFCP session in the UP function: its F-SEIDs and
+// its rules. The establishment, modification and deletion requests of the
+// session are applied as a whole or not at all.
+type Session struct {
+	mu          sync.RWMutex
+	localFSEID  *FSEID
+	remoteFSEID *FSEID
+	rules       SessionRules
+	deleted     bool
+}
+
+// NewSession establishes a session with the UP F-SEID localFSEID, as
+// requested by req, after checking it with ValidateEstablishment. The error
+// is a *SessionError.
+func NewSession(localFSEID *FSEID, req *PFCPSessionEstablishmentRequest) (*Session, error) {
+	rules, err := establishRules(req)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		localFSEID:  localFSEID,
+		remoteFSEID: req.CPFSEID,
+		rules:       rules,
+	}, nil
+}
+
+// establishRules returns the rules of the session established by req.
+func establishRules(req *PFCPSessionEstablishmentRequest) (SessionRules, error) {
+	if req.CPFSEID == nil {
+		return SessionRules{}, missingIE(ieTypeFSEID, "CP F-SEID")
+	}
+	rules := newSessionRules()
+	var bars []*CreateBAR
+	if req.CreateBAR != nil {
+		bars = append(bars, req.CreateBAR)
+	}
+	if err := rules.create(req.CreatePDR, req.CreateFAR, req.CreateQER, req.CreateURR, bars); err != nil {
+		return SessionRules{}, err
+	}
+	if err := rules.checkReferences(); err != nil {
+		return SessionRules{}, err
+	}
+	return rules, nil
+}
+
+// LocalFSEID returns the F-SEID of the session in the UP function.
+func (s *Session) LocalFSEID() *FSEID {
+	return s.localFSEID
+}
+
+// RemoteFSEID returns the F-SEID of the session in the CP function.
+func (s *Session) RemoteFSEID() *FSEID {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.remoteFSEID
+}
+
+// Rules returns the current rules of the session.
+func (s *Session) Rules() SessionRules {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules.clone()
+}
+
+// Modify applies the modification req to the session, after checking it
+// with ValidateModification. The error is a *SessionError, and leaves the
+// session unchanged.
+func (s *Session) Modify(req *PFCPSessionModificationRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deleted {
+		return errSessionDeleted
+	}
+
+	rules, err := s.rules.modify(req)
+	if err != nil {
+		return err
+	}
+	s.rules = rules
+	if req.CPFSEID != nil {
+		s.remoteFSEID = req.CPFSEID
+	}
+	return nil
+}
+
+// modify returns the rules modified by req: the rules are removed, then
+// created, then updated.
+func (r SessionRules) modify(req *PFCPSessionModificationRequest) (SessionRules, error) {
+	rules := r.clone()
+	var removeBARs []*RemoveBAR
+	if req.RemoveBAR != nil {
+		removeBARs = append(removeBARs, req.RemoveBAR)
+	}
+	if err := rules.remove(req.RemovePDR, req.RemoveFAR, req.RemoveQER, req.RemoveURR, removeBARs); err != nil {
+		return SessionRules{}, err
+	}
+	var createBARs []*CreateBAR
+	if req.CreateBAR != nil {
+		createBARs = append(createBARs, req.CreateBAR)
+	}
+	if err := rules.create(req.CreatePDR, req.CreateFAR, req.CreateQER, req.CreateURR, createBARs); err != nil {
+		return SessionRules{}, err
+	}
+	var updateBARs []*UpdateBARPFCPSessionModificationRequest
+	if req.UpdateBAR != nil {
+		updateBARs = append(updateBARs, req.UpdateBAR)
+	}
+	if err := rules.update(req.UpdatePDR, req.UpdateFAR, req.UpdateQER, req.UpdateURR, updateBARs); err != nil {
+		return SessionRules{}, err
+	}
+	if err := rules.checkReferences(); err != nil {
+		return SessionRules{}, err
+	}
+	return rules, nil
+}
+
+// Delete deletes the session, after which its requests fail with
+// CauseSessionContextNotFound. The error is a *SessionError.
+func (s *Session) Delete() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deleted {
+		return errSessionDeleted
+	}
+	s.deleted = true
+	s.rules = newSessionRules()
+	return nil
+}
+
+func (r *SessionRules) create(pdrs []*CreatePDR, fars []*CreateFAR, qers []*CreateQER, urrs []*CreateURR,
+	bars []*CreateBAR,
+) error {
+	for _, pdr := range pdrs {
+		switch {
+		case pdr.PDRID == nil:
+			return missingIE(ieTypePDRID, "PDR ID")
+		case pdr.Precedence == nil:
+			return missingIE(ieTypePrecedence, "Precedence")
+		case pdr.PDI == nil:
+			return missingIE(ieTypePDI, "PDI")
+		}
+		id := pdr.PDRID.RuleId
+		if _, ok := r.PDRs[id]; ok {
+			return ruleFailure(RuleIdTypePdr, uint32(id), "PDR %d already exists", id)
+		}
+		r.PDRs[id] = pdr
+	}
+	for _, far := range fars {
+		switch {
+		case far.FARID == nil:
+			return missingIE(ieTypeFARID, "FAR ID")
+		case far.ApplyAction == nil:
+			return missingIE(ieTypeApplyAction, "Apply Action")
+		}
+		id := far.FARID.FarIdValue
+		if _, ok := r.FARs[id]; ok {
+			return ruleFailure(RuleIdTypeFar, id, "FAR %d already exists", id)
+		}
+		r.FARs[id] = far
+	}
+	for _, qer := range qers {
+		switch {
+		case qer.QERID == nil:
+			return missingIE(ieTypeQERID, "QER ID")
+		case qer.GateStatus == nil:
+			return missingIE(ieTypeGateStatus, "Gate Status")
+		}
+		id := qer.QERID.QERID
+		if _, ok := r.QERs[id]; ok {
+			return ruleFailure(RuleIdTypeQer, id, "QER %d already exists", id)
+		}
+		r.QERs[id] = qer
+	}
+	for _, urr := range urrs {
+		if urr.URRID == nil {
+			return missingIE(ieTypeURRID, "URR ID")
+		}
+		id := urr.URRID.UrrIdValue
+		if _, ok := r.URRs[id]; ok {
+			return ruleFailure(RuleIdTypeUrr, id, "URR %d already exists", id)
+		}
+		r.URRs[id] = urr
+	}
+	for _, bar := range bars {
+		if bar.BARID == nil {
+			return missingIE(ieTypeBARID, "BAR ID")
+		}
+		id := bar.BARID.BarIdValue
+		if _, ok := r.BARs[id]; ok {
+			return ruleFailure(RuleIdTypeBar, uint32(id), "BAR %d already exists", id)
+		}
+		r.BARs[id] = bar
+	}
+	return nil
+}
+
+func (r *SessionRules) remove(pdrs []*RemovePDR, fars []*RemoveFAR, qers []*RemoveQER, urrs []*RemoveURR,
+	bars []*RemoveBAR,
+) error {
+	for _, pdr := range pdrs {
+		if pdr.PDRID == nil {
+			return missingIE(ieTypePDRID, "PDR ID")
+		}
+		id := pdr.PDRID.RuleId
+		if _, ok := r.PDRs[id]; !ok {
+			return ruleFailure(RuleIdTypePdr, uint32(id), "no PDR %d to remove", id)
+		}
+		delete(r.PDRs, id)
+	}
+	for _, far := range fars {
+		if far.FARID == nil {
+			return missingIE(ieTypeFARID, "FAR ID")
+		}
+		id := far.FARID.FarIdValue
+		if _, ok := r.FARs[id]; !ok {
+			return ruleFailure(RuleIdTypeFar, id, "no FAR %d to remove", id)
+		}
+		delete(r.FARs, id)
+	}
+	for _, qer := range qers {
+		if qer.QERID == nil {
+			return missingIE(ieTypeQERID, "QER ID")
+		}
+		id := qer.QERID.QERID
+		if _, ok := r.QERs[id]; !ok {
+			return ruleFailure(RuleIdTypeQer, id, "no QER %d to remove", id)
+		}
+		delete(r.QERs, id)
+	}
+	for _, urr := range urrs {
+		if urr.URRID == nil {
+			return missingIE(ieTypeURRID, "URR ID")
+		}
+		id := urr.URRID.UrrIdValue
+		if _, ok := r.URRs[id]; !ok {
+			return ruleFailure(RuleIdTypeUrr, id, "no URR %d to remove", id)
+		}
+		delete(r.URRs, id)
+	}
+	for _, bar := range bars {
+		if bar.BARID == nil {
+			return missingIE(ieTypeBARID, "BAR ID")
+		}
+		id := bar.BARID.BarIdValue
+		if _, ok := r.BARs[id]; !ok {
+			return ruleFailure(RuleIdTypeBar, uint32(id), "no BAR %d to remove", id)
+		}
+		delete(r.BARs, id)
+	}
+	return nil
+}
+
+// update replaces the updated rules by copies with the IEs of their update.
+func (r *SessionRules) update(pdrs []*UpdatePDR, fars []*UpdateFAR, qers []*UpdateQER, urrs []*UpdateURR,
+	bars []*UpdateBARPFCPSessionModificationRequest,
+) error {
+	for _, u := range pdrs {
+		if u.PDRID == nil {
+			return missingIE(ieTypePDRID, "PDR ID")
+		}
+		id := u.PDRID.RuleId
+		old, ok := r.PDRs[id]
+		if !ok {
+			return ruleFailure(RuleIdTypePdr, uint32(id), "no PDR %d to update", id)
+		}
+		pdr := *old
+		if u.OuterHeaderRemoval != nil {
+			pdr.OuterHeaderRemoval = u.OuterHeaderRemoval
+		}
+		if u.Precedence != nil {
+			pdr.Precedence = u.Precedence
+		}
+		if u.PDI != nil {
+			pdr.PDI = u.PDI
+		}
+		if u.FARID != nil {
+			pdr.FARID = u.FARID
+		}
+		if u.URRID != nil {
+			pdr.URRID = u.URRID
+		}
+		if u.QERID != nil {
+			pdr.QERID = u.QERID
+		}
+		if u.ActivatePredefinedRules != nil {
+			pdr.ActivatePredefinedRules = u.ActivatePredefinedRules
+		}
+		pdr.UnknownIEs = mergeIEs(pdr.UnknownIEs, u.UnknownIEs)
+		r.PDRs[id] = &pdr
+	}
+	for _, u := range fars {
+		if u.FARID == nil {
+			return missingIE(ieTypeFARID, "FAR ID")
+		}
+		id := u.FARID.FarIdValue
+		old, ok := r.FARs[id]
+		if !ok {
+			return ruleFailure(RuleIdTypeFar, id, "no FAR %d to update", id)
+		}
+		far := *old
+		if u.ApplyAction != nil {
+			far.ApplyAction = u.ApplyAction
+		}
+		if u.UpdateForwardingParameters != nil {
+			far.ForwardingParameters = updateForwardingParameters(far.ForwardingParameters,
+				u.UpdateForwardingParameters)
+		}
+		if u.UpdateDuplicatingParameters != nil {
+			far.DuplicatingParameters = &DuplicatingParameters{
+				DuplicatingParametersdata: u.UpdateDuplicatingParameters.UpdateDuplicatingParametersdata,
+			}
+		}
+		if u.BARID != nil {
+			far.BARID = u.BARID
+		}
+		far.UnknownIEs = mergeIEs(far.UnknownIEs, u.UnknownIEs)
+		r.FARs[id] = &far
+	}
+	for _, u := range qers {
+		if u.QERID == nil {
+			return missingIE(ieTypeQERID, "QER ID")
+		}
+		id := u.QERID.QERID
+		old, ok := r.QERs[id]
+		if !ok {
+			return ruleFailure(RuleIdTypeQer, id, "no QER %d to update", id)
+		}
+		qer := *old
+		if u.QERCorrelationID != nil {
+			qer.QERCorrelationID = u.QERCorrelationID
+		}
+		if u.GateStatus != nil {
+			qer.GateStatus = u.GateStatus
+		}
+		if u.MaximumBitrate != nil {
+			qer.MaximumBitrate = u.MaximumBitrate
+		}
+		if u.GuaranteedBitrate != nil {
+			qer.GuaranteedBitrate = u.GuaranteedBitrate
+		}
+		if u.PacketRate != nil {
+			qer.PacketRate = u.PacketRate
+		}
+		if u.DLFlowLevelMarking != nil {
+			qer.DLFlowLevelMarking = u.DLFlowLevelMarking
+		}
+		if u.QoSFlowIdentifier != nil {
+			qer.QoSFlowIdentifier = u.QoSFlowIdentifier
+		}
+		if u.ReflectiveQoS != nil {
+			qer.ReflectiveQoS = u.ReflectiveQoS
+		}
+		qer.UnknownIEs = mergeIEs(qer.UnknownIEs, u.UnknownIEs)
+		r.QERs[id] = &qer
+	}
+	for _, u := range urrs {
+		if u.URRID == nil {
+			return missingIE(ieTypeURRID, "URR ID")
+		}
+		id := u.URRID.UrrIdValue
+		old, ok := r.URRs[id]
+		if !ok {
+			return ruleFailure(RuleIdTypeUrr, id, "no URR %d to update", id)
+		}
+		urr := *old
+		urr.UnknownIEs = mergeIEs(urr.UnknownIEs, u.UnknownIEs)
+		r.URRs[id] = &urr
+	}
+	for _, u := range bars {
+		if u.BARID == nil {
+			return missingIE(ieTypeBARID, "BAR ID")
+		}
+		id := u.BARID.BarIdValue
+		old, ok := r.BARs[id]
+		if !ok {
+			return ruleFailure(RuleIdTypeBar, uint32(id), "no BAR %d to update", id)
+		}
+		bar := *old
+		bar.UnknownIEs = mergeIEs(bar.UnknownIEs, u.UnknownIEs)
+		r.BARs[id] = &bar
+	}
+	return nil
+}
+
+func updateForwardingParameters(old *ForwardingParametersIEInFAR,
+	u *UpdateForwardingParametersIEInFAR,
+) *ForwardingParametersIEInFAR {
+	var params ForwardingParametersIEInFAR
+	if old != nil {
+		params = *old
+	}
+	if u.DestinationInterface != nil {
+		params.DestinationInterface = u.DestinationInterface
+	}
+	if u.NetworkInstance != nil {
+		params.NetworkInstance = u.NetworkInstance
+	}
+	if u.RedirectInformation != nil {
+		params.RedirectInformation = u.RedirectInformation
+	}
+	if u.OuterHeaderCreation != nil {
+		params.OuterHeaderCreation = u.OuterHeaderCreation
+	}
+	if u.TransportLevelMarking != nil {
+		params.TransportLevelMarking = u.TransportLevelMarking
+	}
+	if u.ForwardingPolicy != nil {
+		params.ForwardingPolicy = u.ForwardingPolicy
+	}
+	if u.HeaderEnrichment != nil {
+		params.HeaderEnrichment = u.HeaderEnrichment
+	}
+	if u.LinkedTrafficEndpointID != nil {
+		params.LinkedTrafficEndpointID = u.LinkedTrafficEndpointID
+	}
+	params.UnknownIEs = mergeIEs(params.UnknownIEs, u.UnknownIEs)
+	return &params
+}
+
+// mergeIEs returns the undecoded IEs of a rule with those of its update, which
+// replace the IEs of the same type.
+func mergeIEs(ies, updates []tlv.RawIE) []tlv.RawIE {
+	if len(updates) == 0 {
+		return ies
+	}
+	updated := make(map[uint16]bool, len(updates))
+	for _, ie := range updates {
+		updated[ie.Type] = true
+	}
+	merged := make([]tlv.RawIE, 0, len(ies)+len(updates))
+	for _, ie := range ies {
+		if !updated[ie.Type] {
+			merged = append(merged, ie)
+		}
+	}
+	return append(merged, updates...)
+}
+
+// NewSessionErrorResponse builds the response rejecting the session related
+// request req that failed with err. A *SessionError gives the Cause, Offending
+// IE and Failed Rule ID of the response; other errors reject req with
+// CauseRequestRejected. nodeID identifies the local PFCP entity.
+func NewSessionErrorResponse(req *PFCPMessage, err error, nodeID *NodeID) (*PFCPMessage, error) {
+	cause, offendingIE := CauseRequestRejected, uint16(0)
+	var failedRuleID *FailedRuleID
+	var sessErr *SessionError
+	if errors.As(err, &sessErr) {
+		cause, offendingIE, failedRuleID = sessErr.Cause, sessErr.OffendingIE, sessErr.FailedRuleID
+	}
+	res, buildErr := NewErrorResponse(req, cause, offendingIE, nodeID, time.Time{})
+	if buildErr != nil {
+		return nil, buildErr
+	}
+	switch body := res.Body.(type) {
+	case PFCPSessionEstablishmentResponse:
+		body.FailedRuleID = failedRuleID
+		res.Body = body
+	case PFCPSessionModificationResponse:
+		body.FailedRuleID = failedRuleID
+		res.Body = body
+	}
+	return res, nil
+}
@@ -0,0 +1,256 @@
+package pfcpgolb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var errSEIDsExhausted = errors.New("pfcp: no SEID left")
+
+type remoteSEIDKey struct {
+	ipv4 string
+	ipv6 string
+	seid uint64
+}
+
+func remoteKey(fseid *FSEID) remoteSEIDKey {
+	key := remoteSEIDKey{seid: fseid.Seid}
+	if fseid.V4 {
+		key.ipv4 = string(fseid.Ipv4Address.To4())
+	}
+	if fseid.V6 {
+		key.ipv6 = string(fseid.Ipv6Address.To16())
+	}
+	return key
+}
+
+// SessionTable keeps the sessions of a node by local SEID and by remote
+// F-SEID, and allocates the local SEIDs. The UP function establishes,
+// modifies and deletes its sessions through the table; the CP function
+// allocates its SEIDs and binds them to the F-SEIDs of the UP function.
+//
+// A local SEID may carry the ID of the node in its high bits, so that the
+// SEIDs of several nodes sharing a peer differ.
+type SessionTable struct {
+	nodeBits uint
+	node     uint64
+
+	mu       sync.RWMutex
+	last     uint64
+	sessions int
+	byLocal  map[uint64]*Session
+	byRemote map[remoteSEIDKey]*Session
+}
+
+func NewSessionTable() *SessionTable {
+	return &SessionTable{
+		byLocal:  make(map[uint64]*Session),
+		byRemote: make(map[remoteSEIDKey]*Session),
+	}
+}
+
+// NewSessionTableWithNodeID returns a table allocating the SEIDs with node in
+// their nodeBits high bits.
+func NewSessionTableWithNodeID(node uint64, nodeBits uint) (*SessionTable, error) {
+	if nodeBits == 0 || nodeBits >= 64 {
+		return nil, fmt.Errorf("pfcp: session table: invalid node bits %d", nodeBits)
+	}
+	if node >= 1<<nodeBits {
+		return nil, fmt.Errorf("pfcp: session table: node %d exceeds %d bits", node, nodeBits)
+	}
+	t := NewSessionTable()
+	t.nodeBits, t.node = nodeBits, node
+	return t, nil
+}
+
+// Allocate reserves a local SEID unused by the table, until it is released or
+// a session takes it.
+func (t *SessionTable) Allocate() (uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.allocate()
+}
+
+func (t *SessionTable) allocate() (uint64, error) {
+	// SEIDs below max, never 0 which means no session
+	max := ^uint64(0) >> t.nodeBits
+	prefix := t.node << (64 - t.nodeBits)
+	if uint64(len(t.byLocal)) >= max {
+		return 0, errSEIDsExhausted
+	}
+	for {
+		if t.last >= max {
+			t.last = 0
+		}
+		t.last++
+		seid := prefix | t.last
+		if _, ok := t.byLocal[seid]; !ok {
+			t.byLocal[seid] = nil
+			return seid, nil
+		}
+	}
+}
+
+// Release frees the local SEID seid, removing its session if it has one.
+func (t *SessionTable) Release(seid uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.remove(seid)
+}
+
+func (t *SessionTable) remove(seid uint64) *Session {
+	s, ok := t.byLocal[seid]
+	if !ok {
+		return nil
+	}
+	delete(t.byLocal, seid)
+	if s == nil {
+		return nil
+	}
+	t.sessions--
+	if remote := s.RemoteFSEID(); remote != nil {
+		key := remoteKey(remote)
+		if t.byRemote[key] == s {
+			delete(t.byRemote, key)
+		}
+	}
+	return s
+}
+
+// add indexes s under the local SEID reserved for it.
+func (t *SessionTable) add(s *Session) error {
+	seid := s.localFSEID.Seid
+	if other, ok := t.byLocal[seid]; !ok || other != nil {
+		return fmt.Errorf("pfcp: session table: SEID %#x not allocated", seid)
+	}
+	if s.remoteFSEID != nil {
+		key := remoteKey(s.remoteFSEID)
+		if _, ok := t.byRemote[key]; ok {
+			return &SessionError{
+				Cause: CauseRequestRejected,
+				Err:   fmt.Errorf("remote SEID %#x already in use", s.remoteFSEID.Seid),
+			}
+		}
+		t.byRemote[key] = s
+	}
+	t.byLocal[seid] = s
+	t.sessions++
+	return nil
+}
+
+// Establish establishes the session requested by req with a new local SEID,
+// the addresses of its local F-SEID being those of local. The error is a
+// *SessionError, or a failure to allocate a SEID.
+func (t *SessionTable) Establish(local FSEID, req *PFCPSessionEstablishmentRequest) (*Session, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seid, err := t.allocate()
+	if err != nil {
+		return nil, &SessionError{Cause: CauseNoResourcesAvailable, Err: err}
+	}
+	local.Seid = seid
+	s, err := NewSession(&local, req)
+	if err == nil {
+		err = t.add(s)
+	}
+	if err != nil {
+		t.remove(seid)
+		return nil, err
+	}
+	return s, nil
+}
+
+// Bind records the session of the local SEID seid, reserved by Allocate, with
+// the F-SEID of the peer. The session has no rules.
+func (t *SessionTable) Bind(seid uint64, remote *FSEID) (*Session, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &Session{
+		localFSEID:  &FSEID{Seid: seid},
+		remoteFSEID: remote,
+		rules:       newSessionRules(),
+	}
+	if err := t.add(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Modify applies the modification req to the session of the local SEID seid.
+// The error is a *SessionError.
+func (t *SessionTable) Modify(seid uint64, req *PFCPSessionModificationRequest) (*Session, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.byLocal[seid]
+	if s == nil {
+		return nil, sessionNotFound(seid)
+	}
+	oldRemote := s.RemoteFSEID()
+	if req.CPFSEID != nil {
+		if other, ok := t.byRemote[remoteKey(req.CPFSEID)]; ok && other != s {
+			return nil, &SessionError{
+				Cause:       CauseRequestRejected,
+				OffendingIE: ieTypeFSEID,
+				Err:         fmt.Errorf("remote SEID %#x already in use", req.CPFSEID.Seid),
+			}
+		}
+	}
+	if err := s.Modify(req); err != nil {
+		return nil, err
+	}
+	if req.CPFSEID != nil {
+		if oldRemote != nil {
+			delete(t.byRemote, remoteKey(oldRemote))
+		}
+		t.byRemote[remoteKey(req.CPFSEID)] = s
+	}
+	return s, nil
+}
+
+// Delete deletes the session of the local SEID seid and frees the SEID. The
+// error is a *SessionError.
+func (t *SessionTable) Delete(seid uint64) (*Session, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.byLocal[seid]
+	if s == nil {
+		return nil, sessionNotFound(seid)
+	}
+	// A session deleted by itself leaves the table too
+	t.remove(seid)
+	if err := s.Delete(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Lookup returns the session of the local SEID seid.
+func (t *SessionTable) Lookup(seid uint64) (*Session, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	s := t.byLocal[seid]
+	return s, s != nil
+}
+
+// LookupRemote returns the session with the peer F-SEID remote.
+func (t *SessionTable) LookupRemote(remote *FSEID) (*Session, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	s, ok := t.byRemote[remoteKey(remote)]
+	return s, ok
+}
+
+// Len returns the number of sessions in the table.
+func (t *SessionTable) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.sessions
+}
+
+func sessionNotFound(seid uint64) *SessionError {
+	return &SessionError{
+		Cause: CauseSessionContextNotFound,
+		Err:   fmt.Errorf("no session with SEID %#x", seid),
+	}
+}
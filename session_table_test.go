@@ -0,0 +1,146 @@
+package pfcpgolb
+
+import (
+	"net"
+	"sync"
+	"testing"
+)
+
+var testUPFSEID = FSEID{V4: true, Ipv4Address: net.IPv4(10, 0, 0, 2).To4()}
+
+func TestSessionTableAllocate(t *testing.T) {
+	table := NewSessionTable()
+	seen := make(map[uint64]bool)
+	for i := 0; i < 1000; i++ {
+		seid, err := table.Allocate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seid == 0 || seen[seid] {
+			t.Fatalf("SEID %#x allocated, after %d others", seid, i)
+		}
+		seen[seid] = true
+	}
+
+	// Node 2 in the 62 high bits, 3 SEIDs in the 2 low bits
+	table, err := NewSessionTableWithNodeID(2, 62)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seids []uint64
+	for i := 0; i < 3; i++ {
+		seid, err := table.Allocate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seid>>2 != 2 || seid&3 == 0 {
+			t.Errorf("SEID %#x allocated for node 2", seid)
+		}
+		seids = append(seids, seid)
+	}
+	if seid, err := table.Allocate(); err == nil {
+		t.Fatalf("SEID %#x allocated beyond the 3 available", seid)
+	}
+	table.Release(seids[1])
+	if seid, err := table.Allocate(); err != nil || seid != seids[1] {
+		t.Errorf("SEID allocated after release = %#x, %v, want %#x", seid, err, seids[1])
+	}
+
+	if _, err := NewSessionTableWithNodeID(4, 2); err == nil {
+		t.Error("node 4 fits in 2 bits")
+	}
+}
+
+func TestSessionTableLookup(t *testing.T) {
+	table := NewSessionTable()
+	est := testEstablishmentRequest()
+	s, err := table.Establish(testUPFSEID, &est)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seid := s.LocalFSEID().Seid
+	if !s.LocalFSEID().Ipv4Address.Equal(testUPFSEID.Ipv4Address) {
+		t.Errorf("local F-SEID address %s, want %s", s.LocalFSEID().Ipv4Address, testUPFSEID.Ipv4Address)
+	}
+	if got, ok := table.Lookup(seid); !ok || got != s {
+		t.Error("session not found by local SEID")
+	}
+	if got, ok := table.LookupRemote(est.CPFSEID); !ok || got != s {
+		t.Error("session not found by remote F-SEID")
+	}
+
+	// A second session with the same remote F-SEID is rejected, and frees its
+	// SEID
+	if _, err := table.Establish(testUPFSEID, &est); err == nil {
+		t.Fatal("remote F-SEID used twice")
+	} else {
+		checkSessionError(t, err, CauseRequestRejected, 0, nil)
+	}
+	if n := table.Len(); n != 1 {
+		t.Errorf("%d sessions, want 1", n)
+	}
+
+	// A new remote F-SEID replaces the former one
+	newRemote := &FSEID{V4: true, Seid: 0x99, Ipv4Address: est.CPFSEID.Ipv4Address}
+	if _, err := table.Modify(seid, &PFCPSessionModificationRequest{CPFSEID: newRemote}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := table.LookupRemote(est.CPFSEID); ok {
+		t.Error("session found by its former remote F-SEID")
+	}
+	if got, ok := table.LookupRemote(newRemote); !ok || got != s {
+		t.Error("session not found by its new remote F-SEID")
+	}
+
+	if _, err := table.Delete(seid); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := table.Lookup(seid); ok {
+		t.Error("session found by local SEID after deletion")
+	}
+	if _, ok := table.LookupRemote(newRemote); ok {
+		t.Error("session found by remote F-SEID after deletion")
+	}
+	if n := table.Len(); n != 0 {
+		t.Errorf("%d sessions after deletion, want 0", n)
+	}
+	_, err = table.Delete(seid)
+	checkSessionError(t, err, CauseSessionContextNotFound, 0, nil)
+	_, err = table.Modify(seid, &PFCPSessionModificationRequest{})
+	checkSessionError(t, err, CauseSessionContextNotFound, 0, nil)
+}
+
+func TestSessionTableConcurrent(t *testing.T) {
+	const goroutines, sessions = 8, 100
+	table := NewSessionTable()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < sessions; i++ {
+				est := testEstablishmentRequest()
+				est.CPFSEID.Seid = uint64(g*sessions + i + 1)
+				s, err := table.Establish(testUPFSEID, &est)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if _, ok := table.LookupRemote(est.CPFSEID); !ok {
+					t.Errorf("session %#x not found by remote F-SEID", est.CPFSEID.Seid)
+				}
+				if _, err := table.Delete(s.LocalFSEID().Seid); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if n := table.Len(); n != 0 {
+		t.Errorf("%d sessions left, want 0", n)
+	}
+	if len(table.byLocal) != 0 || len(table.byRemote) != 0 {
+		t.Errorf("%d local and %d remote SEIDs left, want 0", len(table.byLocal), len(table.byRemote))
+	}
+}
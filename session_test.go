@@ -0,0 +1,125 @@
+package pfcpgolb
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newTestSession returns the session of testEstablishmentRequest.
+func newTestSession(t *testing.T) *Session {
+	t.Helper()
+	est := testEstablishmentRequest()
+	s, err := NewSession(&FSEID{V4: true, Seid: 1}, &est)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSessionModify(t *testing.T) {
+	s := newTestSession(t)
+	err := s.Modify(&PFCPSessionModificationRequest{
+		CPFSEID:   &FSEID{V4: true, Seid: 2},
+		RemovePDR: []*RemovePDR{{PDRID: &PacketDetectionRuleID{RuleId: 2}}},
+		RemoveFAR: []*RemoveFAR{{FARID: &FARID{FarIdValue: 2}}},
+		CreateURR: []*CreateURR{{URRID: &URRID{UrrIdValue: 1}}},
+		UpdatePDR: []*UpdatePDR{{PDRID: &PacketDetectionRuleID{RuleId: 1}, URRID: []*URRID{{UrrIdValue: 1}}}},
+		UpdateQER: []*UpdateQER{{QERID: &QERID{QERID: 1}, GateStatus: &GateStatus{DLGate: GateClose}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := s.Rules()
+	if _, ok := rules.PDRs[2]; ok {
+		t.Error("PDR 2 not removed")
+	}
+	if _, ok := rules.FARs[2]; ok {
+		t.Error("FAR 2 not removed")
+	}
+	if urrs := rules.PDRs[1].URRID; len(urrs) != 1 || urrs[0].UrrIdValue != 1 {
+		t.Errorf("PDR 1 URRs = %v, want URR 1", urrs)
+	}
+	if gate := rules.QERs[1].GateStatus.DLGate; gate != GateClose {
+		t.Errorf("DL gate = %d, want %d", gate, GateClose)
+	}
+	if seid := s.RemoteFSEID().Seid; seid != 2 {
+		t.Errorf("remote SEID = %d, want 2", seid)
+	}
+}
+
+func TestSessionModifyFailure(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		req        PFCPSessionModificationRequest
+		failedRule *FailedRuleID
+	}{
+		{
+			name: "existing rule created",
+			req: PFCPSessionModificationRequest{
+				CreateFAR: []*CreateFAR{{FARID: &FARID{FarIdValue: 2}, ApplyAction: &ApplyAction{Drop: true}}},
+			},
+			failedRule: NewFailedRuleID(RuleIdTypeFar, 2),
+		},
+		{
+			name:       "unknown PDR removed",
+			req:        PFCPSessionModificationRequest{RemovePDR: []*RemovePDR{{PDRID: &PacketDetectionRuleID{RuleId: 3}}}},
+			failedRule: NewFailedRuleID(RuleIdTypePdr, 3),
+		},
+		{
+			name:       "unknown URR removed",
+			req:        PFCPSessionModificationRequest{RemoveURR: []*RemoveURR{{URRID: &URRID{UrrIdValue: 4}}}},
+			failedRule: NewFailedRuleID(RuleIdTypeUrr, 4),
+		},
+		{
+			name:       "unknown BAR removed",
+			req:        PFCPSessionModificationRequest{RemoveBAR: &RemoveBAR{BARID: &BARID{BarIdValue: 5}}},
+			failedRule: NewFailedRuleID(RuleIdTypeBar, 5),
+		},
+		{
+			name:       "unknown FAR updated",
+			req:        PFCPSessionModificationRequest{UpdateFAR: []*UpdateFAR{{FARID: &FARID{FarIdValue: 6}}}},
+			failedRule: NewFailedRuleID(RuleIdTypeFar, 6),
+		},
+		{
+			name:       "unknown QER updated",
+			req:        PFCPSessionModificationRequest{UpdateQER: []*UpdateQER{{QERID: &QERID{QERID: 7}}}},
+			failedRule: NewFailedRuleID(RuleIdTypeQer, 7),
+		},
+		{
+			// Fails after the removal, creation and update of other rules
+			name: "partway",
+			req: PFCPSessionModificationRequest{
+				RemoveQER: []*RemoveQER{{QERID: &QERID{QERID: 1}}},
+				CreateQER: []*CreateQER{{QERID: &QERID{QERID: 2}, GateStatus: &GateStatus{}}},
+				UpdatePDR: []*UpdatePDR{
+					{PDRID: &PacketDetectionRuleID{RuleId: 1}, QERID: []*QERID{{QERID: 2}}},
+					{PDRID: &PacketDetectionRuleID{RuleId: 2}, QERID: []*QERID{{QERID: 2}}},
+					{PDRID: &PacketDetectionRuleID{RuleId: 8}},
+				},
+			},
+			failedRule: NewFailedRuleID(RuleIdTypePdr, 8),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestSession(t)
+			before := s.Rules()
+			err := s.Modify(&tc.req)
+			checkSessionError(t, err, CauseRuleCreationModificationFailure, 0, tc.failedRule)
+			if !reflect.DeepEqual(s.Rules(), before) {
+				t.Error("failed modification changed the rules")
+			}
+		})
+	}
+}
+
+func TestSessionDelete(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if rules := s.Rules(); len(rules.PDRs)+len(rules.FARs)+len(rules.QERs) != 0 {
+		t.Errorf("rules left after deletion: %+v", rules)
+	}
+	checkSessionError(t, s.Delete(), CauseSessionContextNotFound, 0, nil)
+	checkSessionError(t, s.Modify(&PFCPSessionModificationRequest{}), CauseSessionContextNotFound, 0, nil)
+}
@@ -0,0 +1,90 @@
+package pfcpgolb
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ValidateEstablishment checks the rules created by req: their mandatory
+// IEs, the uniqueness of their IDs, and that the FAR, QER, URR and BAR IDs
+// they refer to are created too. The error is a *SessionError giving the
+// Cause, the Failed Rule ID and the Offending IE of the response rejecting
+// req, so that the CP function can check req before sending it, and the UP
+// function when receiving it.
+func ValidateEstablishment(req *PFCPSessionEstablishmentRequest) error {
+	_, err := establishRules(req)
+	return err
+}
+
+// ValidateModification checks req against rules, the current rules of the
+// session: the rules removed and updated must exist, the rules created must
+// not, and once modified the rules must refer to existing FARs, QERs, URRs
+// and BARs only. The error is a *SessionError as for ValidateEstablishment.
+func ValidateModification(rules SessionRules, req *PFCPSessionModificationRequest) error {
+	_, err := rules.modify(req)
+	return err
+}
+
+// checkReferences checks that the PDRs and FARs refer to existing rules. A
+// dangling reference fails the rule holding it, with the type of the
+// reference as the Offending IE.
+func (r SessionRules) checkReferences() error {
+	for _, id := range sortedKeys(r.PDRs) {
+		pdr := r.PDRs[id]
+		if pdr.FARID != nil {
+			if _, ok := r.FARs[pdr.FARID.FarIdValue]; !ok {
+				return danglingReference(RuleIdTypePdr, uint32(id), ieTypeFARID, "FAR", pdr.FARID.FarIdValue)
+			}
+		}
+		for _, qerID := range pdr.QERID {
+			if _, ok := r.QERs[qerID.QERID]; !ok {
+				return danglingReference(RuleIdTypePdr, uint32(id), ieTypeQERID, "QER", qerID.QERID)
+			}
+		}
+		for _, urrID := range pdr.URRID {
+			if _, ok := r.URRs[urrID.UrrIdValue]; !ok {
+				return danglingReference(RuleIdTypePdr, uint32(id), ieTypeURRID, "URR", urrID.UrrIdValue)
+			}
+		}
+	}
+	for _, id := range sortedKeys(r.FARs) {
+		far := r.FARs[id]
+		if far.BARID != nil {
+			if _, ok := r.BARs[far.BARID.BarIdValue]; !ok {
+				return danglingReference(RuleIdTypeFar, id, ieTypeBARID, "BAR", uint32(far.BARID.BarIdValue))
+			}
+		}
+	}
+	return nil
+}
+
+func danglingReference(ruleIdType uint8, id uint32, ieType uint16, kind string, ref uint32) *SessionError {
+	err := ruleFailure(ruleIdType, id, "%s %d refers to %s %d that does not exist",
+		ruleIdTypeName(ruleIdType), id, kind, ref)
+	err.OffendingIE = ieType
+	return err
+}
+
+func ruleIdTypeName(ruleIdType uint8) string {
+	switch ruleIdType {
+	case RuleIdTypePdr:
+		return "PDR"
+	case RuleIdTypeFar:
+		return "FAR"
+	case RuleIdTypeQer:
+		return "QER"
+	case RuleIdTypeUrr:
+		return "URR"
+	default:
+		return "BAR"
+	}
+}
+
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,210 @@
+package pfcpgolb
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// checkSessionError checks that err is a *SessionError with cause, offendingIE
+// and failedRule, nil for no Failed Rule ID.
+func checkSessionError(t *testing.T, err error, cause uint8, offendingIE uint16, failedRule *FailedRuleID) {
+	t.Helper()
+	var sessionErr *SessionError
+	if !errors.As(err, &sessionErr) {
+		t.Fatalf("error = %v, want a *SessionError", err)
+	}
+	if sessionErr.Cause != cause {
+		t.Errorf("cause = %d, want %d", sessionErr.Cause, cause)
+	}
+	if sessionErr.OffendingIE != offendingIE {
+		t.Errorf("offending IE = %d, want %d", sessionErr.OffendingIE, offendingIE)
+	}
+	if !reflect.DeepEqual(sessionErr.FailedRuleID, failedRule) {
+		t.Errorf("failed rule ID = %+v, want %+v", sessionErr.FailedRuleID, failedRule)
+	}
+}
+
+func TestValidateEstablishment(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		modify      func(req *PFCPSessionEstablishmentRequest)
+		cause       uint8
+		offendingIE uint16
+		failedRule  *FailedRuleID
+	}{
+		{
+			name:   "valid",
+			modify: func(req *PFCPSessionEstablishmentRequest) {},
+		},
+		{
+			name:        "no CP F-SEID",
+			modify:      func(req *PFCPSessionEstablishmentRequest) { req.CPFSEID = nil },
+			cause:       CauseMandatoryIeMissing,
+			offendingIE: ieTypeFSEID,
+		},
+		{
+			name:        "PDR without PDI",
+			modify:      func(req *PFCPSessionEstablishmentRequest) { req.CreatePDR[1].PDI = nil },
+			cause:       CauseMandatoryIeMissing,
+			offendingIE: ieTypePDI,
+		},
+		{
+			name:        "missing FAR",
+			modify:      func(req *PFCPSessionEstablishmentRequest) { req.CreatePDR[0].FARID = &FARID{FarIdValue: 9} },
+			cause:       CauseRuleCreationModificationFailure,
+			offendingIE: ieTypeFARID,
+			failedRule:  NewFailedRuleID(RuleIdTypePdr, 1),
+		},
+		{
+			name: "missing QER",
+			modify: func(req *PFCPSessionEstablishmentRequest) {
+				req.CreatePDR[1].QERID = []*QERID{{QERID: 1}, {QERID: 5}}
+			},
+			cause:       CauseRuleCreationModificationFailure,
+			offendingIE: ieTypeQERID,
+			failedRule:  NewFailedRuleID(RuleIdTypePdr, 2),
+		},
+		{
+			name:        "missing URR",
+			modify:      func(req *PFCPSessionEstablishmentRequest) { req.CreatePDR[0].URRID = []*URRID{{UrrIdValue: 3}} },
+			cause:       CauseRuleCreationModificationFailure,
+			offendingIE: ieTypeURRID,
+			failedRule:  NewFailedRuleID(RuleIdTypePdr, 1),
+		},
+		{
+			name:        "missing BAR",
+			modify:      func(req *PFCPSessionEstablishmentRequest) { req.CreateFAR[1].BARID = &BARID{BarIdValue: 4} },
+			cause:       CauseRuleCreationModificationFailure,
+			offendingIE: ieTypeBARID,
+			failedRule:  NewFailedRuleID(RuleIdTypeFar, 2),
+		},
+		{
+			name: "existing BAR",
+			modify: func(req *PFCPSessionEstablishmentRequest) {
+				req.CreateFAR[1].BARID = &BARID{BarIdValue: 4}
+				req.CreateBAR = &CreateBAR{BARID: &BARID{BarIdValue: 4}}
+			},
+		},
+		{
+			name: "duplicate PDR",
+			modify: func(req *PFCPSessionEstablishmentRequest) {
+				req.CreatePDR[1].PDRID = &PacketDetectionRuleID{RuleId: 1}
+			},
+			cause:      CauseRuleCreationModificationFailure,
+			failedRule: NewFailedRuleID(RuleIdTypePdr, 1),
+		},
+		{
+			name: "duplicate FAR",
+			modify: func(req *PFCPSessionEstablishmentRequest) {
+				req.CreateFAR[1].FARID = &FARID{FarIdValue: 1}
+			},
+			cause:      CauseRuleCreationModificationFailure,
+			failedRule: NewFailedRuleID(RuleIdTypeFar, 1),
+		},
+		{
+			name: "duplicate QER",
+			modify: func(req *PFCPSessionEstablishmentRequest) {
+				req.CreateQER = append(req.CreateQER, req.CreateQER[0])
+			},
+			cause:      CauseRuleCreationModificationFailure,
+			failedRule: NewFailedRuleID(RuleIdTypeQer, 1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := testEstablishmentRequest()
+			tc.modify(&req)
+			err := ValidateEstablishment(&req)
+			if tc.cause == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			checkSessionError(t, err, tc.cause, tc.offendingIE, tc.failedRule)
+		})
+	}
+}
+
+func TestValidateModification(t *testing.T) {
+	est := testEstablishmentRequest()
+	s, err := NewSession(&FSEID{V4: true, Seid: 1}, &est)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := s.Rules()
+
+	for _, tc := range []struct {
+		name        string
+		req         PFCPSessionModificationRequest
+		cause       uint8
+		offendingIE uint16
+		failedRule  *FailedRuleID
+	}{
+		{
+			name: "valid",
+			req: PFCPSessionModificationRequest{
+				UpdatePDR: []*UpdatePDR{{PDRID: &PacketDetectionRuleID{RuleId: 2}, FARID: &FARID{FarIdValue: 1}}},
+				RemoveFAR: []*RemoveFAR{{FARID: &FARID{FarIdValue: 2}}},
+			},
+		},
+		{
+			name:        "removed FAR still referred to",
+			req:         PFCPSessionModificationRequest{RemoveFAR: []*RemoveFAR{{FARID: &FARID{FarIdValue: 2}}}},
+			cause:       CauseRuleCreationModificationFailure,
+			offendingIE: ieTypeFARID,
+			failedRule:  NewFailedRuleID(RuleIdTypePdr, 2),
+		},
+		{
+			name: "updated PDR to a missing QER",
+			req: PFCPSessionModificationRequest{
+				UpdatePDR: []*UpdatePDR{{PDRID: &PacketDetectionRuleID{RuleId: 1}, QERID: []*QERID{{QERID: 7}}}},
+			},
+			cause:       CauseRuleCreationModificationFailure,
+			offendingIE: ieTypeQERID,
+			failedRule:  NewFailedRuleID(RuleIdTypePdr, 1),
+		},
+		{
+			name: "created QER that exists",
+			req: PFCPSessionModificationRequest{
+				CreateQER: []*CreateQER{{QERID: &QERID{QERID: 1}, GateStatus: &GateStatus{}}},
+			},
+			cause:      CauseRuleCreationModificationFailure,
+			failedRule: NewFailedRuleID(RuleIdTypeQer, 1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateModification(rules, &tc.req)
+			if tc.cause == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			checkSessionError(t, err, tc.cause, tc.offendingIE, tc.failedRule)
+		})
+	}
+	if !reflect.DeepEqual(s.Rules(), rules) {
+		t.Error("validation modified the rules")
+	}
+}
+
+func TestValidateDeterministic(t *testing.T) {
+	// Several dangling references, the one of the lowest PDR ID reported
+	req := testEstablishmentRequest()
+	for i, pdr := range req.CreatePDR {
+		pdr.FARID = &FARID{FarIdValue: uint32(10 + i)}
+	}
+	for i := 0; i < 20; i++ {
+		checkSessionError(t, ValidateEstablishment(&req), CauseRuleCreationModificationFailure, ieTypeFARID,
+			NewFailedRuleID(RuleIdTypePdr, 1))
+	}
+
+	m := map[uint32]bool{5: true, 1: true, 9: true, 3: true, 7: true}
+	for i := 0; i < 20; i++ {
+		if keys := sortedKeys(m); !slices.Equal(keys, []uint32{1, 3, 5, 7, 9}) {
+			t.Fatalf("sortedKeys = %v, want [1 3 5 7 9]", keys)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package pfcpgolb
+
+import "sync"
+
+// subscribers are the functions called with events of type E.
+type subscribers[E any] struct {
+	mu   sync.Mutex
+	subs map[int]func(E)
+	next int
+}
+
+// subscribe has f called with the events until the returned function is
+// called.
+func (s *subscribers[E]) subscribe(f func(E)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs == nil {
+		s.subs = make(map[int]func(E))
+	}
+	id := s.next
+	s.next++
+	s.subs[id] = f
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subs, id)
+	}
+}
+
+func (s *subscribers[E]) emit(event E) {
+	s.mu.Lock()
+	subs := make([]func(E), 0, len(s.subs))
+	for _, f := range s.subs {
+		subs = append(subs, f)
+	}
+	s.mu.Unlock()
+	for _, f := range subs {
+		f(event)
+	}
+}
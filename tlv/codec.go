@@ -0,0 +1,124 @@
+package tlv
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// Appender is implemented by the types with a generated encoder.
+// AppendBinary appends the IEs making up the value to b.
+type Appender interface {
+	AppendBinary(b []byte) ([]byte, error)
+}
+
+// Decoder is implemented by the types with a generated decoder. DecodeFrom
+// decodes the IEs of b into the value.
+type Decoder interface {
+	DecodeFrom(b []byte) error
+}
+
+var appenderType = reflect.TypeOf((*Appender)(nil)).Elem()
+
+// AppendIE appends to b the IE of type tag holding v. It is the building
+// block of the generated encoders, and falls back to reflection for the
+// types that have neither a generated encoder nor a BinaryMarshaler.
+func AppendIE(b []byte, tag int, v interface{}) ([]byte, error) {
+	return appendIE(b, tag, 0, v)
+}
+
+// AppendVendorIE appends to b the vendor-specific IE of type tag of the
+// enterprise enterpriseID holding v.
+func AppendVendorIE(b []byte, tag int, enterpriseID uint16, v interface{}) ([]byte, error) {
+	return appendIE(b, tag, enterpriseID, v)
+}
+
+func appendIE(b []byte, tag int, enterpriseID uint16, v interface{}) ([]byte, error) {
+	start := len(b)
+	b = binary.BigEndian.AppendUint16(b, uint16(tag))
+	b = append(b, 0, 0)
+	if IsVendorSpecific(uint16(tag)) {
+		b = binary.BigEndian.AppendUint16(b, enterpriseID)
+	}
+
+	var err error
+	switch v := v.(type) {
+	case Appender:
+		if b, err = v.AppendBinary(b); err != nil {
+			return nil, err
+		}
+	case encoding.BinaryMarshaler:
+		bin, err := v.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, bin...)
+	default:
+		ie, err := buildTLV(tag, v, true)
+		if err != nil {
+			return nil, err
+		}
+		return append(b[:start], withEnterpriseID(ie, tag, enterpriseID)...), nil
+	}
+
+	length := len(b) - start - 4
+	if length > 0xFFFF {
+		return nil, fmt.Errorf("tlv: IE type %d too long: %d bytes", tag, length)
+	}
+	binary.BigEndian.PutUint16(b[start+2:], uint16(length))
+	return b, nil
+}
+
+// Reader iterates over the IEs of a buffer without copying them: the values
+// of the returned IEs share the memory of the buffer.
+type Reader struct {
+	b   []byte
+	n   int
+	pos int
+}
+
+// NewReader returns a Reader over the IEs of b.
+func NewReader(b []byte) Reader {
+	return Reader{b: b, n: len(b)}
+}
+
+// More reports whether IEs remain to be read.
+func (r *Reader) More() bool {
+	return len(r.b) > 0
+}
+
+// Offset returns the offset in the buffer of the next IE to be read.
+func (r *Reader) Offset() int {
+	return r.n - len(r.b)
+}
+
+// Next returns the next IE of the buffer. Malformed IEs are reported as a
+// *DecodeError.
+func (r *Reader) Next() (RawIE, error) {
+	offset := r.Offset()
+	if len(r.b) < 4 {
+		return RawIE{}, &DecodeError{Offset: offset, Err: fmt.Errorf("%w: %d bytes left", ErrTruncated, len(r.b))}
+	}
+	tag := binary.BigEndian.Uint16(r.b)
+	length := int(binary.BigEndian.Uint16(r.b[2:]))
+	if len(r.b)-4 < length {
+		return RawIE{}, &DecodeError{
+			Type:   int(tag),
+			Offset: offset,
+			Err:    fmt.Errorf("%w: length %d, %d bytes left", ErrOverrun, length, len(r.b)-4),
+		}
+	}
+	if IsVendorSpecific(tag) && length < 2 {
+		return RawIE{}, &DecodeError{
+			Type:   int(tag),
+			Offset: offset,
+			Err:    fmt.Errorf("%w: vendor-specific IE without Enterprise ID", ErrTruncated),
+		}
+	}
+	r.pos++
+	ie := newRawIE(tag, r.b[4:4+length:4+length], r.pos)
+	ie.offset = offset
+	r.b = r.b[4+length:]
+	return ie, nil
+}
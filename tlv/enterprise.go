@@ -0,0 +1,84 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type enterpriseIEKey struct {
+	enterpriseID uint16
+	ieType       uint16
+}
+
+// Go types of the registered vendor-specific IEs
+var enterpriseIETypes sync.Map // map[enterpriseIEKey]reflect.Type
+
+// RegisterEnterpriseIE associates the vendor-specific IE ieType of the
+// enterprise enterpriseID with the Go type of v. The value of such an IE is
+// then decoded by RawIE.Decode into a new value of that type, which is
+// encoded and decoded like a struct field: through its BinaryMarshaler and
+// BinaryUnmarshaler methods if it has them, or field by field from its `tlv`
+// tags otherwise.
+func RegisterEnterpriseIE(enterpriseID, ieType uint16, v interface{}) error {
+	if !IsVendorSpecific(ieType) {
+		return fmt.Errorf("tlv: IE type %d is not vendor-specific", ieType)
+	}
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return fmt.Errorf("tlv: nil value registered for IE type %d of enterprise %d", ieType, enterpriseID)
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	key := enterpriseIEKey{enterpriseID: enterpriseID, ieType: ieType}
+	if _, loaded := enterpriseIETypes.LoadOrStore(key, typ); loaded {
+		return fmt.Errorf("tlv: IE type %d of enterprise %d already registered", ieType, enterpriseID)
+	}
+	return nil
+}
+
+// Decode decodes the value of a registered vendor-specific IE, returning a
+// pointer to a new value of the registered type.
+func (ie *RawIE) Decode() (interface{}, error) {
+	typ, ok := enterpriseIETypes.Load(enterpriseIEKey{enterpriseID: ie.EnterpriseID, ieType: ie.Type})
+	if !ok {
+		return nil, fmt.Errorf("tlv: IE type %d of enterprise %d is not registered", ie.Type, ie.EnterpriseID)
+	}
+	v := reflect.New(typ.(reflect.Type)).Interface()
+	if err := decodeValue(ie.Value, v, true); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NewEnterpriseIE encodes v, whose type has been registered for ieType of
+// enterpriseID, as a RawIE that can be added to the unknown IEs of a message.
+func NewEnterpriseIE(enterpriseID, ieType uint16, v interface{}) (RawIE, error) {
+	typ, ok := enterpriseIETypes.Load(enterpriseIEKey{enterpriseID: enterpriseID, ieType: ieType})
+	if !ok {
+		return RawIE{}, fmt.Errorf("tlv: IE type %d of enterprise %d is not registered", ieType, enterpriseID)
+	}
+	if value := reflect.Indirect(reflect.ValueOf(v)); !value.IsValid() || value.Type() != typ.(reflect.Type) {
+		return RawIE{}, fmt.Errorf("tlv: IE type %d of enterprise %d needs a %s value", ieType, enterpriseID, typ)
+	}
+	ie, err := buildTLV(int(ieType), v, true)
+	if err != nil {
+		return RawIE{}, err
+	}
+	return RawIE{Type: ieType, EnterpriseID: enterpriseID, Value: ie[4:]}, nil
+}
+
+// withEnterpriseID inserts the Enterprise ID of a vendor-specific IE after
+// the type and length of the encoded IE, and updates its length.
+func withEnterpriseID(ie []byte, tagVal int, enterpriseID uint16) []byte {
+	if !IsVendorSpecific(uint16(tagVal)) || len(ie) < 4 {
+		return ie
+	}
+	ret := make([]byte, 0, len(ie)+2)
+	ret = binary.BigEndian.AppendUint16(ret, uint16(tagVal))
+	ret = binary.BigEndian.AppendUint16(ret, binary.BigEndian.Uint16(ie[2:])+2)
+	ret = binary.BigEndian.AppendUint16(ret, enterpriseID)
+	return append(ret, ie[4:]...)
+}
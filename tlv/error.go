@@ -0,0 +1,85 @@
+package tlv
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrTruncated is returned when a buffer ends within the header of an IE.
+	ErrTruncated = errors.New("tlv: truncated IE header")
+	// ErrOverrun is returned when the length of an IE exceeds the bytes left
+	// in its buffer.
+	ErrOverrun = errors.New("tlv: IE length overruns buffer")
+	// ErrEmptyValue is returned when a scalar is decoded from an empty IE.
+	ErrEmptyValue = errors.New("tlv: empty IE value")
+	// ErrShortValue is returned when an IE is shorter than the value it holds.
+	ErrShortValue = errors.New("tlv: IE value too short")
+)
+
+// IsLengthError reports whether err is caused by the length of an IE.
+func IsLengthError(err error) bool {
+	return errors.Is(err, ErrTruncated) || errors.Is(err, ErrOverrun) ||
+		errors.Is(err, ErrEmptyValue) || errors.Is(err, ErrShortValue)
+}
+
+// DecodeError is returned by Unmarshal when an IE cannot be decoded. Path
+// names the struct field holding the offending IE, e.g.
+// "CreatePDR[2].PDI.LocalFTEID", and Offset is the position of that IE from
+// the start of the decoded buffer, or of the message once MessageType is set.
+type DecodeError struct {
+	MessageType uint8
+	Path        string
+	Type        int
+	Offset      int
+	Mandatory   bool
+	Err         error
+}
+
+func (e *DecodeError) Error() string {
+	msg := "tlv: decode"
+	if e.MessageType != 0 {
+		msg += fmt.Sprintf(" message type %d", e.MessageType)
+	}
+	if e.Path != "" {
+		msg += " " + e.Path
+	}
+	if e.Type != 0 {
+		msg += fmt.Sprintf(" (IE type %d)", e.Type)
+	}
+	return fmt.Sprintf("%s at offset %d: %s", msg, e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// WrapDecodeError reports that decoding ie into the struct field named field
+// failed with err. index is the position of ie among the elements of a slice
+// field, -1 for other fields. If err already describes an IE nested in ie, the
+// field is prepended to its path and its offset made relative to the buffer
+// holding ie, so that the innermost offending IE is reported.
+func WrapDecodeError(err error, field string, index int, ie RawIE, mandatory bool) error {
+	name := field
+	if index >= 0 {
+		name = fmt.Sprintf("%s[%d]", field, index)
+	}
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		return &DecodeError{Path: name, Type: int(ie.Type), Offset: ie.offset, Mandatory: mandatory, Err: err}
+	}
+	if decodeErr.Path == "" {
+		decodeErr.Path = name
+	} else {
+		decodeErr.Path = name + "." + decodeErr.Path
+	}
+	if decodeErr.Type == 0 {
+		// The grouped IE itself is malformed
+		decodeErr.Type, decodeErr.Mandatory = int(ie.Type), mandatory
+		decodeErr.Offset = ie.offset
+	} else {
+		decodeErr.Offset += ie.offset + ie.headerLen()
+	}
+	return decodeErr
+}
@@ -0,0 +1,203 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"reflect"
+	"sort"
+)
+
+// RawIE is an IE kept in its encoded form. A struct field of type []RawIE
+// tagged `tlv:"unknown"` collects, in the order they were received, the IEs
+// of the buffer that no other field of the struct declares, and Marshal
+// writes them back at the position they were received at, or in the Order of
+// the struct when it has one.
+type RawIE struct {
+	Type uint16
+	// EnterpriseID is only meaningful for vendor-specific IE types (>= 32768)
+	EnterpriseID uint16
+	Value        []byte
+
+	// 1-based position among the IEs of the enclosing struct, 0 if unknown
+	pos int
+	// offset of the IE header in the buffer it was read from
+	offset int
+}
+
+// unknownTag is the `tlv` tag of the field collecting unrecognised IEs.
+const unknownTag = "unknown"
+
+// IsVendorSpecific reports whether the IE type carries an Enterprise ID.
+func IsVendorSpecific(ieType uint16) bool {
+	return ieType&0x8000 != 0
+}
+
+// MarshalBinary returns the IE with its type and length header.
+func (ie *RawIE) MarshalBinary() ([]byte, error) {
+	return ie.appendTo(nil), nil
+}
+
+func (ie *RawIE) appendTo(b []byte) []byte {
+	length := len(ie.Value)
+	if IsVendorSpecific(ie.Type) {
+		length += 2
+	}
+	b = binary.BigEndian.AppendUint16(b, ie.Type)
+	b = binary.BigEndian.AppendUint16(b, uint16(length))
+	if IsVendorSpecific(ie.Type) {
+		b = binary.BigEndian.AppendUint16(b, ie.EnterpriseID)
+	}
+	return append(b, ie.Value...)
+}
+
+func (ie *RawIE) headerLen() int {
+	if IsVendorSpecific(ie.Type) {
+		return 6
+	}
+	return 4
+}
+
+// newRawIE builds the RawIE received at position pos from the value of an IE,
+// splitting off the Enterprise ID of vendor-specific IEs.
+func newRawIE(tag uint16, value []byte, pos int) RawIE {
+	ie := RawIE{Type: tag, Value: value, pos: pos}
+	if IsVendorSpecific(tag) {
+		ie.EnterpriseID = binary.BigEndian.Uint16(value)
+		ie.Value = value[2:]
+	}
+	return ie
+}
+
+// mergeUnknownIEs inserts the encoded unknown IEs among the encoded known IEs
+// at their received position. Unknown IEs without a position, or whose
+// position lies past the known IEs, are appended at the end.
+func mergeUnknownIEs(known [][]byte, unknown reflect.Value) [][]byte {
+	raws, _ := unknown.Interface().([]RawIE)
+	if len(raws) == 0 {
+		return known
+	}
+
+	positioned := make([]RawIE, 0, len(raws))
+	var trailing []RawIE
+	for _, ie := range raws {
+		if ie.pos > 0 {
+			positioned = append(positioned, ie)
+		} else {
+			trailing = append(trailing, ie)
+		}
+	}
+	sort.SliceStable(positioned, func(i, j int) bool { return positioned[i].pos < positioned[j].pos })
+
+	merged := make([][]byte, 0, len(known)+len(raws))
+	for _, ie := range known {
+		for len(positioned) > 0 && positioned[0].pos <= len(merged)+1 {
+			merged = append(merged, positioned[0].appendTo(nil))
+			positioned = positioned[1:]
+		}
+		merged = append(merged, ie)
+	}
+	for _, ie := range append(positioned, trailing...) {
+		merged = append(merged, ie.appendTo(nil))
+	}
+	return merged
+}
+
+// orderTag is the `tlv` tag of the field recording the order of the IEs.
+const orderTag = "order"
+
+// Order is the order the IEs of a struct were received in. A struct field of
+// type Order tagged `tlv:"order"` records it when the known IEs were not
+// received in the order of their fields, and Marshal then writes all the IEs
+// back in the received order. It is empty when the IEs were in field order,
+// the order Marshal uses by default.
+type Order struct {
+	keys []int
+}
+
+// Len returns the number of IEs recorded.
+func (o Order) Len() int {
+	return len(o.keys)
+}
+
+// OrderChecker tracks whether the IEs decoded into a struct are in the order
+// of its fields. It is used by the generated decoders.
+type OrderChecker struct {
+	last      int
+	unordered bool
+}
+
+// Add records an IE decoded into the known field at index field.
+func (c *OrderChecker) Add(field int) {
+	if field < c.last {
+		c.unordered = true
+	}
+	c.last = field
+}
+
+// Order returns the order of the IEs of b, the buffer that was decoded, or an
+// empty Order when they were in field order.
+func (c *OrderChecker) Order(b []byte) Order {
+	if !c.unordered {
+		return Order{}
+	}
+	return newOrder(b)
+}
+
+// newOrder returns the order of the IEs of b, which are known to be well
+// formed.
+func newOrder(b []byte) Order {
+	var order Order
+	r := NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			break
+		}
+		order.keys = append(order.keys, ieKey(int(ie.Type), ie.EnterpriseID))
+	}
+	return order
+}
+
+// orderIEs arranges the encoded known IEs, of the given keys, and the unknown
+// IEs in the recorded order. The IEs that were not received, added since, are
+// written after them: the known ones in field order, then the unknown ones.
+func orderIEs(known [][]byte, keys []int, unknown reflect.Value, order Order) [][]byte {
+	var raws []RawIE
+	if unknown.IsValid() {
+		raws, _ = unknown.Interface().([]RawIE)
+	}
+	knownByKey := make(map[int][]int)
+	for i, key := range keys {
+		knownByKey[key] = append(knownByKey[key], i)
+	}
+	unknownByKey := make(map[int][]int)
+	for i, ie := range raws {
+		key := ieKey(int(ie.Type), ie.EnterpriseID)
+		unknownByKey[key] = append(unknownByKey[key], i)
+	}
+
+	merged := make([][]byte, 0, len(known)+len(raws))
+	knownUsed := make([]bool, len(known))
+	unknownUsed := make([]bool, len(raws))
+	for _, key := range order.keys {
+		if indexes := knownByKey[key]; len(indexes) > 0 {
+			merged = append(merged, known[indexes[0]])
+			knownUsed[indexes[0]] = true
+			knownByKey[key] = indexes[1:]
+		} else if indexes := unknownByKey[key]; len(indexes) > 0 {
+			merged = append(merged, raws[indexes[0]].appendTo(nil))
+			unknownUsed[indexes[0]] = true
+			unknownByKey[key] = indexes[1:]
+		}
+	}
+	for i, ie := range known {
+		if !knownUsed[i] {
+			merged = append(merged, ie)
+		}
+	}
+	for i, ie := range raws {
+		if !unknownUsed[i] {
+			merged = append(merged, ie.appendTo(nil))
+		}
+	}
+	return merged
+}
@@ -0,0 +1,96 @@
+package tlv
+
+import (
+	"bytes"
+	"testing"
+)
+
+type orderedGroup struct {
+	A          *testLeaf   `tlv:"1"`
+	B          []*testLeaf `tlv:"2"`
+	C          *testLeaf   `tlv:"3"`
+	UnknownIEs []RawIE     `tlv:"unknown"`
+	IEOrder    Order       `tlv:"order"`
+}
+
+type orderedMessage struct {
+	Group      *orderedGroup `tlv:"10"`
+	Leaf       *testLeaf     `tlv:"11"`
+	UnknownIEs []RawIE       `tlv:"unknown"`
+	IEOrder    Order         `tlv:"order"`
+}
+
+func ie(tag uint16, value ...byte) []byte {
+	raw := RawIE{Type: tag, Value: value}
+	b, _ := raw.MarshalBinary()
+	return b
+}
+
+func ies(b ...[]byte) []byte {
+	return bytes.Join(b, nil)
+}
+
+func TestOrderRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		reordered bool
+	}{
+		{
+			"field order",
+			ies(ie(10, ies(ie(1, 1), ie(2, 2), ie(2, 3), ie(3, 4))...), ie(11, 5)),
+			false,
+		},
+		{
+			"field order with unknown IEs",
+			ies(ie(99, 9), ie(10, ies(ie(1, 1), ie(98, 8), ie(3, 4))...), ie(11, 5)),
+			false,
+		},
+		{
+			"out of order",
+			ies(ie(11, 5), ie(10, ies(ie(3, 4), ie(2, 2), ie(1, 1), ie(2, 3))...)),
+			true,
+		},
+		{
+			"out of order with unknown IEs",
+			ies(ie(11, 5), ie(99, 9), ie(10, ies(ie(2, 2), ie(98, 8), ie(1, 1), ie(97, 7), ie(2, 3))...), ie(99, 10)),
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg orderedMessage
+			if err := Unmarshal(tt.data, &msg); err != nil {
+				t.Fatal(err)
+			}
+			if reordered := msg.IEOrder.Len() > 0 || msg.Group.IEOrder.Len() > 0; reordered != tt.reordered {
+				t.Errorf("order recorded = %t, want %t", reordered, tt.reordered)
+			}
+			data, err := Marshal(&msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, tt.data) {
+				t.Errorf("Marshal = %x, want %x", data, tt.data)
+			}
+		})
+	}
+}
+
+func TestOrderAddedIEs(t *testing.T) {
+	var group orderedGroup
+	if err := Unmarshal(ies(ie(3, 4), ie(99, 9), ie(1, 1)), &group); err != nil {
+		t.Fatal(err)
+	}
+	// The IEs added after decoding follow the received ones
+	group.B = append(group.B, &testLeaf{Value: []byte{2}})
+	group.UnknownIEs = append(group.UnknownIEs, RawIE{Type: 98, Value: []byte{8}})
+	data, err := Marshal(&group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ies(ie(3, 4), ie(99, 9), ie(1, 1), ie(2, 2), ie(98, 8))
+	if !bytes.Equal(data, want) {
+		t.Errorf("Marshal = %x, want %x", data, want)
+	}
+}
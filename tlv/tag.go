@@ -0,0 +1,76 @@
+package tlv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tagOptions is the string following the IE type in a `tlv` struct tag,
+// e.g. "mandatory" in `tlv:"60,mandatory"`. The "conditional" option is
+// informational: it records the presence of the IE in TS 29.244 but is not
+// checked, as the conditions are enforced by ConditionChecker.
+type tagOptions string
+
+// parseTag splits a `tlv` struct tag into its IE type and its options.
+func parseTag(tag string) (int, tagOptions, error) {
+	name, opts, _ := strings.Cut(tag, ",")
+	tagVal, err := strconv.Atoi(name)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid tlv tag \"%s\", need to be decimal number", tag)
+	}
+	return tagVal, tagOptions(opts), nil
+}
+
+// Contains reports whether a comma-separated list of options contains the
+// given option.
+func (o tagOptions) Contains(optionName string) bool {
+	s := string(o)
+	for s != "" {
+		var name string
+		name, s, _ = strings.Cut(s, ",")
+		if name == optionName {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the value of an option written as name=value.
+func (o tagOptions) Get(optionName string) (string, bool) {
+	s := string(o)
+	for s != "" {
+		var opt string
+		opt, s, _ = strings.Cut(s, ",")
+		if name, value, found := strings.Cut(opt, "="); found && name == optionName {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// enterpriseID returns the Enterprise ID given by the `eid` option of the tag
+// of a vendor-specific IE, 0 when there is none.
+func (o tagOptions) enterpriseID(tagVal int) (uint16, error) {
+	eid, ok := o.Get("eid")
+	if !ok {
+		return 0, nil
+	}
+	if !IsVendorSpecific(uint16(tagVal)) {
+		return 0, fmt.Errorf("invalid tlv tag option \"eid\" on IE type %d, need to be vendor-specific", tagVal)
+	}
+	val, err := strconv.ParseUint(eid, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tlv tag option \"eid=%s\", need to be decimal number", eid)
+	}
+	return uint16(val), nil
+}
+
+// ieKey identifies an IE type among the IEs of a buffer. Vendor-specific IE
+// types are only unique together with their Enterprise ID.
+func ieKey(tagVal int, enterpriseID uint16) int {
+	if IsVendorSpecific(uint16(tagVal)) {
+		return int(enterpriseID)<<16 | tagVal
+	}
+	return tagVal
+}
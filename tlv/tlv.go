@@ -7,25 +7,32 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"strconv"
 
 	logger "github.com/sirupsen/logrus"
 
 )
 
-type fragments map[int][][]byte
+type fragments map[int][]RawIE
 
-func (f fragments) Add(tag int, buf []byte) {
-	f[tag] = append(f[tag], buf)
+func (f fragments) Add(tag int, ie RawIE) {
+	f[tag] = append(f[tag], ie)
 }
 
-func (f fragments) Get(tag int) ([][]byte, bool) {
+func (f fragments) Get(tag int) ([]RawIE, bool) {
 	ret, t := f[tag]
 	return ret, t
 }
 
+// Unmarshal decodes b into v, using the generated DecodeFrom methods of the
+// types that have them.
 func Unmarshal(b []byte, v interface{}) error {
-	return decodeValue(b, v)
+	return decodeValue(b, v, true)
+}
+
+// UnmarshalReflect decodes b into v by reflection only, ignoring the
+// generated DecodeFrom methods.
+func UnmarshalReflect(b []byte, v interface{}) error {
+	return decodeValue(b, v, false)
 }
 
 func isNumber(typ reflect.Type) bool {
@@ -55,9 +62,12 @@ func hasValue(value reflect.Value) bool {
 	}
 }
 
-func decodeValue(b []byte, v interface{}) error {
+func decodeValue(b []byte, v interface{}, useGenerated bool) error {
 	value := reflect.ValueOf(v)
 
+	if decoder, ok := value.Interface().(Decoder); ok && useGenerated {
+		return decoder.DecodeFrom(b)
+	}
 	if unmarshaler, ok := value.Interface().(encoding.BinaryUnmarshaler); ok {
 		err := unmarshaler.UnmarshalBinary(b)
 		return err
@@ -65,6 +75,11 @@ func decodeValue(b []byte, v interface{}) error {
 
 	value = reflect.Indirect(value)
 	valueType := reflect.TypeOf(value.Interface())
+	if size, ok := scalarSize(value.Kind()); ok {
+		if err := checkScalar(b, size, value.Kind()); err != nil {
+			return err
+		}
+	}
 	switch value.Kind() {
 	case reflect.Int8:
 		tmp := int64(int8(b[0]))
@@ -102,16 +117,20 @@ func decodeValue(b []byte, v interface{}) error {
 		if value.IsNil() {
 			value.Set(reflect.New(value.Type().Elem()))
 		}
-		if err := decodeValue(b, value.Interface()); err != nil {
+		if err := decodeValue(b, value.Interface(), useGenerated); err != nil {
 			return err
 		}
 	case reflect.Struct:
 		var tlvFragment fragments
-		if tlvFragmentTmp, err := parseTLV(b); err != nil {
+		var received []RawIE
+		if tlvFragmentTmp, receivedTmp, err := parseTLV(b); err != nil {
 			return err
 		} else {
-			tlvFragment = tlvFragmentTmp
+			tlvFragment, received = tlvFragmentTmp, receivedTmp
 		}
+		// Index of the field of each known IE type
+		known := make(map[int]int)
+		unknownField, orderField := -1, -1
 		for i := 0; i < value.NumField(); i++ {
 			fieldValue := value.Field(i)
 			fieldType := valueType.Field(i)
@@ -120,13 +139,27 @@ func decodeValue(b []byte, v interface{}) error {
 			if !hasTLV {
 				return errors.New("field " + fieldType.Name + " need tag `tlv`")
 			}
+			if tag == unknownTag {
+				unknownField = i
+				continue
+			}
+			if tag == orderTag {
+				orderField = i
+				continue
+			}
 
-			tagVal, err := strconv.Atoi(tag)
+			tagVal, opts, err := parseTag(tag)
 			if err != nil {
-				return fmt.Errorf("invalid tlv tag \"%s\", need to be decimal number", tag)
+				return err
+			}
+			enterpriseID, err := opts.enterpriseID(tagVal)
+			if err != nil {
+				return err
 			}
 
-			if len(tlvFragment[tagVal]) == 0 {
+			key := ieKey(tagVal, enterpriseID)
+			known[key] = i
+			if len(tlvFragment[key]) == 0 {
 				continue
 			}
 
@@ -136,16 +169,39 @@ func decodeValue(b []byte, v interface{}) error {
 				fieldValue.Set(reflect.MakeSlice(fieldValue.Type(), 0, 1))
 			}
 
-			for _, buf := range tlvFragment[tagVal] {
+			isList := fieldType.Type.Kind() == reflect.Slice && fieldType.Type.Elem().Kind() != reflect.Uint8
+			for idx, ie := range tlvFragment[key] {
 				if fieldValue.Kind() != reflect.Ptr {
 					fieldValue = fieldValue.Addr()
 				}
-				err = decodeValue(buf, fieldValue.Interface())
+				err = decodeValue(ie.Value, fieldValue.Interface(), useGenerated)
 				if err != nil {
-					return err
+					index := -1
+					if isList {
+						index = idx
+					}
+					return WrapDecodeError(err, fieldType.Name, index, ie, opts.Contains("mandatory"))
 				}
 			}
 		}
+		if unknownField >= 0 {
+			var unknown []RawIE
+			for _, ie := range received {
+				if _, ok := known[ieKey(int(ie.Type), ie.EnterpriseID)]; !ok {
+					unknown = append(unknown, ie)
+				}
+			}
+			value.Field(unknownField).Set(reflect.ValueOf(unknown))
+		}
+		if orderField >= 0 {
+			var order OrderChecker
+			for _, ie := range received {
+				if i, ok := known[ieKey(int(ie.Type), ie.EnterpriseID)]; ok {
+					order.Add(i)
+				}
+			}
+			value.Field(orderField).Set(reflect.ValueOf(order.Order(b)))
+		}
 	case reflect.Slice:
 		if value.IsNil() {
 			value.Set(reflect.MakeSlice(value.Type(), 0, 1))
@@ -155,7 +211,7 @@ func decodeValue(b []byte, v interface{}) error {
 		} else if valueType.Elem().Kind() == reflect.Ptr || valueType.Elem().Kind() == reflect.Struct ||
 			isNumber(valueType.Elem()) {
 			elemValue := reflect.New(valueType.Elem())
-			if err := decodeValue(b, elemValue.Interface()); err != nil {
+			if err := decodeValue(b, elemValue.Interface(), useGenerated); err != nil {
 				return err
 			}
 			value.Set(reflect.Append(value, elemValue.Elem()))
@@ -166,36 +222,72 @@ func decodeValue(b []byte, v interface{}) error {
 	return nil
 }
 
-func parseTLV(b []byte) (fragments, error) {
+// parseTLV splits b into its IEs, returned both grouped by type and in the
+// order they were received. The values share the memory of b.
+func parseTLV(b []byte) (fragments, []RawIE, error) {
 	tlvFragment := make(fragments)
-	buffer := bytes.NewBuffer(b)
-
-	var tag uint16
-	var length uint16
-	for {
-		if err := binary.Read(buffer, binary.BigEndian, &tag); err != nil {
-			fmt.Printf("Binary Read error: %v", err)
-		}
-		if err := binary.Read(buffer, binary.BigEndian, &length); err != nil {
-			fmt.Printf("Binary Read error: %v", err)
-		}
-		value := make([]byte, length)
-		if _, err := buffer.Read(value); err != nil {
-			return nil, err
-		}
-		tlvFragment.Add(int(tag), value)
-		if buffer.Len() == 0 {
-			break
+	var received []RawIE
+	r := NewReader(b)
+	for r.More() {
+		ie, err := r.Next()
+		if err != nil {
+			return nil, nil, err
 		}
+		tlvFragment.Add(ieKey(int(ie.Type), ie.EnterpriseID), ie)
+		received = append(received, ie)
+	}
+	return tlvFragment, received, nil
+}
+
+// scalarSize returns the number of bytes a scalar of the given kind is
+// decoded from.
+func scalarSize(kind reflect.Kind) (int, bool) {
+	switch kind {
+	case reflect.Int8, reflect.Uint8:
+		return 1, true
+	case reflect.Int16, reflect.Uint16:
+		return 2, true
+	case reflect.Int32, reflect.Uint32:
+		return 4, true
+	case reflect.Int64, reflect.Uint64, reflect.Int, reflect.Uint:
+		return 8, true
 	}
-	return tlvFragment, nil
+	return 0, false
 }
 
+func checkScalar(b []byte, size int, kind reflect.Kind) error {
+	if len(b) == 0 {
+		return fmt.Errorf("%w: %s", ErrEmptyValue, kind)
+	}
+	if len(b) < size {
+		return fmt.Errorf("%w: %d bytes for %s", ErrShortValue, len(b), kind)
+	}
+	return nil
+}
+
+// Marshal encodes the IEs of the struct v, using the generated AppendBinary
+// methods of the types that have them.
 func Marshal(v interface{}) ([]byte, error) {
 	if reflect.TypeOf(v).Kind() != reflect.Struct && reflect.TypeOf(v).Kind() != reflect.Ptr {
 		return nil, errors.New("tlv need struct value to encode")
 	}
-	return buildTLV(0, v)
+	// AppendBinary has a pointer receiver
+	if value := reflect.ValueOf(v); value.Kind() == reflect.Struct &&
+		reflect.PointerTo(value.Type()).Implements(appenderType) {
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		v = ptr.Interface()
+	}
+	return buildTLV(0, v, true)
+}
+
+// MarshalReflect encodes the IEs of the struct v by reflection only,
+// ignoring the generated AppendBinary methods.
+func MarshalReflect(v interface{}) ([]byte, error) {
+	if reflect.TypeOf(v).Kind() != reflect.Struct && reflect.TypeOf(v).Kind() != reflect.Ptr {
+		return nil, errors.New("tlv need struct value to encode")
+	}
+	return buildTLV(0, v, false)
 }
 
 func makeTLV(tag int, value []byte) []byte {
@@ -216,10 +308,19 @@ func makeTLV(tag int, value []byte) []byte {
 	return buf.Bytes()
 }
 
-func buildTLV(tag int, v interface{}) ([]byte, error) {
+func buildTLV(tag int, v interface{}, useGenerated bool) ([]byte, error) {
 	buf := &bytes.Buffer{}
 	value := reflect.ValueOf(v)
 
+	if appender, ok := value.Interface().(Appender); ok && useGenerated {
+		bin, err := appender.AppendBinary(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return makeTLV(tag, bin), nil
+	}
+
 	if marshaler, ok := value.Interface().(encoding.BinaryMarshaler); ok {
 		bin, err := marshaler.MarshalBinary()
 		if err != nil {
@@ -332,6 +433,10 @@ func buildTLV(tag int, v interface{}) ([]byte, error) {
 		}
 		return buf.Bytes(), nil
 	case reflect.Struct:
+		var ies [][]byte
+		var keys []int
+		var unknown reflect.Value
+		var order Order
 		for i := 0; i < value.Type().NumField(); i++ {
 			field := value.Field(i)
 			if !hasValue(field) {
@@ -342,17 +447,53 @@ func buildTLV(tag int, v interface{}) ([]byte, error) {
 			if !hasTLV {
 				return nil, errors.New("field " + structField.Name + " need tag `tlv`")
 			}
-			tagVal, err := strconv.Atoi(tlvTag)
+			if tlvTag == unknownTag {
+				unknown = field
+				continue
+			}
+			if tlvTag == orderTag {
+				order, _ = field.Interface().(Order)
+				continue
+			}
+			tagVal, opts, err := parseTag(tlvTag)
 			if err != nil {
 				return nil, err
 			}
-			subValue, err := buildTLV(tagVal, field.Interface())
+			enterpriseID, err := opts.enterpriseID(tagVal)
 			if err != nil {
 				return nil, err
 			}
-			if err := binary.Write(buf, binary.BigEndian, subValue); err != nil {
+			// Encode the elements of a slice one by one so that unknown IEs
+			// can be placed between them
+			if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+				for j := 0; j < field.Len(); j++ {
+					elem := field.Index(j)
+					if elem.Kind() == reflect.Struct {
+						elem = elem.Addr()
+					}
+					subValue, err := buildTLV(tagVal, elem.Interface(), useGenerated)
+					if err != nil {
+						return nil, err
+					}
+					ies = append(ies, withEnterpriseID(subValue, tagVal, enterpriseID))
+					keys = append(keys, ieKey(tagVal, enterpriseID))
+				}
+				continue
+			}
+			subValue, err := buildTLV(tagVal, field.Interface(), useGenerated)
+			if err != nil {
 				return nil, err
 			}
+			ies = append(ies, withEnterpriseID(subValue, tagVal, enterpriseID))
+			keys = append(keys, ieKey(tagVal, enterpriseID))
+		}
+		if order.Len() > 0 {
+			ies = orderIEs(ies, keys, unknown, order)
+		} else if unknown.IsValid() {
+			ies = mergeUnknownIEs(ies, unknown)
+		}
+		for _, ie := range ies {
+			buf.Write(ie)
 		}
 
 		return makeTLV(tag, buf.Bytes()), nil
@@ -375,7 +516,7 @@ func buildTLV(tag int, v interface{}) ([]byte, error) {
 				if value.Type().Elem().Kind() == reflect.Struct {
 					elem = elem.Addr()
 				}
-				elemBuf, err := buildTLV(tag, elem.Interface())
+				elemBuf, err := buildTLV(tag, elem.Interface(), useGenerated)
 				if err != nil {
 					return nil, err
 				}
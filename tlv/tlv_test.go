@@ -0,0 +1,117 @@
+package tlv_test
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb"
+	"github.com/Nikhil690/pfcpgolb/tlv"
+)
+
+// fuzzBodies are the message bodies FuzzUnmarshal decodes into, each a
+// valid value to seed it with.
+func fuzzBodies() []interface{} {
+	nodeID := &pfcpgolb.NodeID{NodeIdType: pfcpgolb.NodeIdTypeIpv4Address, IP: net.IPv4(10, 0, 0, 1).To4()}
+	recovery := &pfcpgolb.RecoveryTimeStamp{RecoveryTimeStamp: time.Unix(1700000000, 0)}
+	fseid := &pfcpgolb.FSEID{V4: true, Seid: 1, Ipv4Address: net.IPv4(10, 0, 0, 1).To4()}
+	return []interface{}{
+		&pfcpgolb.HeartbeatRequest{RecoveryTimeStamp: recovery},
+		&pfcpgolb.PFCPAssociationSetupRequest{NodeID: nodeID, RecoveryTimeStamp: recovery},
+		&pfcpgolb.PFCPAssociationSetupResponse{
+			NodeID:            nodeID,
+			Cause:             &pfcpgolb.Cause{CauseValue: pfcpgolb.CauseRequestAccepted},
+			RecoveryTimeStamp: recovery,
+		},
+		&pfcpgolb.PFCPSessionEstablishmentRequest{
+			NodeID:  nodeID,
+			CPFSEID: fseid,
+			CreatePDR: []*pfcpgolb.CreatePDR{{
+				PDRID:      &pfcpgolb.PacketDetectionRuleID{RuleId: 1},
+				Precedence: &pfcpgolb.Precedence{PrecedenceValue: 255},
+				PDI: &pfcpgolb.PDI{
+					SourceInterface: &pfcpgolb.SourceInterface{InterfaceValue: pfcpgolb.SourceInterfaceAccess},
+					LocalFTEID:      &pfcpgolb.FTEID{V4: true, Ch: true},
+				},
+				OuterHeaderRemoval: &pfcpgolb.OuterHeaderRemoval{},
+				FARID:              &pfcpgolb.FARID{FarIdValue: 1},
+			}},
+			CreateFAR: []*pfcpgolb.CreateFAR{{
+				FARID:       &pfcpgolb.FARID{FarIdValue: 1},
+				ApplyAction: &pfcpgolb.ApplyAction{Forw: true},
+				ForwardingParameters: &pfcpgolb.ForwardingParametersIEInFAR{
+					DestinationInterface: &pfcpgolb.DestinationInterface{
+						InterfaceValue: pfcpgolb.DestinationInterfaceCore,
+					},
+					NetworkInstance: &pfcpgolb.NetworkInstance{NetworkInstance: "internet"},
+				},
+			}},
+		},
+		&pfcpgolb.PFCPSessionEstablishmentResponse{
+			NodeID:  nodeID,
+			Cause:   &pfcpgolb.Cause{CauseValue: pfcpgolb.CauseRequestAccepted},
+			UPFSEID: fseid,
+		},
+		&pfcpgolb.PFCPSessionModificationRequest{
+			UpdateQER: []*pfcpgolb.UpdateQER{{
+				QERID:      &pfcpgolb.QERID{QERID: 1},
+				GateStatus: &pfcpgolb.GateStatus{DLGate: pfcpgolb.GateClose},
+			}},
+		},
+	}
+}
+
+// newEmpty returns a pointer to a new zero value of the type v points to.
+func newEmpty(v interface{}) interface{} {
+	return reflect.New(reflect.TypeOf(v).Elem()).Interface()
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	bodies := fuzzBodies()
+	for i, body := range bodies {
+		data, err := tlv.Marshal(body)
+		if err != nil {
+			f.Fatalf("Marshal %T: %v", body, err)
+		}
+		f.Add(uint8(i), data)
+	}
+	f.Fuzz(func(t *testing.T, kind uint8, data []byte) {
+		body := bodies[int(kind)%len(bodies)]
+
+		// The generated decoders agree with the reflective one
+		generated, reflective := newEmpty(body), newEmpty(body)
+		err := tlv.Unmarshal(data, generated)
+		reflectErr := tlv.UnmarshalReflect(data, reflective)
+		if (err == nil) != (reflectErr == nil) {
+			t.Fatalf("Unmarshal %T = %v, UnmarshalReflect = %v", body, err, reflectErr)
+		}
+		if err != nil {
+			return
+		}
+		if !reflect.DeepEqual(generated, reflective) {
+			t.Fatalf("Unmarshal %T = %+v, UnmarshalReflect = %+v", body, generated, reflective)
+		}
+
+		// What is decoded is encoded the same after one round trip
+		encoded, err := tlv.Marshal(generated)
+		if err != nil {
+			t.Fatalf("Marshal %+v: %v", generated, err)
+		}
+		decoded := newEmpty(body)
+		if err := tlv.Unmarshal(encoded, decoded); err != nil {
+			t.Fatalf("Unmarshal of re-encoded %x: %v", encoded, err)
+		}
+		again, err := tlv.Marshal(decoded)
+		if err != nil {
+			t.Fatalf("Marshal %+v: %v", decoded, err)
+		}
+		if !bytes.Equal(again, encoded) {
+			t.Fatalf("Marshal = %x, want %x", again, encoded)
+		}
+		if reflected, err := tlv.MarshalReflect(decoded); err != nil || !bytes.Equal(reflected, encoded) {
+			t.Fatalf("MarshalReflect = %x, %v, want %x", reflected, err, encoded)
+		}
+	})
+}
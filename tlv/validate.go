@@ -0,0 +1,109 @@
+package tlv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MissingIEError is returned by Validate when a field tagged `mandatory` does
+// not hold a value, or when a conditional IE required by the other IEs of its
+// struct is missing.
+type MissingIEError struct {
+	Type  int
+	Field string
+	// Conditional is set for a conditional IE, reported by CheckConditions
+	Conditional bool
+}
+
+func (e *MissingIEError) Error() string {
+	presence := "mandatory"
+	if e.Conditional {
+		presence = "conditional"
+	}
+	return fmt.Sprintf("tlv: %s IE %s (type %d) is missing", presence, e.Field, e.Type)
+}
+
+// ConditionChecker is implemented by the structs whose conditional IEs are
+// required depending on their other IEs. CheckConditions returns the
+// *MissingIEError of a required IE that is missing, built by
+// MissingConditionalIE.
+type ConditionChecker interface {
+	CheckConditions() error
+}
+
+// MissingConditionalIE returns the *MissingIEError of the conditional IE held
+// by the field of the struct v.
+func MissingConditionalIE(v interface{}, field string) error {
+	structField, ok := reflect.Indirect(reflect.ValueOf(v)).Type().FieldByName(field)
+	if !ok {
+		return fmt.Errorf("tlv: no field %s in %T", field, v)
+	}
+	tagVal, _, err := parseTag(structField.Tag.Get("tlv"))
+	if err != nil {
+		return err
+	}
+	return &MissingIEError{Type: tagVal, Field: field, Conditional: true}
+}
+
+// Validate checks that every field of v tagged `mandatory` holds a value,
+// descending into the grouped IEs that are present. The structs that
+// implement ConditionChecker then check their conditional IEs; a field tagged
+// `conditional` without a CheckConditions rule for it is never required. The
+// first missing IE is reported as a *MissingIEError.
+func Validate(v interface{}) error {
+	return validateValue(reflect.ValueOf(v))
+}
+
+func validateValue(value reflect.Value) error {
+	value = reflect.Indirect(value)
+	if !value.IsValid() {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < value.NumField(); i++ {
+			fieldType := valueType.Field(i)
+			tag, hasTLV := fieldType.Tag.Lookup("tlv")
+			if !hasTLV || tag == unknownTag || tag == orderTag {
+				continue
+			}
+			tagVal, opts, err := parseTag(tag)
+			if err != nil {
+				return err
+			}
+
+			fieldValue := value.Field(i)
+			if !hasValue(fieldValue) || (fieldValue.Kind() == reflect.Slice && fieldValue.Len() == 0) {
+				if opts.Contains("mandatory") {
+					return &MissingIEError{Type: tagVal, Field: fieldType.Name}
+				}
+				continue
+			}
+			if err := validateValue(fieldValue); err != nil {
+				return err
+			}
+		}
+		if !value.CanAddr() {
+			// CheckConditions has a pointer receiver
+			ptr := reflect.New(valueType)
+			ptr.Elem().Set(value)
+			value = ptr.Elem()
+		}
+		if checker, ok := value.Addr().Interface().(ConditionChecker); ok {
+			return checker.CheckConditions()
+		}
+	case reflect.Slice:
+		elemKind := value.Type().Elem().Kind()
+		if elemKind != reflect.Ptr && elemKind != reflect.Struct {
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := validateValue(value.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package tlv
+
+import (
+	"errors"
+	"testing"
+)
+
+type testLeaf struct {
+	Value []byte
+}
+
+func (l *testLeaf) MarshalBinary() ([]byte, error) { return l.Value, nil }
+
+func (l *testLeaf) UnmarshalBinary(b []byte) error {
+	l.Value = append([]byte(nil), b...)
+	return nil
+}
+
+type testGroup struct {
+	ID     *testLeaf   `tlv:"1,mandatory"`
+	Flag   *testLeaf   `tlv:"2,conditional"`
+	Detail *testLeaf   `tlv:"3,conditional"`
+	Items  []*testLeaf `tlv:"4"`
+}
+
+// CheckConditions requires Detail along with Flag.
+func (g *testGroup) CheckConditions() error {
+	if g.Flag != nil && g.Detail == nil {
+		return MissingConditionalIE(g, "Detail")
+	}
+	return nil
+}
+
+type testMessage struct {
+	Group  *testGroup   `tlv:"10,mandatory"`
+	Groups []*testGroup `tlv:"11"`
+}
+
+func TestValidate(t *testing.T) {
+	leaf := &testLeaf{Value: []byte{1}}
+	tests := []struct {
+		name string
+		msg  testMessage
+		want *MissingIEError
+	}{
+		{"valid", testMessage{Group: &testGroup{ID: leaf}}, nil},
+		{"mandatory missing", testMessage{}, &MissingIEError{Type: 10, Field: "Group"}},
+		{"nested mandatory missing", testMessage{Group: &testGroup{}}, &MissingIEError{Type: 1, Field: "ID"}},
+		{
+			"conditional missing",
+			testMessage{Group: &testGroup{ID: leaf, Flag: leaf}},
+			&MissingIEError{Type: 3, Field: "Detail", Conditional: true},
+		},
+		{
+			"conditional missing in list",
+			testMessage{Group: &testGroup{ID: leaf}, Groups: []*testGroup{{ID: leaf}, {ID: leaf, Flag: leaf}}},
+			&MissingIEError{Type: 3, Field: "Detail", Conditional: true},
+		},
+		{"conditional present", testMessage{Group: &testGroup{ID: leaf, Flag: leaf, Detail: leaf}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Both as a value and as a pointer
+			for _, v := range []interface{}{tt.msg, &tt.msg} {
+				err := Validate(v)
+				if tt.want == nil {
+					if err != nil {
+						t.Fatalf("Validate(%T) = %v", v, err)
+					}
+					continue
+				}
+				var missing *MissingIEError
+				if !errors.As(err, &missing) || *missing != *tt.want {
+					t.Fatalf("Validate(%T) = %v, want %v", v, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMissingConditionalIEUnknownField(t *testing.T) {
+	var missing *MissingIEError
+	if err := MissingConditionalIE(&testGroup{}, "Nope"); err == nil || errors.As(err, &missing) {
+		t.Fatalf("MissingConditionalIE = %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package pfcpgolb
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -54,13 +55,52 @@ type Transaction struct {
 	MessageType    MessageType
 	TxType         TransactionType
 	EventChannel   chan ReceiveEvent
-	Conn           *net.UDPConn
+	Conn           net.PacketConn
 	DestAddr       *net.UDPAddr
 	ConsumerAddr   string
+	// The first transmission went out in a batch
+	sent bool
+	// Request retransmission timers
+	timers RetransmitTimers
+	// Closed when the transaction ends
+	done chan struct{}
+	// SEID of the response to a session related request, when known
+	responseSEID uint64
+	checkSEID    bool
+	// State of a request retransmitted by the scheduler of the server
+	attempts   int
+	deadline   time.Time
+	index      int
+	complete   func(*ReceiveEvent, error)
+	finishOnce sync.Once
+}
+
+// RetransmitTimers are the timers of the reliable delivery of a request
+// (TS 29.244 6.4). With the default Backoff and Jitter, T1 is fixed.
+type RetransmitTimers struct {
+	// T1 is the time waited for the response before retransmitting
+	T1 time.Duration
+	// N1 is the maximum number of retransmissions
+	N1 int
+	// Backoff multiplies the time waited after each retransmission, when
+	// greater than 1
+	Backoff float64
+	// MaxT1 caps the time waited as it grows by Backoff, when set
+	MaxT1 time.Duration
+	// Jitter spreads each time waited randomly by up to that fraction of it,
+	// from 0 to 1 excluded
+	Jitter float64
+}
+
+// DefaultRetransmitTimers are the timers used unless set per call, per peer
+// or per message type.
+var DefaultRetransmitTimers = RetransmitTimers{
+	T1: ResendRequestTimeOutPeriod * time.Second,
+	N1: NumOfResend - 1,
 }
 
 
-func NewTransaction(pfcpMSG *PFCPMessage, binaryMSG []byte, Conn *net.UDPConn, DestAddr *net.UDPAddr) (tx *Transaction) {
+func NewTransaction(pfcpMSG *PFCPMessage, binaryMSG []byte, Conn net.PacketConn, DestAddr *net.UDPAddr) (tx *Transaction) {
 	tx = &Transaction{
 		SendMsg:        binaryMSG,
 		SequenceNumber: pfcpMSG.Header.SequenceNumber,
@@ -68,11 +108,15 @@ func NewTransaction(pfcpMSG *PFCPMessage, binaryMSG []byte, Conn *net.UDPConn, D
 		EventChannel:   make(chan ReceiveEvent),
 		Conn:           Conn,
 		DestAddr:       DestAddr,
+		timers:         DefaultRetransmitTimers,
+		done:           make(chan struct{}),
+		index:          -1,
 	}
 
 	if pfcpMSG.IsRequest() {
 		tx.TxType = SendingRequest
-		tx.ConsumerAddr = Conn.LocalAddr().String()
+		tx.ConsumerAddr = DestAddr.String()
+		tx.responseSEID, tx.checkSEID = expectedResponseSEID(pfcpMSG)
 	} else if pfcpMSG.IsResponse() {
 		tx.TxType = SendingResponse
 		tx.ConsumerAddr = DestAddr.String()
@@ -82,17 +126,90 @@ func NewTransaction(pfcpMSG *PFCPMessage, binaryMSG []byte, Conn *net.UDPConn, D
 	return
 }
 
+// expectedResponseSEID returns the SEID of the response to a session related
+// request: the SEID of the CP F-SEID sent in the request, if any.
+func expectedResponseSEID(reqMsg *PFCPMessage) (uint64, bool) {
+	switch body := reqMsg.Body.(type) {
+	case PFCPSessionEstablishmentRequest:
+		if body.CPFSEID != nil {
+			return body.CPFSEID.Seid, true
+		}
+	case PFCPSessionModificationRequest:
+		if body.CPFSEID != nil {
+			return body.CPFSEID.Seid, true
+		}
+	}
+	return 0, false
+}
+
+// match checks that resMsg answers the request of the transaction.
+func (tx *Transaction) match(resMsg *PFCPMessage) error {
+	if resMsg.Header.MessageType != tx.MessageType+1 {
+		return ErrUnexpectedResponseType
+	}
+	if tx.checkSEID && resMsg.Header.SEID != tx.responseSEID {
+		return ErrUnexpectedSEID
+	}
+	return nil
+}
+
+// deliver passes event to the transaction, unless it has ended.
+func (tx *Transaction) deliver(event ReceiveEvent) bool {
+	if tx.complete != nil {
+		return tx.finish(&event, nil)
+	}
+	select {
+	case tx.EventChannel <- event:
+		return true
+	case <-tx.done:
+		return false
+	}
+}
+
+// finish ends a transaction driven by the scheduler with the response or the
+// failure of its request, returning false when it had already ended.
+func (tx *Transaction) finish(event *ReceiveEvent, err error) bool {
+	finished := false
+	tx.finishOnce.Do(func() {
+		finished = true
+		close(tx.done)
+		tx.complete(event, err)
+	})
+	return finished
+}
+
+func (tx *Transaction) ended() bool {
+	select {
+	case <-tx.done:
+		return true
+	default:
+		return false
+	}
+}
+
 func (tx *Transaction) StartSendingRequest() (*ReceiveEvent, error) {
+	return tx.StartSendingRequestContext(context.Background())
+}
+
+// StartSendingRequestContext sends the request until its response arrives,
+// the retransmissions run out or ctx is done.
+func (tx *Transaction) StartSendingRequestContext(ctx context.Context) (*ReceiveEvent, error) {
 	if tx.TxType != SendingRequest {
 		return nil, errors.New("this transaction is not for sending request")
 	}
+	defer close(tx.done)
 
 	logger.Tracef("Start Request Transaction [%d]", tx.SequenceNumber)
 
-	for iter := 0; iter < NumOfResend; iter++ {
-		_, err := tx.Conn.WriteToUDP(tx.SendMsg, tx.DestAddr)
-		if err != nil {
-			return nil, fmt.Errorf("Request Transaction [%d]: %s", tx.SequenceNumber, err)
+	for iter := 0; iter <= tx.timers.N1; iter++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("Request Transaction [%d]: %w", tx.SequenceNumber, err)
+		}
+		if iter > 0 || !tx.sent {
+			_, err := tx.Conn.WriteTo(tx.SendMsg, tx.DestAddr)
+			if err != nil {
+				return nil, fmt.Errorf("Request Transaction [%d]: %s", tx.SequenceNumber, err)
+			}
 		}
 		logger.Tracef("Request Transaction [%d]: Sent a PFCP request packet", tx.SequenceNumber)
 
@@ -102,9 +219,11 @@ func (tx *Transaction) StartSendingRequest() (*ReceiveEvent, error) {
 				logger.Tracef("Request Transaction [%d]: receive valid response", tx.SequenceNumber)
 				return &event, nil
 			}
-		case <-time.After(ResendRequestTimeOutPeriod * time.Second):
+		case <-time.After(tx.timers.interval(iter)):
 			logger.Tracef("Request Transaction [%d]: timeout expire", tx.SequenceNumber)
 			continue
+		case <-ctx.Done():
+			return nil, fmt.Errorf("Request Transaction [%d]: %w", tx.SequenceNumber, ctx.Err())
 		}
 	}
 	return nil, fmt.Errorf("Request Transaction [%d]: retry-out", tx.SequenceNumber)
@@ -114,13 +233,16 @@ func (tx *Transaction) StartSendingResponse() error {
 	if tx.TxType != SendingResponse {
 		return errors.New("this transaction is not for sending response")
 	}
+	defer close(tx.done)
 
 	logger.Tracef("Start Response Transaction [%d]", tx.SequenceNumber)
 
-	for {
-		_, err := tx.Conn.WriteToUDP(tx.SendMsg, tx.DestAddr)
-		if err != nil {
-			return fmt.Errorf("Response Transaction [%d]: sending error", tx.SequenceNumber)
+	for sent := tx.sent; ; sent = false {
+		if !sent {
+			_, err := tx.Conn.WriteTo(tx.SendMsg, tx.DestAddr)
+			if err != nil {
+				return fmt.Errorf("Response Transaction [%d]: sending error", tx.SequenceNumber)
+			}
 		}
 
 		select {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net"
     "sync"
+    "time"
 )
 
 const (
@@ -14,6 +15,9 @@ const (
 type Message struct {
     RemoteAddr  *net.UDPAddr
     PfcpMessage *PFCPMessage
+    // Session is the session of a session related request, set by a ServeMux
+    // with a session table
+    Session *Session
 }
 
 type ConsumerTable struct {
@@ -23,12 +27,51 @@ type ConsumerTable struct {
 
 type PfcpServer struct {
     Addr string
-    Conn *net.UDPConn
+    // Config, when it has listen addresses, takes precedence over Addr
+    Config ServerConfig
+    // Conn is the UDP socket opened by Listen, or any packet transport using
+    // *net.UDPAddr addresses set before reading or writing
+    Conn net.PacketConn
+    // Local PFCP entity, used in the messages built by the server itself
+    NodeID            *NodeID
+    RecoveryTimeStamp time.Time
+    // RejectMalformedRequests makes ReadFrom answer a request that fails to
+    // decode with an error response instead of leaving the peer to time out
+    RejectMalformedRequests bool
+    // PriorityQueueSize enables queueing the received requests by message
+    // priority, holding at most that many requests not read yet
+    PriorityQueueSize int
+    queueOnce         sync.Once
+    queueMu           sync.Mutex
+    queueCond         *sync.Cond
+    queue             receiveQueue
+    queueOrder        uint64
+    queueErr          error
+    // Messages received concatenated in a datagram, not returned by ReadFrom yet
+    pendingMu sync.Mutex
+    pending   []pendingMessage
+    // Windows of the asynchronous requests per peer address
+    windowMu sync.Mutex
+    windows  map[string]*peerWindow
+    // Responses sent, replayed to retransmitted requests
+    responses responseCache
+    // Sequence numbers of the requests sent, per local address when Conn
+    // sends from several ones
+    seqAllocators sync.Map
+    // Retransmissions of the requests waiting for their response
+    retransmitter retransmitScheduler
+    // Retransmission timers per peer address
+    peerTimers sync.Map
     // Consumer Table
     // Map Consumer IP to its tx table
     ConsumerTable ConsumerTable
 }
 
+type pendingMessage struct {
+    data []byte
+    addr *net.UDPAddr
+}
+
 var ErrReceivedResentRequest = errors.New("received a request that is re-sent")
 
 type ReceiveEventType uint8
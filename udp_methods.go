@@ -1,9 +1,11 @@
 package pfcpgolb
 
 import (
+	"context"
 	"errors"
 	"net"
 	"fmt"
+	"sync"
 	logger "github.com/sirupsen/logrus"
 
 )
@@ -13,27 +15,54 @@ func NewPfcpServer(addr string) *PfcpServer {
     return &server
 }
 
+// NewPfcpServerConn returns a server exchanging its messages over conn, which
+// needs no Listen.
+func NewPfcpServerConn(conn net.PacketConn) *PfcpServer {
+	return &PfcpServer{Addr: conn.LocalAddr().String(), Conn: conn}
+}
+
+// udpAddr returns the UDP address of a packet transport address.
+func udpAddr(addr net.Addr) (*net.UDPAddr, error) {
+	if udpAddr, ok := addr.(*net.UDPAddr); ok {
+		return udpAddr, nil
+	}
+	return net.ResolveUDPAddr("udp", addr.String())
+}
+
 func (t *ConsumerTable) LoadOrStore(consumerAddr string, storeTable *TxTable) (*TxTable, bool) {
 	txTable, loaded := t.m.LoadOrStore(consumerAddr, storeTable)
 	return txTable.(*TxTable), loaded
 }
 
+// Listen opens the sockets configured by Config, or else listens on Addr.
 func (pfcpServer *PfcpServer) Listen() error {
-	var serverIp net.IP
-	if pfcpServer.Addr == "" {
-		serverIp = net.IPv4zero
-	} else {
-		serverIp = net.ParseIP(pfcpServer.Addr)
+	cfg := pfcpServer.Config
+	if len(cfg.ListenAddrs) == 0 && pfcpServer.Addr != "" {
+		cfg.ListenAddrs = []string{pfcpServer.Addr}
+	}
+	network, addrs, err := cfg.resolve()
+	if err != nil {
+		return err
 	}
 
-	addr := &net.UDPAddr{
-		IP:   serverIp,
-		Port: PFCP_PORT,
+	conns := make([]*net.UDPConn, 0, len(addrs))
+	for _, addr := range addrs {
+		conn, err := net.ListenUDP(network, addr)
+		if err != nil {
+			for _, conn := range conns {
+				conn.Close()
+			}
+			return fmt.Errorf("pfcp: listen: %w", err)
+		}
+		conns = append(conns, conn)
 	}
 
-	conn, err := net.ListenUDP("udp", addr)
-	pfcpServer.Conn = conn
-	return err
+	if len(conns) == 1 {
+		pfcpServer.Conn = conns[0]
+	} else {
+		pfcpServer.Conn = newMultiConn(conns)
+	}
+	return nil
 }
 
 func NewMessage(remoteAddr *net.UDPAddr, pfcpmessage *PFCPMessage) (msg *Message) {
@@ -43,9 +72,17 @@ func NewMessage(remoteAddr *net.UDPAddr, pfcpmessage *PFCPMessage) (msg *Message
 	}
 }
 
+// ReadFrom returns the next message received. With a PriorityQueueSize, the
+// requests waiting to be read are returned by message priority.
 func (pfcpServer *PfcpServer) ReadFrom() (*Message, error) {
-	buf := make([]byte, PFCP_MAX_UDP_LEN)
-	n, addr, err := pfcpServer.Conn.ReadFromUDP(buf)
+	if pfcpServer.PriorityQueueSize > 0 {
+		return pfcpServer.readQueued(context.Background())
+	}
+	return pfcpServer.readMessage()
+}
+
+func (pfcpServer *PfcpServer) readMessage() (*Message, error) {
+	buf, addr, err := pfcpServer.nextMessage()
 	if err != nil {
 		return nil, err
 	}
@@ -53,45 +90,109 @@ func (pfcpServer *PfcpServer) ReadFrom() (*Message, error) {
 	pfcpMsg := &PFCPMessage{}
 	msg := NewMessage(addr, pfcpMsg)
 
-	err = pfcpMsg.Unmarshal(buf[:n])
+	err = pfcpMsg.Unmarshal(buf)
 	if err != nil {
+		if pfcpServer.RejectMalformedRequests && pfcpMsg.IsRequest() && len(buf) >= pfcpMsg.Header.Len() {
+			pfcpServer.rejectRequest(pfcpMsg, addr, err)
+		}
 		return msg, err
 	}
 
 	if pfcpMsg.IsRequest() {
-		// Todo: Implement SendingResponse type of reliable delivery
-		tx, err := pfcpServer.FindTransaction(pfcpMsg, addr)
-		if err != nil {
-			return msg, err
-		}
-		if tx != nil {
-			// tx != nil => Already Replied => Resend Request
-			tx.EventChannel <- ReceiveEvent{
-				Type:       ReceiveEventTypeResendRequest,
-				RemoteAddr: addr,
-				RcvMsg:     pfcpMsg,
-			}
+		if pfcpServer.replayResponse(pfcpMsg, addr) {
 			return msg, ErrReceivedResentRequest
-		} else {
-			// tx == nil => New Request
-			return msg, nil
 		}
+		return msg, nil
 	} else if pfcpMsg.IsResponse() {
-		tx, err := pfcpServer.FindTransaction(pfcpMsg, pfcpServer.Conn.LocalAddr().(*net.UDPAddr))
+		tx, err := pfcpServer.matchResponse(pfcpMsg, addr)
 		if err != nil {
 			return msg, err
 		}
 
-		tx.EventChannel <- ReceiveEvent{
+		if !tx.deliver(ReceiveEvent{
 			Type:       ReceiveEventTypeValidResponse,
 			RemoteAddr: addr,
 			RcvMsg:     pfcpMsg,
+		}) {
+			logger.Debugf("Response SEQ[%d] from %s arrived after the end of its transaction",
+				pfcpMsg.Header.SequenceNumber, addr)
 		}
 	}
 
 	return msg, nil
 }
 
+// matchResponse returns the transaction of the request answered by resMsg,
+// received from addr.
+func (pfcpServer *PfcpServer) matchResponse(resMsg *PFCPMessage, addr *net.UDPAddr) (*Transaction, error) {
+	unmatched := func(err error) error {
+		return &UnmatchedResponseError{
+			RemoteAddr:     addr,
+			MessageType:    resMsg.Header.MessageType,
+			SequenceNumber: resMsg.Header.SequenceNumber,
+			SEID:           resMsg.Header.SEID,
+			Err:            err,
+		}
+	}
+
+	txTable, ok := pfcpServer.ConsumerTable.Load(addr.String())
+	if !ok {
+		return nil, unmatched(ErrNoTransaction)
+	}
+	tx, ok := txTable.Load(resMsg.Header.SequenceNumber)
+	if !ok || tx.TxType != SendingRequest {
+		return nil, unmatched(ErrNoTransaction)
+	}
+	if err := tx.match(resMsg); err != nil {
+		return nil, unmatched(err)
+	}
+	return tx, nil
+}
+
+// nextMessage returns the next message received, reading a new datagram once
+// all the messages concatenated in the previous one have been returned.
+func (pfcpServer *PfcpServer) nextMessage() ([]byte, *net.UDPAddr, error) {
+	pfcpServer.pendingMu.Lock()
+	if len(pfcpServer.pending) > 0 {
+		next := pfcpServer.pending[0]
+		pfcpServer.pending = pfcpServer.pending[1:]
+		pfcpServer.pendingMu.Unlock()
+		return next.data, next.addr, nil
+	}
+	pfcpServer.pendingMu.Unlock()
+
+	buf := make([]byte, PFCP_MAX_UDP_LEN)
+	var n int
+	var addr *net.UDPAddr
+	for addr == nil {
+		var remoteAddr net.Addr
+		var err error
+		n, remoteAddr, err = pfcpServer.Conn.ReadFrom(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		if addr, err = udpAddr(remoteAddr); err != nil {
+			logger.Warnf("Drop datagram from %s: %+v", remoteAddr, err)
+		}
+	}
+
+	msgs, err := SplitDatagram(buf[:n])
+	if err != nil {
+		logger.Warnf("Datagram from %s: %+v", addr, err)
+		if len(msgs) <= 1 {
+			// Leave it to Unmarshal to report what is wrong with the message
+			return buf[:n], addr, nil
+		}
+	}
+
+	pfcpServer.pendingMu.Lock()
+	for _, data := range msgs[1:] {
+		pfcpServer.pending = append(pfcpServer.pending, pendingMessage{data: data, addr: addr})
+	}
+	pfcpServer.pendingMu.Unlock()
+	return msgs[0], addr, nil
+}
+
 func (t *ConsumerTable) Load(consumerAddr string) (*TxTable, bool) {
 	txTable, ok := t.m.Load(consumerAddr)
 	if ok {
@@ -158,48 +259,247 @@ func (pfcpServer *PfcpServer) RemoveTransaction(tx *Transaction) (err error) {
 
 
 func (pfcpServer *PfcpServer) WriteRequestTo(reqMsg *PFCPMessage, addr *net.UDPAddr) (resMsg *Message, err error) {
+	return pfcpServer.SendRequest(context.Background(), reqMsg, addr)
+}
+
+// SendRequest sends reqMsg to addr and waits for its response, retransmitting
+// it with the timers set by WithRetransmitTimers on ctx, else those of the
+// peer, else those of its message type in Config.Retransmit. It gives up
+// when ctx is done, ending the transaction. A sequence number of 0 is
+// replaced by the next one free.
+func (pfcpServer *PfcpServer) SendRequest(ctx context.Context, reqMsg *PFCPMessage, addr *net.UDPAddr) (*Message, error) {
 	if !reqMsg.IsRequest() {
 		return nil, errors.New("not a request message")
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	buf, err := reqMsg.Marshal()
+	type result struct {
+		resMsg *Message
+		err    error
+	}
+	results := make(chan result, 1)
+	tx, err := pfcpServer.newRequestTransaction(ctx, reqMsg, addr, func(resMsg *Message, err error) {
+		results <- result{resMsg, err}
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	tx := NewTransaction(reqMsg, buf, pfcpServer.Conn, addr)
+	pfcpServer.startRequest(tx)
+	select {
+	case r := <-results:
+		return r.resMsg, r.err
+	case <-ctx.Done():
+		tx.finish(nil, fmt.Errorf("Request Transaction [%d]: %w", tx.SequenceNumber, ctx.Err()))
+		r := <-results
+		return r.resMsg, r.err
+	}
+}
 
-	err = pfcpServer.PutTransaction(tx)
+// newRequestTransaction encodes msg and stores its transaction, which passes
+// the response or the failure of the request to complete. A request with a
+// sequence number of 0 gets the next sequence number free.
+func (pfcpServer *PfcpServer) newRequestTransaction(ctx context.Context, msg *PFCPMessage, addr *net.UDPAddr,
+	complete func(*Message, error),
+) (*Transaction, error) {
+	timers, err := pfcpServer.requestTimers(ctx, msg.Header.MessageType, addr)
 	if err != nil {
 		return nil, err
 	}
+	allocate := msg.Header.SequenceNumber == 0
+	for {
+		if allocate {
+			txTable, _ := pfcpServer.ConsumerTable.LoadOrStore(addr.String(), &TxTable{})
+			seq, err := pfcpServer.sequenceAllocator(addr).next(func(seq uint32) bool {
+				_, inFlight := txTable.Load(seq)
+				return inFlight
+			})
+			if err != nil {
+				return nil, err
+			}
+			msg.Header.SequenceNumber = seq
+		}
+
+		buf, err := msg.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("marshal error: %w", err)
+		}
+
+		tx := NewTransaction(msg, buf, pfcpServer.Conn, addr)
+		tx.timers = timers
+		pfcpServer.bindRequest(tx, complete)
+		err = pfcpServer.PutTransaction(tx)
+		if err == nil {
+			return tx, nil
+		}
+		if !allocate {
+			return nil, fmt.Errorf("PutTransaction error: %w", err)
+		}
+		// Taken in the meantime by a request with its own sequence number
+	}
+}
+
+// SetPeerTimers overrides the retransmission timers of the requests sent to
+// addr.
+func (pfcpServer *PfcpServer) SetPeerTimers(addr *net.UDPAddr, timers RetransmitTimers) error {
+	if err := timers.validate(); err != nil {
+		return err
+	}
+	pfcpServer.peerTimers.Store(addr.String(), timers)
+	return nil
+}
+
+// ClearPeerTimers restores the default retransmission timers for addr.
+func (pfcpServer *PfcpServer) ClearPeerTimers(addr *net.UDPAddr) {
+	pfcpServer.peerTimers.Delete(addr.String())
+}
 
-	return pfcpServer.StartReqTxLifeCycle(tx)
+// PeerTimers returns the retransmission timers set for addr, or else
+// DefaultRetransmitTimers.
+func (pfcpServer *PfcpServer) PeerTimers(addr *net.UDPAddr) RetransmitTimers {
+	if timers, ok := pfcpServer.peerTimers.Load(addr.String()); ok {
+		return timers.(RetransmitTimers)
+	}
+	return DefaultRetransmitTimers
 }
 
 func (pfcpServer *PfcpServer) WriteResponseTo(resMsg *PFCPMessage, addr *net.UDPAddr) {
+	if err := pfcpServer.writeResponse(resMsg, addr); err != nil {
+		logger.Warnf("%+v", err)
+	}
+}
+
+func (pfcpServer *PfcpServer) writeResponse(resMsg *PFCPMessage, addr *net.UDPAddr) error {
 	if !resMsg.IsResponse() {
-		logger.Warn("not a response message")
-		return
+		return errors.New("not a response message")
 	}
 
 	buf, err := resMsg.Marshal()
 	if err != nil {
-		logger.Warnf("marshal error: %+v", err)
-		return
+		return fmt.Errorf("marshal error: %w", err)
+	}
+	if _, err := pfcpServer.Conn.WriteTo(buf, addr); err != nil {
+		return fmt.Errorf("Response SEQ[%d]: %w", resMsg.Header.SequenceNumber, err)
 	}
+	pfcpServer.responses.put(addr.String(), resMsg.Header.SequenceNumber, buf, pfcpServer.responseRetention())
+	return nil
+}
 
-	tx := NewTransaction(resMsg, buf, pfcpServer.Conn, addr)
+// replayResponse handles the retransmissions of a request received from addr:
+// the response, once sent, is sent again, and the request is otherwise
+// dropped while being processed. It returns false for a new request.
+func (pfcpServer *PfcpServer) replayResponse(reqMsg *PFCPMessage, addr *net.UDPAddr) bool {
+	seq := reqMsg.Header.SequenceNumber
+	if pfcpServer.responses.begin(addr.String(), seq, pfcpServer.responseRetention()) {
+		return false
+	}
+	data, _ := pfcpServer.responses.get(addr.String(), seq)
+	if data == nil {
+		logger.Debugf("Drop retransmitted request SEQ[%d] from %s being processed", seq, addr)
+		return true
+	}
+	logger.Tracef("Replay response SEQ[%d] to %s", seq, addr)
+	if _, err := pfcpServer.Conn.WriteTo(data, addr); err != nil {
+		logger.Warnf("Replay response SEQ[%d] to %s: %+v", seq, addr, err)
+	}
+	return true
+}
+
+// WriteBatchTo sends msgs to addr concatenated in as few datagrams as
+// possible, using the FO flag of the header. Each request keeps its own
+// transaction, retransmitted on its own, and WriteBatchTo waits for their
+// responses, returned at the index of the request. The entries of the
+// responses are nil.
+func (pfcpServer *PfcpServer) WriteBatchTo(msgs []*PFCPMessage, addr *net.UDPAddr) ([]*Message, error) {
+	txs := make([]*Transaction, 0, len(msgs))
+	encoded := make([][]byte, 0, len(msgs))
+	resMsgs := make([]*Message, len(msgs))
+	errs := make([]error, len(msgs))
+	var wg sync.WaitGroup
+	abort := func(err error) ([]*Message, error) {
+		for _, tx := range txs {
+			if tx != nil {
+				tx.finish(nil, err)
+			}
+		}
+		return nil, err
+	}
 
-	err = pfcpServer.PutTransaction(tx)
+	for i, msg := range msgs {
+		if !msg.IsRequest() && !msg.IsResponse() {
+			return abort(fmt.Errorf("message type %d is neither a request nor a response", msg.Header.MessageType))
+		}
+		msg.Header.FO = 0
+		if msg.IsResponse() {
+			buf, err := msg.Marshal()
+			if err != nil {
+				return abort(fmt.Errorf("marshal error: %w", err))
+			}
+			encoded = append(encoded, buf)
+			txs = append(txs, nil)
+			continue
+		}
+		i := i
+		wg.Add(1)
+		tx, err := pfcpServer.newRequestTransaction(context.Background(), msg, addr, func(resMsg *Message, err error) {
+			resMsgs[i], errs[i] = resMsg, err
+			wg.Done()
+		})
+		if err != nil {
+			wg.Done()
+			return abort(err)
+		}
+		tx.sent = true
+		txs = append(txs, tx)
+		encoded = append(encoded, tx.SendMsg)
+	}
+
+	datagrams, err := packDatagrams(encoded)
 	if err != nil {
-		logger.Warnf("PutTransaction error: %+v", err)
+		return abort(err)
+	}
+	for _, datagram := range datagrams {
+		if _, err := pfcpServer.Conn.WriteTo(datagram, addr); err != nil {
+			return abort(err)
+		}
+	}
+
+	for i, tx := range txs {
+		if tx == nil {
+			pfcpServer.responses.put(addr.String(), msgs[i].Header.SequenceNumber, encoded[i],
+				pfcpServer.responseRetention())
+			continue
+		}
+		pfcpServer.startRequest(tx)
+	}
+	wg.Wait()
+	return resMsgs, errors.Join(errs...)
+}
+
+// rejectRequest answers a request that failed to decode with err, unless it is
+// the retransmission of a request that has already been answered.
+func (pfcpServer *PfcpServer) rejectRequest(reqMsg *PFCPMessage, addr *net.UDPAddr, err error) {
+	if pfcpServer.replayResponse(reqMsg, addr) {
 		return
 	}
 
-	go pfcpServer.StartResTxLifeCycle(tx)
+	cause, offendingIE := rejectCause(err)
+	resMsg, err := NewErrorResponse(reqMsg, cause, offendingIE, pfcpServer.NodeID, pfcpServer.RecoveryTimeStamp)
+	if err == nil {
+		logger.Debugf("Reject request type %d SEQ[%d] from %s with cause %d", reqMsg.Header.MessageType,
+			reqMsg.Header.SequenceNumber, addr, cause)
+		err = pfcpServer.writeResponse(resMsg, addr)
+	}
+	if err != nil {
+		logger.Warnf("Reject request error: %+v", err)
+		// Without a response, the retransmissions are rejected again
+		pfcpServer.responses.forget(addr.String(), reqMsg.Header.SequenceNumber)
+	}
 }
 
 func (pfcpServer *PfcpServer) Close() error {
+	pfcpServer.retransmitter.close()
 	return pfcpServer.Conn.Close()
 }
\ No newline at end of file
@@ -0,0 +1,289 @@
+package pfcpgolb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nikhil690/pfcpgolb/memconn"
+)
+
+// exchange sends data from conn to addr and returns the decoded response.
+func exchange(t *testing.T, conn net.PacketConn, addr net.Addr, data []byte) *PFCPMessage {
+	t.Helper()
+	if _, err := conn.WriteTo(data, addr); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, PFCP_MAX_UDP_LEN)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res PFCPMessage
+	if err := res.Unmarshal(buf[:n]); err != nil {
+		t.Fatal(err)
+	}
+	return &res
+}
+
+// readMessages reads the messages received by server until the end of the
+// test.
+func readMessages(t *testing.T, server *PfcpServer) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if msg, _ := server.ReadFrom(); msg == nil {
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		server.Close()
+		<-done
+	})
+}
+
+func TestRejectMalformedRequests(t *testing.T) {
+	network := memconn.NewNetwork()
+	up := newTestServer(t, network, testUPAddr)
+	up.RejectMalformedRequests = true
+	readMessages(t, up)
+	conn, err := network.Listen(testCPAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+		body interface{}
+	}{
+		{
+			"heartbeat without recovery time stamp",
+			[]byte{0x20, byte(PFCP_HEARTBEAT_REQUEST), 0, 4, 0, 0, 1, 0},
+			HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: up.RecoveryTimeStamp}},
+		},
+		{
+			"PFD management",
+			[]byte{0x20, byte(PFCP_PFD_MANAGEMENT_REQUEST), 0, 4, 0, 0, 2, 0},
+			PFCPPFDManagementResponse{Cause: &Cause{CauseValue: CauseRequestRejected}},
+		},
+		{
+			"node report",
+			[]byte{0x20, byte(PFCP_NODE_REPORT_REQUEST), 0, 4, 0, 0, 3, 0},
+			PFCPNodeReportResponse{NodeID: up.NodeID, Cause: &Cause{CauseValue: CauseRequestRejected}},
+		},
+		{
+			"session set deletion",
+			[]byte{0x20, byte(PFCP_SESSION_SET_DELETION_REQUEST), 0, 4, 0, 0, 4, 0},
+			PFCPSessionSetDeletionResponse{NodeID: up.NodeID, Cause: &Cause{CauseValue: CauseRequestRejected}},
+		},
+		{
+			"session report",
+			[]byte{0x21, byte(PFCP_SESSION_REPORT_REQUEST), 0, 12, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 5, 0},
+			PFCPSessionReportResponse{Cause: &Cause{CauseValue: CauseRequestRejected}},
+		},
+		{
+			"session establishment without F-SEID",
+			[]byte{0x21, byte(PFCP_SESSION_ESTABLISHMENT_REQUEST), 0, 12, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 6, 0},
+			PFCPSessionEstablishmentResponse{
+				NodeID:      up.NodeID,
+				Cause:       &Cause{CauseValue: CauseMandatoryIeMissing},
+				OffendingIE: &OffendingIE{TypeOfOffendingIe: 60},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := exchange(t, conn, testUPAddr, tt.data)
+			var req Header
+			if err := req.UnmarshalBinary(tt.data); err != nil {
+				t.Fatal(err)
+			}
+			if res.Header.MessageType != req.MessageType+1 || res.Header.SequenceNumber != req.SequenceNumber {
+				t.Errorf("response type %d SEQ[%d], want type %d SEQ[%d]", res.Header.MessageType,
+					res.Header.SequenceNumber, req.MessageType+1, req.SequenceNumber)
+			}
+			if !reflect.DeepEqual(res.Body, tt.body) {
+				t.Errorf("response = %+v, want %+v", res.Body, tt.body)
+			}
+		})
+	}
+}
+
+// failingConn fails the first write of a response.
+type failingConn struct {
+	net.PacketConn
+	failed atomic.Bool
+}
+
+func (c *failingConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	if c.failed.CompareAndSwap(false, true) {
+		return 0, errors.New("write failed")
+	}
+	return c.PacketConn.WriteTo(b, addr)
+}
+
+func TestRejectRetransmissionAfterFailure(t *testing.T) {
+	network := memconn.NewNetwork()
+	upConn, err := network.Listen(testUPAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	up := NewPfcpServerConn(&failingConn{PacketConn: upConn})
+	up.NodeID = &NodeID{NodeIdType: NodeIdTypeIpv4Address, IP: testUPAddr.IP}
+	up.RejectMalformedRequests = true
+	readMessages(t, up)
+	conn, err := network.Listen(testCPAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	data := []byte{0x20, byte(PFCP_ASSOCIATION_RELEASE_REQUEST), 0, 4, 0, 0, 7, 0}
+	if _, err := conn.WriteTo(data, testUPAddr); err != nil {
+		t.Fatal(err)
+	}
+	// The retransmission is answered, the first rejection having failed
+	res := exchange(t, conn, testUPAddr, data)
+	if cause := res.Body.(PFCPAssociationReleaseResponse).Cause.CauseValue; cause != CauseMandatoryIeMissing {
+		t.Fatalf("cause = %d, want %d", cause, CauseMandatoryIeMissing)
+	}
+}
+
+func TestUnmatchedResponses(t *testing.T) {
+	network := memconn.NewNetwork()
+	cp := newTestServer(t, network, testCPAddr)
+	peer, err := network.Listen(testUPAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+	other, err := network.Listen(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 3).To4(), Port: PFCP_PORT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+
+	est := testEstablishmentRequest()
+	future := cp.SendRequestAsync(context.Background(), &PFCPMessage{
+		Header: Header{Version: PfcpVersion, MessageType: PFCP_SESSION_ESTABLISHMENT_REQUEST, S: SEID_PRESENT},
+		Body:   est,
+	}, testUPAddr, nil)
+	peer.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, PFCP_MAX_UDP_LEN)
+	n, _, err := peer.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req Header
+	if err := req.UnmarshalBinary(buf[:n]); err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := PFCPSessionEstablishmentResponse{
+		NodeID:  cp.NodeID,
+		Cause:   &Cause{CauseValue: CauseRequestAccepted},
+		UPFSEID: &FSEID{V4: true, Seid: 1, Ipv4Address: testUPAddr.IP},
+	}
+	for _, tc := range []struct {
+		name string
+		conn net.PacketConn
+		res  *PFCPMessage
+		err  error
+	}{
+		{
+			name: "unknown peer",
+			conn: other,
+			res: &PFCPMessage{
+				Header: Header{MessageType: PFCP_SESSION_ESTABLISHMENT_RESPONSE, S: SEID_PRESENT, SEID: est.CPFSEID.Seid},
+				Body:   accepted,
+			},
+			err: ErrNoTransaction,
+		},
+		{
+			name: "unknown sequence number",
+			conn: peer,
+			res: &PFCPMessage{
+				Header: Header{MessageType: PFCP_HEARTBEAT_RESPONSE, SequenceNumber: req.SequenceNumber + 1},
+				Body:   HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: time.Unix(1, 0)}},
+			},
+			err: ErrNoTransaction,
+		},
+		{
+			name: "unexpected type",
+			conn: peer,
+			res: &PFCPMessage{
+				Header: Header{MessageType: PFCP_HEARTBEAT_RESPONSE},
+				Body:   HeartbeatResponse{RecoveryTimeStamp: &RecoveryTimeStamp{RecoveryTimeStamp: time.Unix(1, 0)}},
+			},
+			err: ErrUnexpectedResponseType,
+		},
+		{
+			name: "unexpected SEID",
+			conn: peer,
+			res: &PFCPMessage{
+				Header: Header{MessageType: PFCP_SESSION_ESTABLISHMENT_RESPONSE, S: SEID_PRESENT, SEID: est.CPFSEID.Seid + 1},
+				Body:   accepted,
+			},
+			err: ErrUnexpectedSEID,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.res.Header.Version = PfcpVersion
+			if tc.res.Header.SequenceNumber == 0 {
+				tc.res.Header.SequenceNumber = req.SequenceNumber
+			}
+			data, err := tc.res.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tc.conn.WriteTo(data, testCPAddr); err != nil {
+				t.Fatal(err)
+			}
+			_, err = cp.ReadFrom()
+			var unmatched *UnmatchedResponseError
+			if !errors.As(err, &unmatched) || !errors.Is(err, tc.err) {
+				t.Fatalf("read error = %v, want an UnmatchedResponseError of %v", err, tc.err)
+			}
+			if unmatched.RemoteAddr.String() != tc.conn.LocalAddr().String() ||
+				unmatched.SequenceNumber != tc.res.Header.SequenceNumber {
+				t.Errorf("unmatched response from %s SEQ[%d], want from %s SEQ[%d]", unmatched.RemoteAddr,
+					unmatched.SequenceNumber, tc.conn.LocalAddr(), tc.res.Header.SequenceNumber)
+			}
+		})
+	}
+
+	// The request still waits for its response
+	select {
+	case <-future.Done():
+		t.Fatal("request ended by an unmatched response")
+	default:
+	}
+	res := &PFCPMessage{
+		Header: Header{Version: PfcpVersion, MessageType: PFCP_SESSION_ESTABLISHMENT_RESPONSE, S: SEID_PRESENT,
+			SEID: est.CPFSEID.Seid, SequenceNumber: req.SequenceNumber},
+		Body: accepted,
+	}
+	data, err := res.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peer.WriteTo(data, testCPAddr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cp.ReadFrom(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := future.Result(); err != nil {
+		t.Fatal(err)
+	}
+}